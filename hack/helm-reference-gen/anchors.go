@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// anchorLockHeader is written at the top of the anchor lockfile.
+const anchorLockHeader = `# Anchors of the keys in the generated Helm reference docs.
+# Links to these anchors are published so they must not be removed. If a key
+# is renamed or moved, add an @alias annotation with its previous path.
+# Regenerate with: make update-anchors
+`
+
+// AnchorReport is the difference between the anchors in the lockfile and the
+// anchors in the generated docs.
+type AnchorReport struct {
+	// Removed are anchors in the lockfile that the docs no longer have.
+	// Links to them are broken.
+	Removed []string
+
+	// Added are anchors in the docs that aren't in the lockfile yet. They
+	// don't break any links so they don't fail the check.
+	Added []string
+
+	// Duplicates are anchors that the docs have more than once, e.g. because
+	// an @alias is the path of another key.
+	Duplicates []string
+}
+
+// OK returns true if every anchor in the lockfile is still in the docs, i.e.
+// it's either still a key's anchor or it's kept by an @alias annotation.
+func (r AnchorReport) OK() bool {
+	return len(r.Removed) == 0 && len(r.Duplicates) == 0
+}
+
+// String formats the report with one anchor per line.
+func (r AnchorReport) String() string {
+	var b strings.Builder
+	if len(r.Removed) > 0 {
+		b.WriteString("Anchors removed from the docs, add an @alias annotation to keep them:\n")
+		for _, anchor := range r.Removed {
+			b.WriteString("  #" + anchor + "\n")
+		}
+	}
+	if len(r.Added) > 0 {
+		b.WriteString("Anchors not in the lockfile yet, add them with: make update-anchors\n")
+		for _, anchor := range r.Added {
+			b.WriteString("  #" + anchor + "\n")
+		}
+	}
+	if len(r.Duplicates) > 0 {
+		b.WriteString("Anchors used more than once:\n")
+		for _, anchor := range r.Duplicates {
+			b.WriteString("  #" + anchor + "\n")
+		}
+	}
+	return b.String()
+}
+
+// Anchors returns the sorted HTML anchors of every key in the tree rooted at
+// node, including the anchors for @alias annotations. Anchors that are used
+// more than once are only returned once.
+func Anchors(node DocNode) []string {
+	anchors, _ := collectAnchors(node)
+	return anchors
+}
+
+// collectAnchors returns the sorted anchors in the tree rooted at node and
+// the sorted anchors that are used more than once.
+func collectAnchors(node DocNode) (anchors []string, duplicates []string) {
+	counts := make(map[string]int)
+	var walk func(n DocNode)
+	walk = func(n DocNode) {
+		for _, child := range n.Children {
+			counts[child.Anchor()]++
+			for _, alias := range child.AliasAnchors() {
+				counts[alias]++
+			}
+			walk(child)
+		}
+	}
+	walk(node)
+
+	for anchor, count := range counts {
+		anchors = append(anchors, anchor)
+		if count > 1 {
+			duplicates = append(duplicates, anchor)
+		}
+	}
+	sort.Strings(anchors)
+	sort.Strings(duplicates)
+	return anchors, duplicates
+}
+
+// FormatAnchorLock returns the contents of the lockfile for anchors.
+func FormatAnchorLock(anchors []string) string {
+	return anchorLockHeader + strings.Join(anchors, "\n") + "\n"
+}
+
+// ParseAnchorLock returns the anchors in the lockfile contents. Blank lines
+// and comments are ignored.
+func ParseAnchorLock(contents string) []string {
+	var anchors []string
+	for _, line := range strings.Split(contents, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		anchors = append(anchors, line)
+	}
+	return anchors
+}
+
+// CheckAnchors compares the anchors in the lockfile contents against the
+// anchors in yamlStr.
+func CheckAnchors(yamlStr string, lockContents string) (AnchorReport, error) {
+	node, err := Parse(yamlStr)
+	if err != nil {
+		return AnchorReport{}, err
+	}
+	current, duplicates := collectAnchors(node)
+	locked := ParseAnchorLock(lockContents)
+
+	report := AnchorReport{
+		Removed:    difference(locked, current),
+		Added:      difference(current, locked),
+		Duplicates: duplicates,
+	}
+	return report, nil
+}
+
+// GenerateAnchorLock returns the contents of the lockfile for yamlStr.
+func GenerateAnchorLock(yamlStr string) (string, error) {
+	node, err := Parse(yamlStr)
+	if err != nil {
+		return "", err
+	}
+	anchors, duplicates := collectAnchors(node)
+	if len(duplicates) > 0 {
+		return "", fmt.Errorf("anchors used more than once: %s", strings.Join(duplicates, ", "))
+	}
+	return FormatAnchorLock(anchors), nil
+}
+
+// difference returns the elements of a that aren't in b.
+func difference(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, s := range b {
+		inB[s] = true
+	}
+	var out []string
+	for _, s := range a {
+		if !inB[s] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateAnchorLock(t *testing.T) {
+	lock, err := GenerateAnchorLock(`---
+global:
+  # Name docs
+  # @alias: name, global.fullname
+  name: consul
+`)
+	require.NoError(t, err)
+	require.Equal(t, anchorLockHeader+"v-global\nv-global-fullname\nv-global-name\nv-name\n", lock)
+	require.Equal(t, []string{"v-global", "v-global-fullname", "v-global-name", "v-name"}, ParseAnchorLock(lock))
+}
+
+func TestGenerateAnchorLock_Duplicates(t *testing.T) {
+	_, err := GenerateAnchorLock(`---
+global:
+  # @alias: global.image
+  name: consul
+  image: consul
+`)
+	require.EqualError(t, err, "anchors used more than once: v-global-image")
+}
+
+func TestCheckAnchors(t *testing.T) {
+	lock := FormatAnchorLock([]string{"v-global", "v-global-imageenvoy", "v-global-name"})
+	cases := map[string]struct {
+		Input string
+		Exp   AnchorReport
+	}{
+		"unchanged": {
+			Input: `---
+global:
+  name: consul
+  imageEnvoy: envoy
+`,
+			Exp: AnchorReport{},
+		},
+		"renamed without alias": {
+			Input: `---
+global:
+  name: consul
+  envoy:
+    image: envoy
+`,
+			Exp: AnchorReport{
+				Removed: []string{"v-global-imageenvoy"},
+				Added:   []string{"v-global-envoy", "v-global-envoy-image"},
+			},
+		},
+		"renamed with alias": {
+			Input: `---
+global:
+  name: consul
+  envoy:
+    # @alias: global.imageEnvoy
+    image: envoy
+`,
+			Exp: AnchorReport{
+				Added: []string{"v-global-envoy", "v-global-envoy-image"},
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			report, err := CheckAnchors(c.Input, lock)
+			require.NoError(t, err)
+			require.Equal(t, c.Exp, report)
+			require.Equal(t, len(c.Exp.Removed) == 0, report.OK())
+		})
+	}
+}
+
+func TestAnchorReport_String(t *testing.T) {
+	report := AnchorReport{
+		Removed:    []string{"v-global-imageenvoy"},
+		Added:      []string{"v-global-envoy-image"},
+		Duplicates: []string{"v-global-image"},
+	}
+	require.Equal(t, `Anchors removed from the docs, add an @alias annotation to keep them:
+  #v-global-imageenvoy
+Anchors not in the lockfile yet, add them with: make update-anchors
+  #v-global-envoy-image
+Anchors used more than once:
+  #v-global-image
+`, report.String())
+}
+
+// Test that the anchors in anchors.lock are all in the docs. If this fails,
+// run `make update-anchors`.
+func TestAnchorsUpToDate(t *testing.T) {
+	values, err := ioutil.ReadFile(filepath.Join("..", "..", "values.yaml"))
+	require.NoError(t, err)
+	lock, err := ioutil.ReadFile("anchors.lock")
+	require.NoError(t, err)
+	report, err := CheckAnchors(string(values), string(lock))
+	require.NoError(t, err)
+	require.True(t, report.OK(), report.String())
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"text/tabwriter"
+)
+
+// CoverageCounts counts how many leaf keys are documented.
+type CoverageCounts struct {
+	// Keys is the number of leaf keys, i.e. keys without documented
+	// sub-keys.
+	Keys int `json:"keys"`
+
+	// Described is the number of keys with a description.
+	Described int `json:"described"`
+
+	// Typed is the number of keys whose type is shown in the docs. Maps
+	// without @type aren't.
+	Typed int `json:"typed"`
+
+	// Defaulted is the number of keys whose default is shown in the docs.
+	// Maps, arrays of maps and multi-line values need @default for that.
+	// Required keys don't need a default so they count too.
+	Defaulted int `json:"defaulted"`
+
+	// Complete is the number of keys that are described, typed and
+	// defaulted.
+	Complete int `json:"complete"`
+
+	// Coverage is the percentage of keys that are complete.
+	Coverage float64 `json:"coverage"`
+}
+
+// add counts the leaf key n.
+func (c *CoverageCounts) add(n DocNode) {
+	described := n.PlainDocumentation() != ""
+	typed := n.FormattedKind() != ""
+	defaulted := n.FormattedDefault() != "" || n.Required
+
+	c.Keys++
+	if described {
+		c.Described++
+	}
+	if typed {
+		c.Typed++
+	}
+	if defaulted {
+		c.Defaulted++
+	}
+	if described && typed && defaulted {
+		c.Complete++
+	}
+	c.Coverage = percent(c.Complete, c.Keys)
+}
+
+// SectionCoverage is the coverage of the keys under a top-level key.
+type SectionCoverage struct {
+	Section string `json:"section"`
+	CoverageCounts
+}
+
+// CoverageReport is how much of values.yaml is documented, per top-level key
+// and in total.
+type CoverageReport struct {
+	Sections []SectionCoverage `json:"sections"`
+	Total    CoverageCounts    `json:"total"`
+}
+
+// Table formats the report as a table with a row per section.
+func (r CoverageReport) Table() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SECTION\tKEYS\tDESCRIBED\tTYPED\tDEFAULTED\tCOVERAGE")
+	row := func(name string, c CoverageCounts) {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%.1f%%\n", name, c.Keys, c.Described, c.Typed, c.Defaulted, c.Coverage)
+	}
+	for _, s := range r.Sections {
+		row(s.Section, s.CoverageCounts)
+	}
+	row("total", r.Total)
+	w.Flush()
+	return b.String()
+}
+
+// JSON formats the report as indented JSON.
+func (r CoverageReport) JSON() (string, error) {
+	// Output an empty list rather than null when there are no sections.
+	if r.Sections == nil {
+		r.Sections = []SectionCoverage{}
+	}
+	out, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out) + "\n", nil
+}
+
+// Coverage parses yamlStr and counts how many of its leaf keys are
+// documented, per top-level key in the order they're in the file. The fields
+// of @items count as leaf keys.
+func Coverage(yamlStr string) (CoverageReport, error) {
+	node, err := Parse(yamlStr)
+	if err != nil {
+		return CoverageReport{}, err
+	}
+
+	var report CoverageReport
+	for _, section := range node.Children {
+		s := SectionCoverage{Section: section.Key}
+		var walk func(n DocNode)
+		walk = func(n DocNode) {
+			if len(n.Children) == 0 {
+				s.add(n)
+				report.Total.add(n)
+				return
+			}
+			for _, child := range n.Children {
+				walk(child)
+			}
+		}
+		walk(section)
+		report.Sections = append(report.Sections, s)
+	}
+	// Set the total's coverage in case there are no keys to count.
+	report.Total.Coverage = percent(report.Total.Complete, report.Total.Keys)
+	return report, nil
+}
+
+// percent returns n as a percentage of total rounded to one decimal place.
+// If there's nothing to count then everything is covered.
+func percent(n int, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return math.Round(float64(n)/float64(total)*1000) / 10
+}
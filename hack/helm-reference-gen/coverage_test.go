@@ -0,0 +1,77 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoverage(t *testing.T) {
+	report, err := Coverage(`---
+global:
+  # Name docs.
+  name: consul
+  # @type: string
+  domain: null
+  tls:
+    # Enabled docs.
+    enabled: false
+    # CA docs.
+    caCert:
+      secretName: null
+server:
+  # Resources docs.
+  # @type: map
+  resources:
+    requests:
+      memory: 100Mi
+  # Volumes docs.
+  # @type: array<map>
+  # @items:
+  #   # Name docs.
+  #   # @type: string
+  #   # @required: true
+  #   name: null
+  extraVolumes: []
+  # Annotations docs.
+  annotations: {}
+`)
+	require.NoError(t, err)
+	// server.resources has sub-keys so server.resources.requests.memory is
+	// counted instead, and it has no description.
+	require.Equal(t, CoverageReport{
+		Sections: []SectionCoverage{
+			{
+				Section:        "global",
+				CoverageCounts: CoverageCounts{Keys: 4, Described: 2, Typed: 4, Defaulted: 4, Complete: 2, Coverage: 50},
+			},
+			{
+				Section:        "server",
+				CoverageCounts: CoverageCounts{Keys: 3, Described: 2, Typed: 2, Defaulted: 3, Complete: 1, Coverage: 33.3},
+			},
+		},
+		Total: CoverageCounts{Keys: 7, Described: 4, Typed: 6, Defaulted: 7, Complete: 3, Coverage: 42.9},
+	}, report)
+
+	require.Equal(t, `SECTION  KEYS  DESCRIBED  TYPED  DEFAULTED  COVERAGE
+global   4     2          4      4          50.0%
+server   3     2          2      3          33.3%
+total    7     4          6      7          42.9%
+`, report.Table())
+
+	out, err := report.JSON()
+	require.NoError(t, err)
+	require.Contains(t, out, `"section": "global",
+      "keys": 4,`)
+	require.Contains(t, out, `"total": {
+    "keys": 7,`)
+}
+
+func TestCoverage_Empty(t *testing.T) {
+	report, err := Coverage("---\n")
+	require.NoError(t, err)
+	require.Equal(t, 100.0, report.Total.Coverage)
+	out, err := report.JSON()
+	require.NoError(t, err)
+	require.Contains(t, out, `"sections": []`)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultYAML returns n as block YAML without comments. Aliases are replaced
+// by the values they refer to and merge keys are expanded so the result
+// stands on its own.
+func defaultYAML(n *yaml.Node) (string, error) {
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(stripComments(n)); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(out.String(), "\n"), nil
+}
+
+// stripComments returns a deep copy of n without comments, anchors, aliases
+// or merge keys.
+func stripComments(n *yaml.Node) *yaml.Node {
+	n = resolveAlias(n)
+	cp := *n
+	cp.HeadComment, cp.LineComment, cp.FootComment = "", "", ""
+	cp.Anchor = ""
+	content := n.Content
+	if n.Kind == yaml.MappingNode {
+		content = expandMerges(content)
+	}
+	cp.Content = make([]*yaml.Node, len(content))
+	for i, c := range content {
+		cp.Content[i] = stripComments(c)
+	}
+	return &cp
+}
+
+// ExpandedDefault returns the default value of this node as YAML if it's too
+// big for FormattedDefault, i.e. it's a map, a list of maps or a multi-line
+// string. Maps with sub-keys don't have one since their sub-keys show their
+// own defaults. It's empty if the default is already shown.
+func (n DocNode) ExpandedDefault() string {
+	if n.FormattedKind() == "" || n.FormattedDefault() != "" {
+		return ""
+	}
+	if defaultAnnotation.MatchString(n.Comment) || (n.Required && n.KindTag == "!!null") {
+		return ""
+	}
+	if len(n.Children) > 0 && n.FormattedKind() != "array<map>" {
+		return ""
+	}
+	if n.DefaultYAML != "" {
+		return n.DefaultYAML
+	}
+	return strings.Trim(n.Default, "\n")
+}
+
+// FormattedDefaultBlock returns ExpandedDefault as a fenced YAML block,
+// indented to line up with the documentation. If collapsible is true, the
+// block is wrapped in <details> so it's hidden until clicked. Otherwise it's
+// preceded by a "Default value:" line.
+func (n DocNode) FormattedDefaultBlock(collapsible bool) string {
+	def := n.ExpandedDefault()
+	if def == "" {
+		return ""
+	}
+	lines := []string{"```yaml", def, "```"}
+	if collapsible {
+		lines = append([]string{"<details>", "<summary>Default value</summary>", ""}, lines...)
+		lines = append(lines, "", "</details>")
+	} else {
+		lines = append([]string{"Default value:", ""}, lines...)
+	}
+	var indented []string
+	for _, line := range strings.Split(strings.Join(lines, "\n"), "\n") {
+		if line != "" {
+			line = n.docIndent() + line
+		}
+		indented = append(indented, line)
+	}
+	return strings.Join(indented, "\n")
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandedDefault(t *testing.T) {
+	input := `---
+base: &base
+  a: 1
+# @type: map
+labels:
+  app: consul
+# @type: array<map>
+volumes:
+  # Comment that's stripped.
+  - name: data # Line comment.
+    <<: *base
+# @type: string
+affinity: |
+  podAntiAffinity:
+    enabled: true
+# @type: array<map>
+# @default: []
+annotated:
+  - a: 1
+short: value
+# @type: array<map>
+empty: []
+parent:
+  child: true
+`
+	node, err := Parse(input)
+	require.NoError(t, err)
+	nodes := node.Flatten()
+
+	cases := map[string]string{
+		"volumes":   "- name: data\n  a: 1",
+		"affinity":  "podAntiAffinity:\n  enabled: true",
+		"annotated": "",
+		"short":     "",
+		"empty":     "[]",
+		"base":      "",
+		"base.a":    "",
+		// Maps with sub-keys show the defaults of the sub-keys instead.
+		"labels": "",
+		"parent": "",
+	}
+	for path, exp := range cases {
+		t.Run(path, func(t *testing.T) {
+			require.Equal(t, exp, nodes[path].ExpandedDefault())
+		})
+	}
+}
+
+func TestFormattedDefaultBlock(t *testing.T) {
+	input := `---
+parent:
+  # @type: map
+  labels: {}
+`
+	node, err := Parse(input)
+	require.NoError(t, err)
+	labels := node.Children[0].Children[0]
+
+	require.Equal(t, strings.Replace(`    <details>
+    <summary>Default value</summary>
+
+    $$$yaml
+    {}
+    $$$
+
+    </details>`, "$", "`", -1), labels.FormattedDefaultBlock(true))
+	require.Equal(t, strings.Replace(`    Default value:
+
+    $$$yaml
+    {}
+    $$$`, "$", "`", -1), labels.FormattedDefaultBlock(false))
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// ChangeKind is the kind of change made to a key between two values.yaml
+// revisions.
+type ChangeKind string
+
+const (
+	KeyAdded       ChangeKind = "added"
+	KeyRemoved     ChangeKind = "removed"
+	KeyRenamed     ChangeKind = "renamed"
+	DefaultChanged ChangeKind = "default-changed"
+	TypeChanged    ChangeKind = "type-changed"
+	KeyDeprecated  ChangeKind = "deprecated"
+)
+
+// ValuesChange is a single change to a key between two values.yaml revisions.
+type ValuesChange struct {
+	Kind ChangeKind `json:"kind"`
+
+	// Key is the dotted path to the key in the old revision, or in the new
+	// revision if the key was added.
+	Key string `json:"key"`
+
+	// NewKey is the dotted path to the key in the new revision if it was
+	// renamed.
+	NewKey string `json:"newKey,omitempty"`
+
+	// Old and New are the old and new type or default for type and default
+	// changes, and the type and default of added keys.
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+
+	// Breaking is true if users upgrading may need to change their values.
+	Breaking bool `json:"breaking"`
+}
+
+// String formats the change as a CHANGELOG.md style sentence.
+func (c ValuesChange) String() string {
+	switch c.Kind {
+	case KeyAdded:
+		if c.New != "" {
+			return fmt.Sprintf("Added `%s` (`%s`).", c.Key, c.New)
+		}
+		return fmt.Sprintf("Added `%s`.", c.Key)
+	case KeyRemoved:
+		return fmt.Sprintf("Removed `%s`.", c.Key)
+	case KeyRenamed:
+		return fmt.Sprintf("Renamed `%s` to `%s`.", c.Key, c.NewKey)
+	case DefaultChanged:
+		return fmt.Sprintf("Changed the default of `%s` from `%s` to `%s`.", c.Key, c.Old, c.New)
+	case TypeChanged:
+		return fmt.Sprintf("Changed the type of `%s` from `%s` to `%s`.", c.Key, c.Old, c.New)
+	case KeyDeprecated:
+		if c.NewKey != "" {
+			return fmt.Sprintf("Deprecated `%s`, use `%s` instead.", c.Key, c.NewKey)
+		}
+		return fmt.Sprintf("Deprecated `%s`.", c.Key)
+	}
+	return ""
+}
+
+// ValuesDiff is the set of changes between two values.yaml revisions.
+type ValuesDiff struct {
+	Changes []ValuesChange `json:"changes"`
+}
+
+// HasBreaking returns true if any of the changes are breaking.
+func (d ValuesDiff) HasBreaking() bool {
+	for _, c := range d.Changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// Markdown formats the diff as CHANGELOG.md style sections.
+func (d ValuesDiff) Markdown() string {
+	var breaking, nonBreaking []string
+	for _, c := range d.Changes {
+		if c.Breaking {
+			breaking = append(breaking, "* "+c.String())
+		} else {
+			nonBreaking = append(nonBreaking, "* "+c.String())
+		}
+	}
+
+	var sections []string
+	if len(breaking) > 0 {
+		sections = append(sections, "BREAKING CHANGES:\n"+strings.Join(breaking, "\n"))
+	}
+	if len(nonBreaking) > 0 {
+		sections = append(sections, "CHANGES:\n"+strings.Join(nonBreaking, "\n"))
+	}
+	if len(sections) == 0 {
+		return "No changes.\n"
+	}
+	return strings.Join(sections, "\n\n") + "\n"
+}
+
+// JSON formats the diff as indented JSON.
+func (d ValuesDiff) JSON() (string, error) {
+	// Output an empty list rather than null when there are no changes.
+	if d.Changes == nil {
+		d.Changes = []ValuesChange{}
+	}
+	out, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out) + "\n", nil
+}
+
+// DiffValues parses both revisions of values.yaml and returns the changes
+// from oldYamlStr to newYamlStr.
+//
+// Removed keys and type changes are breaking. Added keys, default changes and
+// deprecations are not, since existing values will continue to work. A removed key is
+// reported as renamed if a key with the same kind and the same documentation
+// was added. Keys with the same name aren't enough, e.g. server.affinity and
+// client.affinity are unrelated.
+func DiffValues(oldYamlStr string, newYamlStr string) (ValuesDiff, error) {
+	oldNode, err := Parse(oldYamlStr)
+	if err != nil {
+		return ValuesDiff{}, fmt.Errorf("parsing old values: %s", err)
+	}
+	newNode, err := Parse(newYamlStr)
+	if err != nil {
+		return ValuesDiff{}, fmt.Errorf("parsing new values: %s", err)
+	}
+	oldNodes := oldNode.Flatten()
+	newNodes := newNode.Flatten()
+
+	// Only report the top-most key that was added or removed, e.g. if a map
+	// was removed then we don't also report each of its keys.
+	var removed, added []string
+	for key := range oldNodes {
+		if _, ok := newNodes[key]; !ok && !hasParentIn(key, oldNodes, newNodes) {
+			removed = append(removed, key)
+		}
+	}
+	for key := range newNodes {
+		if _, ok := oldNodes[key]; !ok && !hasParentIn(key, newNodes, oldNodes) {
+			added = append(added, key)
+		}
+	}
+	sort.Strings(removed)
+	sort.Strings(added)
+
+	var diff ValuesDiff
+	renamedTo := make(map[string]bool)
+	for _, key := range removed {
+		if newKey := findRename(oldNodes[key], added, newNodes, renamedTo); newKey != "" {
+			renamedTo[newKey] = true
+			diff.Changes = append(diff.Changes, ValuesChange{Kind: KeyRenamed, Key: key, NewKey: newKey, Breaking: true})
+			continue
+		}
+		diff.Changes = append(diff.Changes, ValuesChange{Kind: KeyRemoved, Key: key, Breaking: true})
+	}
+	for _, key := range added {
+		if renamedTo[key] {
+			continue
+		}
+		diff.Changes = append(diff.Changes, ValuesChange{Kind: KeyAdded, Key: key, New: kindAndDefault(newNodes[key])})
+	}
+
+	var keys []string
+	for key := range oldNodes {
+		if _, ok := newNodes[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		oldN, newN := oldNodes[key], newNodes[key]
+		oldKind, newKind := comparableKind(oldN), comparableKind(newN)
+		if oldKind != newKind {
+			diff.Changes = append(diff.Changes, ValuesChange{
+				Kind:     TypeChanged,
+				Key:      key,
+				Old:      oldKind,
+				New:      newKind,
+				Breaking: true,
+			})
+			continue
+		}
+		if newN.Deprecated && !oldN.Deprecated {
+			diff.Changes = append(diff.Changes, ValuesChange{
+				Kind:   KeyDeprecated,
+				Key:    key,
+				NewKey: newN.ReplacedBy,
+			})
+		}
+		// Maps don't have a default, their keys do.
+		if newKind != "map" && oldN.FormattedDefault() != newN.FormattedDefault() {
+			diff.Changes = append(diff.Changes, ValuesChange{
+				Kind: DefaultChanged,
+				Key:  key,
+				Old:  oldN.FormattedDefault(),
+				New:  newN.FormattedDefault(),
+			})
+		}
+	}
+	return diff, nil
+}
+
+// hasParentIn returns true if a parent of key is in nodes but not in other,
+// i.e. the parent itself was added or removed. It also returns true if the
+// parent is in other but its keys aren't documented there, e.g. because it's
+// annotated with @recurse: false, since its keys weren't actually changed.
+func hasParentIn(key string, nodes map[string]DocNode, other map[string]DocNode) bool {
+	parts := strings.Split(key, ".")
+	for i := len(parts) - 1; i > 0; i-- {
+		parent := strings.Join(parts[:i], ".")
+		_, inNodes := nodes[parent]
+		otherParent, inOther := other[parent]
+		if inNodes && (!inOther || len(otherParent.Children) == 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// comparableKind returns the kind of n normalized so that equivalent kinds
+// compare equal, e.g. maps without an @type annotation and "int".
+func comparableKind(n DocNode) string {
+	kind := n.FormattedKind()
+	switch {
+	case kind == "" && strings.TrimLeft(n.KindTag, "!") == "map":
+		return "map"
+	case kind == "int":
+		return "integer"
+	}
+	return kind
+}
+
+// findRename returns the added key that n was most likely renamed to, or an
+// empty string if there isn't one. Keys in taken have already been matched.
+// Undocumented keys are never matched since there's nothing to match on.
+func findRename(n DocNode, added []string, newNodes map[string]DocNode, taken map[string]bool) string {
+	doc := n.PlainDocumentation()
+	if doc == "" {
+		return ""
+	}
+	for _, key := range added {
+		if taken[key] {
+			continue
+		}
+		candidate := newNodes[key]
+		if comparableKind(candidate) != comparableKind(n) {
+			continue
+		}
+		if candidate.PlainDocumentation() == doc {
+			return key
+		}
+	}
+	return ""
+}
+
+// kindAndDefault formats the kind and default of n the same way as the
+// generated docs, e.g. "string: consul".
+func kindAndDefault(n DocNode) string {
+	kind := n.FormattedKind()
+	if kind == "" {
+		return ""
+	}
+	if def := n.FormattedDefault(); def != "" {
+		return kind + ": " + def
+	}
+	return kind
+}
+
+// readValuesRevision returns the contents of values.yaml at rev. rev is
+// either a path to a values file or a git ref, in which case the file at
+// valuesPath in the repo at repoPath is read at that ref.
+func readValuesRevision(rev string, repoPath string, valuesPath string) (string, error) {
+	if _, err := os.Stat(rev); err == nil {
+		contents, err := ioutil.ReadFile(rev)
+		return string(contents), err
+	}
+	cmd := exec.Command("git", "show", fmt.Sprintf("%s:%s", rev, valuesPath))
+	cmd.Dir = repoPath
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%q is not a file and could not be read as a git ref: %s", rev, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
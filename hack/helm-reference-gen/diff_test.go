@@ -0,0 +1,217 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffValues(t *testing.T) {
+	cases := map[string]struct {
+		Old string
+		New string
+		Exp []ValuesChange
+	}{
+		"no changes": {
+			Old: `---
+# docs
+key: value
+`,
+			New: `---
+# docs
+key: value
+`,
+			Exp: nil,
+		},
+		"added key": {
+			Old: `---
+key: value
+`,
+			New: `---
+key: value
+replicas: 3
+`,
+			Exp: []ValuesChange{{Kind: KeyAdded, Key: "replicas", New: "integer: 3"}},
+		},
+		"removed map only reports map": {
+			Old: `---
+key: value
+tls:
+  enabled: false
+  caCert: ""
+`,
+			New: `---
+key: value
+`,
+			Exp: []ValuesChange{{Kind: KeyRemoved, Key: "tls", Breaking: true}},
+		},
+		"moved key with same docs": {
+			Old: `---
+global:
+  name: consul
+connectInject:
+  enabled: false
+  # The Envoy image.
+  # @type: string
+  imageEnvoy: null
+`,
+			New: `---
+global:
+  name: consul
+  # The Envoy image.
+  # @type: string
+  imageEnvoy: null
+connectInject:
+  enabled: false
+`,
+			Exp: []ValuesChange{{Kind: KeyRenamed, Key: "connectInject.imageEnvoy", NewKey: "global.imageEnvoy", Breaking: true}},
+		},
+		"key with same name but different docs isn't renamed": {
+			Old: `---
+server:
+  # Affinity of the server pods.
+  # @type: string
+  affinity: null
+client:
+  enabled: true
+`,
+			New: `---
+server:
+  enabled: true
+client:
+  enabled: true
+  # Affinity of the client pods.
+  # @type: string
+  affinity: null
+`,
+			Exp: []ValuesChange{
+				{Kind: KeyRemoved, Key: "server.affinity", Breaking: true},
+				{Kind: KeyAdded, Key: "client.affinity", New: "string: null"},
+				{Kind: KeyAdded, Key: "server.enabled", New: "boolean: true"},
+			},
+		},
+		"undocumented key with same name isn't renamed": {
+			Old: `---
+global:
+  name: consul
+connectInject:
+  enabled: false
+  # @type: string
+  imageEnvoy: null
+`,
+			New: `---
+global:
+  name: consul
+  # @type: string
+  imageEnvoy: null
+connectInject:
+  enabled: false
+`,
+			Exp: []ValuesChange{
+				{Kind: KeyRemoved, Key: "connectInject.imageEnvoy", Breaking: true},
+				{Kind: KeyAdded, Key: "global.imageEnvoy", New: "string: null"},
+			},
+		},
+		"renamed key with same docs": {
+			Old: `---
+# If true, bootstrap ACLs.
+bootstrapACLs: false
+`,
+			New: `---
+# If true, bootstrap ACLs.
+manageSystemACLs: false
+`,
+			Exp: []ValuesChange{{Kind: KeyRenamed, Key: "bootstrapACLs", NewKey: "manageSystemACLs", Breaking: true}},
+		},
+		"default changed": {
+			Old: `---
+logLevel: info
+`,
+			New: `---
+logLevel: debug
+`,
+			Exp: []ValuesChange{{Kind: DefaultChanged, Key: "logLevel", Old: "info", New: "debug"}},
+		},
+		"type changed": {
+			Old: `---
+# @type: string
+resources: null
+`,
+			New: `---
+# @type: map
+resources: null
+`,
+			Exp: []ValuesChange{{Kind: TypeChanged, Key: "resources", Old: "string", New: "map", Breaking: true}},
+		},
+		"equivalent types": {
+			Old: `---
+# @type: int
+replicas: 3
+server:
+  enabled: true
+`,
+			New: `---
+# @type: integer
+replicas: 3
+# @type: map
+server:
+  enabled: true
+`,
+			Exp: nil,
+		},
+		"deprecated key": {
+			Old: `---
+# docs
+port: 8500
+`,
+			New: `---
+# docs
+# @deprecated: server.port
+port: 8500
+`,
+			Exp: []ValuesChange{{Kind: KeyDeprecated, Key: "port", NewKey: "server.port"}},
+		},
+		"keys no longer documented aren't removed": {
+			Old: `---
+resources:
+  requests:
+    cpu: 100m
+`,
+			New: `---
+# @type: map
+# @recurse: false
+resources:
+  requests:
+    cpu: 100m
+`,
+			Exp: nil,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			diff, err := DiffValues(c.Old, c.New)
+			require.NoError(t, err)
+			require.Equal(t, c.Exp, diff.Changes)
+		})
+	}
+}
+
+func TestValuesDiff_Markdown(t *testing.T) {
+	diff := ValuesDiff{
+		Changes: []ValuesChange{
+			{Kind: KeyRemoved, Key: "meshGateway.globalMode", Breaking: true},
+			{Kind: KeyRenamed, Key: "global.lifecycleSidecarContainer", NewKey: "global.consulSidecarContainer", Breaking: true},
+			{Kind: KeyAdded, Key: "global.logLevel", New: "string: info"},
+			{Kind: DefaultChanged, Key: "connectInject.logLevel", Old: "info", New: `""`},
+		},
+	}
+	require.Equal(t, "BREAKING CHANGES:\n"+
+		"* Removed `meshGateway.globalMode`.\n"+
+		"* Renamed `global.lifecycleSidecarContainer` to `global.consulSidecarContainer`.\n"+
+		"\n"+
+		"CHANGES:\n"+
+		"* Added `global.logLevel` (`string: info`).\n"+
+		"* Changed the default of `connectInject.logLevel` from `info` to `\"\"`.\n", diff.Markdown())
+	require.Equal(t, "No changes.\n", ValuesDiff{}.Markdown())
+}
@@ -3,11 +3,34 @@ package main
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
 const UnknownKindError = "unknown kind"
 
+// enterpriseMarker is the text that marks a key as enterprise only when it's
+// at the start of its documentation. It's an alternative to the @enterprise
+// annotation.
+const enterpriseMarker = "[Enterprise Only]"
+
+// keyReference matches a @default that refers to another key, e.g.
+// "global.enabled", rather than being a literal value.
+var keyReference = regexp.MustCompile(`^[a-zA-Z]\w*(\.[a-zA-Z]\w*)+$`)
+
+// typeTags maps the types used in @type annotations to the YAML kind tags
+// that are allowed for them. Types that aren't listed, e.g. custom types
+// like "string or integer", aren't checked.
+var typeTags = map[string]string{
+	"string":  "!!str",
+	"integer": "!!int",
+	"int":     "!!int",
+	"boolean": "!!bool",
+	"map":     "!!map",
+}
+
 // DocNode is a node in the final generated reference document.
 // For example this would be a single DocNode:
 // ```
@@ -20,11 +43,19 @@ type DocNode struct {
 	// shouldn't be indented.
 	Column int
 
+	// Line is the line in the YAML file that this node's key is on.
+	Line int
+
 	// ParentBreadcrumb is the path to this node's parent from the root.
 	// It is used for the HTML anchor, e.g. `#v-global-name`.
 	// If this node were global.name, then this would be set to "global".
 	ParentBreadcrumb string
 
+	// ParentPath is the dotted path to this node's parent from the root.
+	// If this node were global.tls.enabled, then this would be set to
+	// "global.tls".
+	ParentPath string
+
 	// ParentWasMap is true when the parent of this node was a map.
 	ParentWasMap bool
 
@@ -35,15 +66,131 @@ type DocNode struct {
 	// Default would be "false".
 	Default string
 
-	// Comment is the YAML comment that described this node.
+	// DefaultYAML is the default value of a map or list re-serialized as
+	// block YAML without comments. It's used to show defaults that are too
+	// big for FormattedDefault.
+	DefaultYAML string
+
+	// Comment is the YAML comment that described this node. It's the comment
+	// above the key, which is where annotations go.
 	Comment string
 
+	// LineComment is the YAML comment on the same line as the key, e.g.
+	// `key: value # comment`.
+	LineComment string
+
+	// FootComment is the YAML comment below the value that's separated from
+	// the next key by a blank line.
+	FootComment string
+
+	// YAMLAnchor is the name of the YAML anchor on this node's value, e.g.
+	// "base" for `key: &base value`.
+	YAMLAnchor string
+
+	// YAMLAlias is the name of the YAML anchor that this node's value is an
+	// alias of, e.g. "base" for `key: *base`. The node is documented using
+	// the value of the anchor.
+	YAMLAlias string
+
+	// AliasOf is the path of the key with the YAML anchor that YAMLAlias
+	// refers to. It's empty if the anchor isn't on a key's value.
+	AliasOf string
+
+	// AliasIsElement is true if YAMLAlias is an element of this node's list
+	// value, e.g. "base" for `key: [*base]`, rather than the value itself.
+	AliasIsElement bool
+
 	// KindTag is the YAML parsed kind tag from the YAML library. This has values
 	// like "!!seq" and "!!str".
 	KindTag string
 
 	// Children are other nodes that should be displayed as sub-keys of this node.
 	Children []DocNode
+
+	// Aliases are the previous paths of this key from the @alias annotation,
+	// e.g. "connectInject.imageEnvoy". Anchors are emitted for them so that
+	// links to the old paths keep working.
+	Aliases []string
+
+	// Required is true if the key must be set, from the @required
+	// annotation. It's used for the fields of @items.
+	Required bool
+
+	// ItemsLine is the line of the @items annotation in the YAML file if this
+	// node has one. Its children are then the fields of each element.
+	ItemsLine int
+
+	// ElementKindTags are the YAML kind tags of each element if this node is
+	// a sequence, e.g. ["!!str", "!!str"] for `key: [a, b]`.
+	ElementKindTags []string
+
+	// Enum is the list of allowed values from the @enum annotation.
+	Enum []string
+
+	// Deprecated is true if the key is deprecated via the @deprecated
+	// annotation.
+	Deprecated bool
+
+	// ReplacedBy is the key that replaces this key if it's deprecated, from
+	// the @deprecated annotation.
+	ReplacedBy string
+
+	// Since is the chart version the key was added in, from the @since
+	// annotation.
+	Since string
+
+	// Enterprise is true if the key only applies to Consul Enterprise, either
+	// via the @enterprise annotation or the [Enterprise Only] marker.
+	Enterprise bool
+
+	// Profiles are the values of the key in each profile it's part of, keyed
+	// by the profile's name, from the @profile annotations. The values are
+	// YAML, e.g. "3" or "true".
+	Profiles map[string]string
+
+	// HeadComment is the comment at the top of the documents that's
+	// separated from the first key by a blank line, e.g. with the
+	// @defineProfile annotations. It's only set on the root node.
+	HeadComment string
+}
+
+// parseAnnotations sets the fields of n that come from annotations in its
+// comment.
+func (n *DocNode) parseAnnotations() {
+	if match := lastAnnotation(enumAnnotation, n.Comment); match != "" {
+		n.Enum = nil
+		for _, v := range strings.Split(match, ",") {
+			n.Enum = append(n.Enum, strings.Trim(strings.TrimSpace(v), `"'`))
+		}
+	}
+	if match := lastAnnotation(deprecatedAnnotation, n.Comment); match != "" && match != "false" {
+		n.Deprecated = true
+		if match != "true" {
+			n.ReplacedBy = match
+		}
+	}
+	if match := lastAnnotation(aliasAnnotation, n.Comment); match != "" {
+		n.Aliases = nil
+		for _, v := range strings.Split(match, ",") {
+			n.Aliases = append(n.Aliases, strings.TrimSpace(v))
+		}
+	}
+	// Malformed @profile annotations are reported by Validate.
+	n.Profiles, _ = parseProfiles(n.Comment)
+	n.Required = lastAnnotation(requiredAnnotation, n.Comment) == "true"
+	n.Since = lastAnnotation(sinceAnnotation, n.Comment)
+	n.Enterprise = lastAnnotation(enterpriseAnnotation, n.Comment) == "true" ||
+		strings.HasPrefix(commentPrefix.ReplaceAllString(n.Comment, ""), enterpriseMarker)
+}
+
+// lastAnnotation returns the value of the last match of annotation in
+// comment or an empty string if it doesn't match.
+func lastAnnotation(annotation *regexp.Regexp, comment string) string {
+	match := annotation.FindAllStringSubmatch(comment, -1)
+	if len(match) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(match[len(match)-1][1])
 }
 
 // Validate returns an error if this node is invalid, else nil.
@@ -52,14 +199,200 @@ func (n DocNode) Validate() error {
 	if strings.Contains(kind, UnknownKindError) {
 		return errors.New(kind)
 	}
+
+	// The YAML default must be one of the allowed values. We don't check null
+	// and "-" defaults since they mean the value is unset or inherited.
+	if len(n.Enum) > 0 && n.KindTag != "!!null" && n.Default != "-" {
+		found := false
+		for _, v := range n.Enum {
+			if v == n.Default {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("default %q is not one of the @enum values: %s", n.Default, strings.Join(n.Enum, ", "))
+		}
+	}
+
+	if match := lastAnnotation(typeAnnotation, n.Comment); match != "" {
+		if err := n.validateType(match); err != nil {
+			return err
+		}
+	}
+	if match := lastAnnotation(defaultAnnotation, n.Comment); match != "" {
+		if err := n.validateDefault(kind, match); err != nil {
+			return err
+		}
+	}
+	return n.validateProfiles()
+}
+
+// validateType returns an error if the YAML value of this node isn't of
+// type typ from its @type annotation.
+func (n DocNode) validateType(typ string) error {
+	// A null value is valid for any type since it means the key is unset.
+	// Nodes with @recurse: false don't have a kind tag so we can't check them.
+	if n.KindTag == "!!null" || n.KindTag == "" {
+		return nil
+	}
+
+	if elemType, ok := arrayElementType(typ); ok {
+		if n.KindTag != "!!seq" {
+			return fmt.Errorf("@type is %s but the default is %s", typ, tagName(n.KindTag))
+		}
+		return checkElementTags(typ, elemType, n.ElementKindTags)
+	}
+
+	tag, ok := typeTags[typ]
+	if !ok {
+		return nil
+	}
+	// "-" is used for scalars that inherit their value from another key,
+	// e.g. global.enabled.
+	if n.KindTag == "!!str" && n.Default == "-" && typ != "map" {
+		return nil
+	}
+	if n.KindTag != tag {
+		if n.KindTag == "!!map" || n.KindTag == "!!seq" {
+			return fmt.Errorf("@type is %s but the default is %s", typ, tagName(n.KindTag))
+		}
+		return fmt.Errorf("@type is %s but the default %q is %s", typ, n.Default, tagName(n.KindTag))
+	}
+	return nil
+}
+
+// validateDefault returns an error if the @default annotation def can't be
+// parsed as kind or if it's a literal that disagrees with the YAML value.
+func (n DocNode) validateDefault(kind string, def string) error {
+	elemType, isArray := arrayElementType(kind)
+	tag, isScalar := typeTags[kind]
+	if !isArray && (!isScalar || kind == "string") {
+		// Strings can be anything, e.g. "hashicorp/consul:<latest version>",
+		// and custom types can't be checked.
+		return nil
+	}
+	if keyReference.MatchString(def) && kind != "map" {
+		return nil
+	}
+
+	var parsed yaml.Node
+	if err := yaml.Unmarshal([]byte(def), &parsed); err != nil || len(parsed.Content) == 0 {
+		return fmt.Errorf("@default %q is not a valid %s", def, kind)
+	}
+	value := parsed.Content[0]
+
+	if isArray {
+		if value.Kind != yaml.SequenceNode {
+			return fmt.Errorf("@default %q is not a valid %s", def, kind)
+		}
+		var tags []string
+		for _, elem := range value.Content {
+			tags = append(tags, elem.Tag)
+		}
+		if err := checkElementTags(kind, elemType, tags); err != nil {
+			return fmt.Errorf("@default %q: %s", def, err)
+		}
+		return nil
+	}
+
+	if value.Tag != tag {
+		return fmt.Errorf("@default %q is not a valid %s", def, kind)
+	}
+	// If the YAML value is set then the annotation should agree with it.
+	// Maps aren't compared since their @default is usually a summary.
+	if kind != "map" && n.KindTag == tag && value.Value != n.Default {
+		return fmt.Errorf("@default %q doesn't match the default %q", def, n.Default)
+	}
+	return nil
+}
+
+// arrayElementType returns the element type of an array type, e.g.
+// "array<string>" => "string". ok is false if typ isn't an array.
+func arrayElementType(typ string) (elemType string, ok bool) {
+	if !strings.HasPrefix(typ, "array<") || !strings.HasSuffix(typ, ">") {
+		return "", false
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(typ, "array<"), ">"), true
+}
+
+// checkElementTags returns an error if any of the element kind tags don't
+// match elemType, the element type of typ.
+func checkElementTags(typ string, elemType string, tags []string) error {
+	want, ok := typeTags[elemType]
+	if !ok {
+		return nil
+	}
+	for i, tag := range tags {
+		if tag != want {
+			return fmt.Errorf("@type is %s but element %d is %s", typ, i, tagName(tag))
+		}
+	}
 	return nil
 }
 
+// tagName returns a human readable name for a YAML kind tag, e.g.
+// "!!str" => "a string".
+func tagName(tag string) string {
+	switch tag {
+	case "!!str":
+		return "a string"
+	case "!!int":
+		return "an integer"
+	case "!!bool":
+		return "a boolean"
+	case "!!float":
+		return "a float"
+	case "!!map":
+		return "a map"
+	case "!!seq":
+		return "an array"
+	}
+	return tag
+}
+
 // HTMLAnchor constructs the HTML anchor to be used to link to this node.
 func (n DocNode) HTMLAnchor() string {
 	return fmt.Sprintf("%s-%s", n.ParentBreadcrumb, strings.ToLower(n.Key))
 }
 
+// Anchor returns the HTML anchor used to link to this node in the generated
+// docs, e.g. "v-global-tls-enabled".
+func (n DocNode) Anchor() string {
+	return anchorForPath(n.Path())
+}
+
+// anchorForPath returns the HTML anchor for the key at a dotted path, e.g.
+// "global.tls.enabled" => "v-global-tls-enabled". It's the only place the
+// format of anchors is defined.
+func anchorForPath(path string) string {
+	return "v-" + strings.ToLower(strings.ReplaceAll(path, ".", "-"))
+}
+
+// AliasAnchors returns the HTML anchors for the previous paths of this node
+// from its @alias annotation.
+func (n DocNode) AliasAnchors() []string {
+	var anchors []string
+	for _, alias := range n.Aliases {
+		anchors = append(anchors, anchorForPath(alias))
+	}
+	return anchors
+}
+
+// Path returns the dotted path to this node from the root, e.g.
+// "global.tls.enabled".
+func (n DocNode) Path() string {
+	return joinPath(n.ParentPath, n.Key)
+}
+
+// joinPath returns the dotted path of key in the map at parentPath.
+func joinPath(parentPath string, key string) string {
+	if parentPath == "" {
+		return key
+	}
+	return parentPath + "." + key
+}
+
 // FormattedDefault returns the default value for this node formatted properly.
 func (n DocNode) FormattedDefault() string {
 
@@ -69,6 +402,11 @@ func (n DocNode) FormattedDefault() string {
 		return match[len(match)-1][1]
 	}
 
+	// Required keys don't have a default.
+	if n.Required && n.KindTag == "!!null" {
+		return ""
+	}
+
 	// We don't show the default if the kind is a map of arrays or map because the
 	// default will be too big to show inline.
 	if n.FormattedKind() == "array<map>" || n.FormattedKind() == "map" {
@@ -92,25 +430,23 @@ func (n DocNode) FormattedDefault() string {
 }
 
 // FormattedDocumentation returns the formatted documentation for this node.
+// Deprecation and enterprise notices are added before the documentation and
+// the allowed values and version added are added after it.
 func (n DocNode) FormattedDocumentation() string {
-	doc := n.Comment
-
 	// Replace all leading YAML comment characters, e.g.
 	// `# yaml comment` => `yaml comment`.
-	doc = commentPrefix.ReplaceAllString(n.Comment, "")
+	doc := commentPrefix.ReplaceAllString(n.documentationComment(), "")
 
 	// Indent each line of the documentation so it lines up correctly.
 	var indentedLines []string
 	for i, line := range strings.Split(doc, "\n") {
 
-		// If the line is a @type, @default or @recurse annotation we don't include it in
-		// the markdown description.
+		// If the line is an annotation we don't include it in the markdown
+		// description.
 		// This check must be before the i == 0 check because if there's only
 		// one line in the description and it's the type description then we
 		// want to discard it.
-		if len(typeAnnotation.FindStringSubmatch(line)) > 0 ||
-			len(defaultAnnotation.FindStringSubmatch(line)) > 0 ||
-			len(recurseAnnotation.FindStringSubmatch(line)) > 0 {
+		if isAnnotationLine(line) {
 			continue
 		}
 
@@ -121,11 +457,7 @@ func (n DocNode) FormattedDocumentation() string {
 		if i == 0 {
 			indentedLine = line
 		} else if line != "" {
-			indent := n.Column + 1
-			if n.ParentWasMap {
-				indent = n.Column
-			}
-			indentedLine = strings.Repeat(" ", indent) + line
+			indentedLine = n.docIndent() + line
 		} else {
 			// No need to add whitespace indent to a newline.
 		}
@@ -133,7 +465,117 @@ func (n DocNode) FormattedDocumentation() string {
 	}
 
 	// Trim all final newlines and whitespace.
-	return strings.TrimRight(strings.Join(indentedLines, "\n"), "\n ")
+	formatted := strings.TrimRight(strings.Join(indentedLines, "\n"), "\n ")
+
+	var prefix string
+	if n.Required {
+		prefix = "**Required**. "
+	}
+	if n.Deprecated {
+		deprecated := "**Deprecated**. "
+		if n.ReplacedBy != "" {
+			deprecated = fmt.Sprintf("**Deprecated**: use `%s` instead. ", n.ReplacedBy)
+		}
+		prefix = deprecated + prefix
+	}
+	if n.Enterprise && !strings.HasPrefix(formatted, enterpriseMarker) {
+		prefix = enterpriseMarker + " " + prefix
+	}
+	formatted = strings.TrimRight(prefix+formatted, " ")
+
+	var suffixes []string
+	if len(n.Enum) > 0 {
+		suffixes = append(suffixes, fmt.Sprintf("Supported values: `%s`.", strings.Join(n.Enum, "`, `")))
+	}
+	if n.Since != "" {
+		suffixes = append(suffixes, fmt.Sprintf("Added in chart version `%s`.", n.Since))
+	}
+	if n.YAMLAlias != "" {
+		prefix := "Defaults to"
+		if n.AliasIsElement {
+			prefix = "Its elements include"
+		}
+		if n.AliasOf != "" {
+			suffixes = append(suffixes, fmt.Sprintf("%s the value of `%s` via the YAML alias `*%s`.", prefix, n.AliasOf, n.YAMLAlias))
+		} else {
+			suffixes = append(suffixes, fmt.Sprintf("%s the value of the YAML anchor `&%s`.", prefix, n.YAMLAlias))
+		}
+	}
+	for _, suffix := range suffixes {
+		if formatted == "" {
+			formatted = suffix
+		} else {
+			formatted += "\n\n" + n.docIndent() + suffix
+		}
+	}
+	return formatted
+}
+
+// documentationComment returns all the YAML comments that document this
+// node. The line comment, e.g. `key: value # comment`, follows the head comment
+// and the foot comment is its own paragraph.
+func (n DocNode) documentationComment() string {
+	comment := n.Comment
+	if n.LineComment != "" {
+		if comment != "" {
+			comment += "\n"
+		}
+		comment += n.LineComment
+	}
+	if n.FootComment != "" {
+		if comment != "" {
+			comment += "\n#\n"
+		}
+		comment += n.FootComment
+	}
+	return comment
+}
+
+// docIndent returns the indentation for the lines of this node's
+// documentation after the first.
+func (n DocNode) docIndent() string {
+	indent := n.Column + 1
+	if n.ParentWasMap {
+		indent = n.Column
+	}
+	return strings.Repeat(" ", indent)
+}
+
+// PlainDocumentation returns the documentation for this node without any
+// YAML comment characters, annotations or indentation. A leading
+// [Enterprise Only] marker is also removed since it's captured by Enterprise.
+func (n DocNode) PlainDocumentation() string {
+	var lines []string
+	for _, line := range strings.Split(commentPrefix.ReplaceAllString(n.documentationComment(), ""), "\n") {
+		if isAnnotationLine(line) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	doc := strings.TrimSpace(strings.Join(lines, "\n"))
+	return strings.TrimSpace(strings.TrimPrefix(doc, enterpriseMarker))
+}
+
+// isAnnotationLine returns true if line, with its comment characters removed,
+// is an annotation that shouldn't be included in the documentation.
+func isAnnotationLine(line string) bool {
+	for _, annotation := range []*regexp.Regexp{
+		typeAnnotation,
+		defaultAnnotation,
+		recurseAnnotation,
+		enumAnnotation,
+		deprecatedAnnotation,
+		sinceAnnotation,
+		enterpriseAnnotation,
+		aliasAnnotation,
+		requiredAnnotation,
+		profileAnnotation,
+	} {
+		if annotation.MatchString(line) {
+			return true
+		}
+	}
+	return false
 }
 
 // FormattedKind returns the kind of this node, e.g. string, boolean, etc.
@@ -172,6 +614,25 @@ func (n DocNode) FormattedKind() string {
 	}
 }
 
+// Flatten returns all the descendants of this node keyed by their dotted
+// path from this node, e.g. "global.tls.enabled".
+func (n DocNode) Flatten() map[string]DocNode {
+	nodes := make(map[string]DocNode)
+	var walk func(prefix string, n DocNode)
+	walk = func(prefix string, n DocNode) {
+		for _, child := range n.Children {
+			key := child.Key
+			if prefix != "" {
+				key = prefix + "." + child.Key
+			}
+			nodes[key] = child
+			walk(key, child)
+		}
+	}
+	walk("", n)
+	return nodes
+}
+
 // LeadingIndent returns the leading indentation for the first line of this
 // node.
 func (n DocNode) LeadingIndent() string {
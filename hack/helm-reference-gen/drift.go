@@ -0,0 +1,302 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// valuesReference matches a reference to a values key from a template, e.g.
+// `.Values.global.tls.enabled` or `$root.Values.global.name`. It captures the
+// dotted path after `.Values.`.
+var valuesReference = regexp.MustCompile(`\.Values((?:\.[A-Za-z0-9_]+)+)`)
+
+// valuesAssignment matches assigning a values key to a variable, e.g.
+// `$defaults := .Values.ingressGateways.defaults`. It captures whether the
+// assignment is part of a range, the variable name and the dotted path after
+// `.Values.`.
+var valuesAssignment = regexp.MustCompile(`(range\s+)?(?:\$[A-Za-z0-9_]+\s*,\s*)?\$([A-Za-z0-9_]+)\s*:=\s*\(?\s*(?:\$[A-Za-z0-9_]*)?\.Values((?:\.[A-Za-z0-9_]+)+)`)
+
+// variableReference matches a reference to a key under a variable, e.g.
+// `$defaults.service.type`. It captures the variable name and the dotted
+// path after it.
+var variableReference = regexp.MustCompile(`\$([A-Za-z0-9_]+)((?:\.[A-Za-z0-9_]+)+)`)
+
+// includeValues matches passing a values key to a helper template, e.g.
+// `include "consul.extraEnvironmentVars" .Values.client`. It captures the
+// helper name and the dotted path after `.Values.`.
+var includeValues = regexp.MustCompile(`include\s+"([^"]+)"\s+\(?\s*(?:\$[A-Za-z0-9_]*)?\.Values((?:\.[A-Za-z0-9_]+)+)`)
+
+// defineStart matches the start of a helper template definition. It captures
+// the helper name.
+var defineStart = regexp.MustCompile(`define\s+"([^"]+)"`)
+
+// dotReference matches a reference to a key on the current context, e.g.
+// `.extraEnvironmentVars`. It captures the dotted path after the first dot.
+var dotReference = regexp.MustCompile(`(?:^|[\s(])\.([A-Za-z0-9_]+(?:\.[A-Za-z0-9_]+)*)`)
+
+// builtinObjects are the top-level objects that Helm passes to templates.
+// References to them from a helper aren't keys of the helper's context.
+var builtinObjects = map[string]bool{
+	"Values":       true,
+	"Release":      true,
+	"Chart":        true,
+	"Capabilities": true,
+	"Files":        true,
+	"Template":     true,
+}
+
+// DriftItem is a single key that is either used by the templates but not
+// documented in values.yaml or documented in values.yaml but not used by
+// the templates.
+type DriftItem struct {
+	// Key is the dotted path to the key, e.g. "global.tls.enabled".
+	Key string
+
+	// File is the file the key was found in.
+	File string
+
+	// Line is the line in File the key was found on.
+	Line int
+}
+
+func (d DriftItem) String() string {
+	return fmt.Sprintf("%s:%d: %s", d.File, d.Line, d.Key)
+}
+
+// DriftReport is the result of comparing the keys that the templates use to
+// the keys documented in values.yaml.
+type DriftReport struct {
+	// Undocumented are keys that the templates use but that aren't in
+	// values.yaml.
+	Undocumented []DriftItem
+
+	// Unused are keys in values.yaml that no template uses.
+	Unused []DriftItem
+}
+
+// HasDrift returns true if there are any undocumented or unused keys.
+func (r DriftReport) HasDrift() bool {
+	return len(r.Undocumented) > 0 || len(r.Unused) > 0
+}
+
+// String formats the report with one key per line.
+func (r DriftReport) String() string {
+	var b strings.Builder
+	if len(r.Undocumented) > 0 {
+		b.WriteString("Keys used by templates but not documented in values.yaml:\n")
+		for _, item := range r.Undocumented {
+			fmt.Fprintf(&b, "  %s\n", item)
+		}
+	}
+	if len(r.Unused) > 0 {
+		b.WriteString("Keys documented in values.yaml but not used by templates:\n")
+		for _, item := range r.Unused {
+			fmt.Fprintf(&b, "  %s\n", item)
+		}
+	}
+	return b.String()
+}
+
+// FindValuesReferences returns all the references to values keys from the
+// files in templatesDir. Each key is only returned once per line.
+//
+// Keys referenced through variables, e.g.
+// ```
+// {{- $defaults := .Values.ingressGateways.defaults }}
+// {{ $defaults.replicas }}
+// ```
+// are resolved to their full path, i.e. "ingressGateways.defaults.replicas".
+// The assignment itself isn't counted as a reference unless it's part of a
+// range since ranging over a key uses all of its sub-keys. Similarly, keys
+// passed to helper templates are resolved using the keys the helper reads.
+func FindValuesReferences(templatesDir string) ([]DriftItem, error) {
+	helpers, err := findHelperKeys(templatesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []DriftItem
+	err = filepath.Walk(templatesDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		// vars maps variable names to the key they were assigned from.
+		// Template variables are scoped to their file.
+		vars := make(map[string]string)
+		scanner := bufio.NewScanner(f)
+		line := 0
+		for scanner.Scan() {
+			line++
+			text := scanner.Text()
+			var keys []string
+
+			// assigned holds keys that were only assigned to a variable on
+			// this line and so shouldn't count as references.
+			assigned := make(map[string]bool)
+			for _, match := range valuesAssignment.FindAllStringSubmatch(text, -1) {
+				key := strings.TrimPrefix(match[3], ".")
+				vars[match[2]] = key
+				if match[1] == "" {
+					assigned[key] = true
+				}
+			}
+			for _, match := range valuesReference.FindAllStringSubmatch(text, -1) {
+				key := strings.TrimPrefix(match[1], ".")
+				if !assigned[key] {
+					keys = append(keys, key)
+				}
+			}
+			for _, match := range variableReference.FindAllStringSubmatch(text, -1) {
+				if parent, ok := vars[match[1]]; ok {
+					keys = append(keys, parent+match[2])
+				}
+			}
+			for _, match := range includeValues.FindAllStringSubmatch(text, -1) {
+				parent := strings.TrimPrefix(match[2], ".")
+				for _, key := range helpers[match[1]] {
+					keys = append(keys, parent+"."+key)
+				}
+			}
+
+			seen := make(map[string]bool)
+			for _, key := range keys {
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				refs = append(refs, DriftItem{Key: key, File: path, Line: line})
+			}
+		}
+		return scanner.Err()
+	})
+	return refs, err
+}
+
+// findHelperKeys returns the keys of their context that each helper
+// template defined in templatesDir reads, keyed by helper name. A helper's
+// definition is assumed to run until the next definition or the end of the
+// file, which is how _helpers.tpl is laid out.
+func findHelperKeys(templatesDir string) (map[string][]string, error) {
+	helpers := make(map[string][]string)
+	err := filepath.Walk(templatesDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		var name string
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			text := scanner.Text()
+			if match := defineStart.FindStringSubmatch(text); len(match) > 0 {
+				name = match[1]
+				continue
+			}
+			if name == "" {
+				continue
+			}
+			for _, match := range dotReference.FindAllStringSubmatch(text, -1) {
+				if !builtinObjects[strings.Split(match[1], ".")[0]] {
+					helpers[name] = append(helpers[name], match[1])
+				}
+			}
+		}
+		return scanner.Err()
+	})
+	return helpers, err
+}
+
+// DetectDrift compares the keys documented in yamlStr with the template
+// references in refs. valuesFile is used as the file name for unused keys.
+// ignoreKeys are keys that are intentionally undocumented, e.g. removed
+// settings that the templates check for.
+func DetectDrift(yamlStr string, valuesFile string, refs []DriftItem, ignoreKeys []string) (DriftReport, error) {
+	node, err := Parse(yamlStr)
+	if err != nil {
+		return DriftReport{}, err
+	}
+
+	documented := node.Flatten()
+
+	var report DriftReport
+	for _, ref := range refs {
+		if !isDocumented(ref.Key, documented) && !hasPrefixKey(ref.Key, ignoreKeys) {
+			report.Undocumented = append(report.Undocumented, ref)
+		}
+	}
+
+	for key, n := range documented {
+		// Only report leaf keys since a map is used if any of its keys are.
+		if len(n.Children) > 0 {
+			continue
+		}
+		if !isUsed(key, refs) {
+			report.Unused = append(report.Unused, DriftItem{Key: key, File: valuesFile, Line: n.Line})
+		}
+	}
+	sort.Slice(report.Unused, func(i, j int) bool {
+		return report.Unused[i].Line < report.Unused[j].Line
+	})
+	return report, nil
+}
+
+// isDocumented returns true if key, or a parent key without documented
+// sub-keys, e.g. a map annotated with @recurse: false, is documented.
+func isDocumented(key string, documented map[string]DocNode) bool {
+	parts := strings.Split(key, ".")
+	for i := len(parts); i > 0; i-- {
+		n, ok := documented[strings.Join(parts[:i], ".")]
+		if ok && (i == len(parts) || len(n.Children) == 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// isUsed returns true if a template references key, a sub-key of key or a
+// parent of key. A parent counts because templates commonly pass whole
+// maps to toYaml or range over them. Top-level keys, e.g. "client", don't
+// count as using all their sub-keys because they're passed to helpers that
+// only read a few of them.
+func isUsed(key string, refs []DriftItem) bool {
+	for _, ref := range refs {
+		if isKeyOrParent(key, ref.Key) {
+			return true
+		}
+		if strings.Contains(ref.Key, ".") && isKeyOrParent(ref.Key, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPrefixKey returns true if key is one of keys or a sub-key of one of them.
+func hasPrefixKey(key string, keys []string) bool {
+	for _, k := range keys {
+		if isKeyOrParent(k, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// isKeyOrParent returns true if parent is key or one of its parents, e.g.
+// "global.tls" is a parent of "global.tls.enabled".
+func isKeyOrParent(parent string, key string) bool {
+	return key == parent || strings.HasPrefix(key, parent+".")
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectDrift(t *testing.T) {
+	cases := map[string]struct {
+		Values          string
+		Template        string
+		ExpUndocumented []string
+		ExpUnused       []string
+	}{
+		"no drift": {
+			Values: `---
+global:
+  name: consul
+  tls:
+    enabled: false
+`,
+			Template: `{{ .Values.global.name }}
+{{ if .Values.global.tls.enabled }}{{ end }}`,
+		},
+		"undocumented key": {
+			Values: `---
+global:
+  name: consul
+`,
+			Template: `{{ .Values.global.name }}
+{{ .Values.global.domain }}`,
+			ExpUndocumented: []string{"templates/t.yaml:2: global.domain"},
+		},
+		"unused key": {
+			Values: `---
+global:
+  name: consul
+  domain: consul
+`,
+			Template:  `{{ .Values.global.name }}`,
+			ExpUnused: []string{"values.yaml:4: global.domain"},
+		},
+		"ignored key": {
+			Values: `---
+global:
+  name: consul
+`,
+			Template: `{{ .Values.global.name }}
+{{ if .Values.global.bootstrapACLs }}{{ fail "removed" }}{{ end }}`,
+		},
+		"parent map uses sub-keys": {
+			Values: `---
+server:
+  # @type: map
+  resources:
+    requests:
+      memory: "100Mi"
+`,
+			Template: `{{ toYaml .Values.server.resources }}`,
+		},
+		"sub-key of map without documented sub-keys": {
+			Values: `---
+server:
+  # @type: map
+  # @recurse: false
+  resources:
+    requests:
+      memory: "100Mi"
+`,
+			Template: `{{ .Values.server.resources.requests.memory }}`,
+		},
+		"variables": {
+			Values: `---
+gateways:
+  defaults:
+    replicas: 2
+    service:
+      type: ClusterIP
+`,
+			Template: `{{- $defaults := .Values.gateways.defaults }}
+{{ $defaults.replicas }}
+{{ $defaults.service.type }}`,
+		},
+		"variable assignment is not a use": {
+			Values: `---
+gateways:
+  defaults:
+    replicas: 2
+    service:
+      type: ClusterIP
+`,
+			Template: `{{- $defaults := .Values.gateways.defaults }}
+{{ $defaults.replicas }}`,
+			ExpUnused: []string{"values.yaml:6: gateways.defaults.service.type"},
+		},
+		"range is a use": {
+			Values: `---
+client:
+  nodeMeta:
+    pod-name: name
+`,
+			Template: `{{- range $k, $v := .Values.client.nodeMeta }}{{ $k }}{{ end }}`,
+		},
+		"helpers": {
+			Values: `---
+client:
+  extraEnvironmentVars: {}
+  other: value
+`,
+			Template: `{{- define "extraEnvironmentVars" -}}
+{{- if .extraEnvironmentVars -}}{{- end -}}
+{{- end -}}
+{{- include "extraEnvironmentVars" .Values.client }}`,
+			ExpUnused: []string{"values.yaml:4: client.other"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+			require.NoError(t, os.Mkdir(filepath.Join(dir, "templates"), 0755))
+			require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "templates", "t.yaml"), []byte(c.Template), 0644))
+
+			// Change directory so the file names in the report are relative.
+			wd, err := os.Getwd()
+			require.NoError(t, err)
+			require.NoError(t, os.Chdir(dir))
+			defer os.Chdir(wd)
+
+			refs, err := FindValuesReferences("templates")
+			require.NoError(t, err)
+			report, err := DetectDrift(c.Values, "values.yaml", refs, undocumentedKeys)
+			require.NoError(t, err)
+
+			var undocumented, unused []string
+			for _, item := range report.Undocumented {
+				undocumented = append(undocumented, item.String())
+			}
+			for _, item := range report.Unused {
+				unused = append(unused, item.String())
+			}
+			require.Equal(t, c.ExpUndocumented, undocumented)
+			require.Equal(t, c.ExpUnused, unused)
+			require.Equal(t, len(c.ExpUndocumented)+len(c.ExpUnused) > 0, report.HasDrift())
+		})
+	}
+}
+
+// Test that the chart's templates and values.yaml haven't drifted.
+func TestChartHasNoDrift(t *testing.T) {
+	valuesBytes, err := ioutil.ReadFile(filepath.Join("..", "..", "values.yaml"))
+	require.NoError(t, err)
+	refs, err := FindValuesReferences(filepath.Join("..", "..", "templates"))
+	require.NoError(t, err)
+	report, err := DetectDrift(string(valuesBytes), "values.yaml", refs, undocumentedKeys)
+	require.NoError(t, err)
+	require.False(t, report.HasDrift(), report.String())
+}
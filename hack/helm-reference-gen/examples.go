@@ -0,0 +1,276 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Example is a ```yaml code block in the documentation of a key, wrapped
+// under the path of the key so it's a complete values file.
+type Example struct {
+	// Path is the dotted path of the key whose documentation has the example.
+	Path string
+
+	// Index is the position of the example in the key's documentation,
+	// starting from 1. Blocks in other languages aren't counted.
+	Index int
+
+	// Values is the example as a values file.
+	Values string
+}
+
+// Filename returns the name of the file the example is written to, e.g.
+// "server.affinity.yaml". If the key has more than one example, the index of
+// the ones after the first is added, e.g. "server.extraConfig-2.yaml".
+func (e Example) Filename() string {
+	if e.Index > 1 {
+		return fmt.Sprintf("%s-%d.yaml", e.Path, e.Index)
+	}
+	return e.Path + ".yaml"
+}
+
+// FindExamples returns the ```yaml examples in the documentation of the tree
+// rooted at root and an error for each example that isn't valid. Examples
+// must be maps. They're wrapped under the path of the key they document
+// depending on their top-level key:
+//
+// - if it's the key itself, e.g. `affinity:`, it's wrapped under the key's
+// parent.
+//
+// - if it's the top-level key of the key's path, e.g. `global:`, it's
+// already complete.
+//
+// - otherwise the example is of the key's sub-keys and is wrapped under the
+// key.
+//
+// The keys the examples set must exist and their values must match the keys'
+// types. Examples that aren't valid YAML aren't returned.
+func FindExamples(root DocNode) ([]Example, []*ParseError) {
+	var examples []Example
+	var errs []*ParseError
+	var walk func(n DocNode)
+	walk = func(n DocNode) {
+		for _, child := range n.Children {
+			for i, source := range yamlCodeBlocks(child.PlainDocumentation()) {
+				example, exampleErrs := newExample(root, child, i+1, source)
+				errs = append(errs, exampleErrs...)
+				if example != nil {
+					examples = append(examples, *example)
+				}
+			}
+			walk(child)
+		}
+	}
+	walk(root)
+	return examples, errs
+}
+
+// CheckExamples returns an error for each example in the documentation of the
+// tree rooted at root that isn't valid. See FindExamples.
+func CheckExamples(root DocNode) []*ParseError {
+	_, errs := FindExamples(root)
+	return errs
+}
+
+// WriteExamples writes each example to its own file in dir, creating dir if
+// it doesn't exist.
+func WriteExamples(dir string, examples []Example) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, e := range examples {
+		contents := fmt.Sprintf("# Example %d from the documentation of %s in values.yaml.\n%s", e.Index, e.Path, e.Values)
+		if err := ioutil.WriteFile(filepath.Join(dir, e.Filename()), []byte(contents), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// yamlCodeBlocks returns the contents of the ```yaml code blocks in doc.
+func yamlCodeBlocks(doc string) []string {
+	var blocks []string
+	var block []string
+	inYAML, inFence := false, false
+	for _, line := range strings.Split(doc, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "```") {
+			if inYAML {
+				block = append(block, line)
+			}
+			continue
+		}
+		if inFence {
+			if inYAML {
+				blocks = append(blocks, strings.Join(block, "\n")+"\n")
+			}
+			inYAML, inFence, block = false, false, nil
+			continue
+		}
+		inFence = true
+		inYAML = strings.TrimPrefix(trimmed, "```") == "yaml"
+	}
+	return blocks
+}
+
+// newExample returns the index'th example of n from its source. The example
+// is nil if it isn't a valid YAML map.
+func newExample(root DocNode, n DocNode, index int, source string) (*Example, []*ParseError) {
+	exampleErr := func(format string, args ...interface{}) *ParseError {
+		return &ParseError{
+			ParentAnchor: n.ParentBreadcrumb,
+			CurrAnchor:   n.Key,
+			Path:         n.Path(),
+			Line:         n.Line,
+			Column:       n.Column,
+			Err:          fmt.Sprintf("example %d: %s", index, fmt.Sprintf(format, args...)),
+		}
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(source), &doc); err != nil {
+		return nil, []*ParseError{exampleErr("isn't valid YAML: %s", err)}
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode || len(doc.Content[0].Content) == 0 {
+		return nil, []*ParseError{exampleErr("must be a map of keys")}
+	}
+	values := doc.Content[0]
+
+	// Find the path of the map the example's keys are in.
+	var prefix string
+	switch topKey := values.Content[0].Value; {
+	case topKey == n.Key:
+		prefix = n.ParentPath
+	case topKey == strings.Split(n.Path(), ".")[0]:
+		prefix = ""
+	default:
+		prefix = n.Path()
+	}
+	if prefix != "" {
+		parts := strings.Split(prefix, ".")
+		for i := len(parts) - 1; i >= 0; i-- {
+			values = &yaml.Node{
+				Kind:    yaml.MappingNode,
+				Tag:     "!!map",
+				Content: []*yaml.Node{{Kind: yaml.ScalarNode, Tag: "!!str", Value: parts[i]}, values},
+			}
+		}
+	}
+
+	var errs []*ParseError
+	for _, msg := range checkExampleMap(root.Children, values, "") {
+		errs = append(errs, exampleErr("%s", msg))
+	}
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(values); err != nil {
+		return nil, append(errs, exampleErr("%s", err))
+	}
+	if err := enc.Close(); err != nil {
+		return nil, append(errs, exampleErr("%s", err))
+	}
+	return &Example{Path: n.Path(), Index: index, Values: out.String()}, errs
+}
+
+// checkExampleMap returns a message for each key in the map m at path that
+// isn't one of keys or whose value doesn't match the key's type.
+func checkExampleMap(keys []DocNode, m *yaml.Node, path string) []string {
+	var msgs []string
+	seen := make(map[string]bool)
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		name := m.Content[i].Value
+		keyPath := name
+		if path != "" {
+			keyPath = path + "." + name
+		}
+		if seen[name] {
+			msgs = append(msgs, fmt.Sprintf("sets %q more than once", keyPath))
+			continue
+		}
+		seen[name] = true
+
+		var key *DocNode
+		for j := range keys {
+			if keys[j].Key == name {
+				key = &keys[j]
+				break
+			}
+		}
+		if key == nil {
+			msgs = append(msgs, fmt.Sprintf("sets %q which doesn't exist", keyPath))
+			continue
+		}
+		msgs = append(msgs, checkExampleValue(*key, resolveAlias(m.Content[i+1]), keyPath)...)
+	}
+	return msgs
+}
+
+// checkExampleValue returns a message for each part of the value v of key at
+// path that doesn't match its type.
+func checkExampleValue(key DocNode, v *yaml.Node, path string) []string {
+	// Any key can be set to null to use the chart's default.
+	if v.Tag == "!!null" {
+		return nil
+	}
+
+	kind := key.FormattedKind()
+	if kind == "" && len(key.Children) > 0 {
+		kind = "map"
+	}
+	if tag, ok := typeTags[kind]; ok && v.Tag != tag {
+		return []string{fmt.Sprintf("sets %q to %s but its type is %s", path, describeTag(v.Tag), kind)}
+	}
+
+	switch {
+	case v.Kind == yaml.MappingNode && len(key.Children) > 0:
+		return checkExampleMap(key.Children, v, path)
+	case strings.HasPrefix(kind, "array<"):
+		if v.Kind != yaml.SequenceNode {
+			return []string{fmt.Sprintf("sets %q to %s but its type is %s", path, describeTag(v.Tag), kind)}
+		}
+		elemKind := strings.TrimSuffix(strings.TrimPrefix(kind, "array<"), ">")
+		var msgs []string
+		for i, elem := range resolveAliases(v.Content) {
+			elemPath := fmt.Sprintf("%s[%d]", path, i)
+			if tag, ok := typeTags[elemKind]; ok && elem.Tag != tag {
+				msgs = append(msgs, fmt.Sprintf("sets %q to %s but its type is %s", elemPath, describeTag(elem.Tag), elemKind))
+				continue
+			}
+			// The fields of the elements are documented with @items.
+			if elem.Kind == yaml.MappingNode && len(key.Children) > 0 {
+				msgs = append(msgs, checkExampleMap(key.Children, elem, elemPath)...)
+			}
+		}
+		return msgs
+	}
+	return nil
+}
+
+// describeTag returns a description of a value with the YAML kind tag, e.g.
+// "a boolean" for "!!bool".
+func describeTag(tag string) string {
+	switch tag {
+	case "!!str":
+		return "a string"
+	case "!!int":
+		return "an integer"
+	case "!!bool":
+		return "a boolean"
+	case "!!float":
+		return "a float"
+	case "!!map":
+		return "a map"
+	case "!!seq":
+		return "a list"
+	default:
+		return fmt.Sprintf("a value of kind '%s'", tag)
+	}
+}
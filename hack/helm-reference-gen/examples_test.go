@@ -0,0 +1,202 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindExamples(t *testing.T) {
+	input := `---
+server:
+  # Affinity docs.
+  #
+  # $$$yaml
+  # affinity: |
+  #   podAntiAffinity: {}
+  # $$$
+  #
+  # $$$shell
+  # --set 'server.affinity=null'
+  # $$$
+  #
+  # $$$yaml
+  # server:
+  #   affinity: null
+  # $$$
+  # @type: string
+  affinity: null
+
+  # Resources docs.
+  #
+  # $$$yaml
+  # requests:
+  #   # The CPU.
+  #   cpu: 100m
+  # $$$
+  # @type: map
+  resources:
+    requests:
+      cpu: 100m
+`
+	node, err := Parse(strings.Replace(input, "$", "`", -1))
+	require.NoError(t, err)
+	examples, errs := FindExamples(node)
+	require.Empty(t, errs)
+
+	var filenames, values []string
+	for _, e := range examples {
+		filenames = append(filenames, e.Filename())
+		values = append(values, e.Values)
+	}
+	require.Equal(t, []string{"server.affinity.yaml", "server.affinity-2.yaml", "server.resources.yaml"}, filenames)
+	require.Equal(t, []string{
+		`server:
+  affinity: |
+    podAntiAffinity: {}
+`,
+		`server:
+  affinity: null
+`,
+		`server:
+  resources:
+    requests:
+      # The CPU.
+      cpu: 100m
+`,
+	}, values)
+}
+
+func TestCheckExamples(t *testing.T) {
+	cases := map[string]struct {
+		Example string
+		Exp     string
+	}{
+		"unknown key": {
+			Example: "key:\n  unknown: true",
+			Exp:     `example 1: sets "map.key.unknown" which doesn't exist`,
+		},
+		"wrong type": {
+			Example: "enabled: yes please",
+			Exp:     `example 1: sets "map.key.enabled" to a string but its type is boolean`,
+		},
+		"map set to a scalar": {
+			Example: "key: value",
+			Exp:     `example 1: sets "map.key" to a string but its type is map`,
+		},
+		"set more than once": {
+			Example: "enabled: true\nenabled: false",
+			Exp:     `example 1: sets "map.key.enabled" more than once`,
+		},
+		"list set to a scalar": {
+			Example: "names: a",
+			Exp:     `example 1: sets "map.key.names" to a string but its type is array<string>`,
+		},
+		"wrong element type": {
+			Example: "names: [a, {b: c}]",
+			Exp:     `example 1: sets "map.key.names[1]" to a map but its type is string`,
+		},
+		"unknown item field": {
+			Example: "volumes:\n  - name: data\n    size: 1",
+			Exp:     `example 1: sets "map.key.volumes[0].size" which doesn't exist`,
+		},
+		"invalid yaml": {
+			Example: "key: [",
+			Exp:     "example 1: isn't valid YAML: yaml: line 1: did not find expected node content",
+		},
+		"not a map": {
+			Example: "- a",
+			Exp:     "example 1: must be a map of keys",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var example []string
+			for _, line := range strings.Split(c.Example, "\n") {
+				example = append(example, "  #   "+line)
+			}
+			input := `---
+map:
+  # Key docs.
+  #
+  # $$$yaml
+` + strings.Join(example, "\n") + `
+  # $$$
+  key:
+    enabled: false
+    # @type: array<string>
+    names: []
+    # @type: array<map>
+    # @items:
+    #   # The name.
+    #   # @type: string
+    #   name: null
+    volumes: []
+`
+			node, err := Parse(strings.Replace(input, "$", "`", -1))
+			require.NoError(t, err)
+			errs := CheckExamples(node)
+			require.Len(t, errs, 1)
+			require.Equal(t, "-map-key: "+c.Exp, errs[0].Error())
+		})
+	}
+}
+
+func TestCheckExamples_Valid(t *testing.T) {
+	input := `---
+map:
+  # Key docs.
+  #
+  # $$$yaml
+  # key:
+  #   enabled: null
+  #   names: [a, b]
+  #   volumes:
+  #     - name: data
+  #   # Maps without sub-keys can have any keys.
+  #   labels:
+  #     app: consul
+  # $$$
+  key:
+    enabled: false
+    # @type: array<string>
+    names: []
+    # @type: array<map>
+    # @items:
+    #   # The name.
+    #   # @type: string
+    #   name: null
+    volumes: []
+    # @type: map
+    labels: null
+`
+	node, err := Parse(strings.Replace(input, "$", "`", -1))
+	require.NoError(t, err)
+	require.Empty(t, CheckExamples(node))
+}
+
+func TestWriteExamples(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	examplesDir := filepath.Join(dir, "examples")
+	err = WriteExamples(examplesDir, []Example{{Path: "server.affinity", Index: 2, Values: "server:\n  affinity: null\n"}})
+	require.NoError(t, err)
+	contents, err := ioutil.ReadFile(filepath.Join(examplesDir, "server.affinity-2.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, "# Example 2 from the documentation of server.affinity in values.yaml.\nserver:\n  affinity: null\n", string(contents))
+}
+
+func TestExamplesValid(t *testing.T) {
+	valuesBytes, err := ioutil.ReadFile(filepath.Join("..", "..", "values.yaml"))
+	require.NoError(t, err)
+	node, err := Parse(string(valuesBytes))
+	require.NoError(t, err)
+	require.Empty(t, CheckExamples(node))
+}
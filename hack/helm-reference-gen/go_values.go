@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"regexp"
+	"strings"
+)
+
+// goValuesHeader is the start of the generated Go file.
+const goValuesHeader = `// Code generated by helm-reference-gen from values.yaml. DO NOT EDIT.
+// Regenerate with: make gen-go-values
+
+package helmvalues
+`
+
+// goNameSeparator matches the characters in a key that can't be in a Go
+// identifier.
+var goNameSeparator = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// goInitialisms are the parts of keys that are written in upper case in Go
+// names, keyed by their lower case form, e.g. "tls" => "TLS".
+var goInitialisms = map[string]string{
+	"acls":  "ACLs",
+	"api":   "API",
+	"ca":    "CA",
+	"dns":   "DNS",
+	"grpc":  "GRPC",
+	"http":  "HTTP",
+	"https": "HTTPS",
+	"id":    "ID",
+	"ip":    "IP",
+	"k8s":   "K8S",
+	"tls":   "TLS",
+	"ui":    "UI",
+	"url":   "URL",
+}
+
+// goStruct is a struct type to generate for a map in values.yaml or for the
+// elements of an array of maps.
+type goStruct struct {
+	Name   string
+	Doc    string
+	Fields []DocNode
+}
+
+// GenerateGoValues returns the source of a Go file with a struct for each map
+// in yamlStr, starting with Values for the root. Fields are pointers, or
+// slices and maps, so that unset fields can be told apart from zero values.
+// Their doc comments are the keys' documentation and defaults.
+func GenerateGoValues(yamlStr string) (string, error) {
+	node, err := Parse(yamlStr)
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	out.WriteString(goValuesHeader)
+
+	// Generate the structs breadth first so the top-level ones come first.
+	queue := []goStruct{{Name: "Values", Doc: "Values are the values of the Helm chart.", Fields: node.Children}}
+	names := make(map[string]string)
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+
+		fmt.Fprintf(&out, "\n// %s\ntype %s struct {\n", s.Doc, s.Name)
+		for i, field := range s.Fields {
+			typ, nested, err := goFieldType(field, names)
+			if err != nil {
+				return "", err
+			}
+			if nested != nil {
+				queue = append(queue, *nested)
+			}
+			if i > 0 {
+				out.WriteString("\n")
+			}
+			for _, line := range goFieldDoc(field) {
+				out.WriteString(strings.TrimRight("\t// "+line, " ") + "\n")
+			}
+			fmt.Fprintf(&out, "\t%s %s `yaml:\"%s,omitempty\"`\n", goName(field.Key), typ, field.Key)
+		}
+		out.WriteString("}\n")
+	}
+
+	formatted, err := format.Source([]byte(out.String()))
+	if err != nil {
+		return "", err
+	}
+	return string(formatted), nil
+}
+
+// goFieldType returns the Go type of the field for n. If it's a struct that
+// needs generating, that's returned too. names is the paths of the structs
+// generated so far keyed by their names so that clashes are caught.
+func goFieldType(n DocNode, names map[string]string) (string, *goStruct, error) {
+	kind := n.FormattedKind()
+	switch {
+	case len(n.Children) > 0 && kind == "":
+		name, err := goStructName(n.Path(), "Values", names)
+		if err != nil {
+			return "", nil, err
+		}
+		return "*" + name, &goStruct{
+			Name:   name,
+			Doc:    fmt.Sprintf("%s are the values under `%s`.", name, n.Path()),
+			Fields: n.Children,
+		}, nil
+	case len(n.Children) > 0 && kind == "array<map>":
+		name, err := goStructName(n.Path(), "Item", names)
+		if err != nil {
+			return "", nil, err
+		}
+		return "[]" + name, &goStruct{
+			Name:   name,
+			Doc:    fmt.Sprintf("%s is an element of `%s`.", name, n.Path()),
+			Fields: n.Children,
+		}, nil
+	}
+
+	switch kind {
+	case "string":
+		return "*string", nil, nil
+	case "boolean":
+		// Booleans are strings so that they can also be set to "-".
+		return "*Boolean", nil, nil
+	case "integer", "int":
+		return "*int", nil, nil
+	case "map", "":
+		return "map[string]interface{}", nil, nil
+	case "array<string>":
+		return "[]string", nil, nil
+	case "array<map>":
+		return "[]map[string]interface{}", nil, nil
+	default:
+		// Types like "string or integer" and other arrays can't be
+		// represented exactly.
+		return "interface{}", nil, nil
+	}
+}
+
+// goStructName returns the name of the struct for the key at path, e.g.
+// "GlobalTLSValues" for "global.tls" with the suffix "Values".
+func goStructName(path string, suffix string, names map[string]string) (string, error) {
+	var name strings.Builder
+	for _, part := range strings.Split(path, ".") {
+		name.WriteString(goName(part))
+	}
+	name.WriteString(suffix)
+	if other, ok := names[name.String()]; ok {
+		return "", fmt.Errorf("the Go struct for %q would have the same name as the one for %q: %s", path, other, name.String())
+	}
+	names[name.String()] = path
+	return name.String(), nil
+}
+
+// goName returns the exported Go name for key, e.g. "connectInject" =>
+// "ConnectInject" and "tls" => "TLS".
+func goName(key string) string {
+	var name strings.Builder
+	for _, part := range goNameSeparator.Split(key, -1) {
+		if part == "" {
+			continue
+		}
+		if initialism, ok := goInitialisms[strings.ToLower(part)]; ok {
+			name.WriteString(initialism)
+			continue
+		}
+		name.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return name.String()
+}
+
+// goFieldDoc returns the lines of the doc comment for the field for n: its
+// documentation, its default and whether it's deprecated.
+func goFieldDoc(n DocNode) []string {
+	var lines []string
+	if doc := n.PlainDocumentation(); doc != "" {
+		lines = strings.Split(doc, "\n")
+	}
+	addParagraph := func(line string) {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, line)
+	}
+	if n.Required {
+		addParagraph("Required.")
+	}
+	// Maps with sub-keys have a default for each of them instead.
+	if def := n.FormattedDefault(); def != "" && len(n.Children) == 0 {
+		addParagraph(fmt.Sprintf("Default: %s", def))
+	}
+	if n.Deprecated {
+		if n.ReplacedBy != "" {
+			addParagraph(fmt.Sprintf("Deprecated: use %s instead.", n.ReplacedBy))
+		} else {
+			addParagraph("Deprecated: this key is deprecated.")
+		}
+	}
+	return lines
+}
+
+// ValidateGoValues returns an error if the existing contents of the
+// generated Go file don't match the file generated from yamlStr.
+func ValidateGoValues(yamlStr string, existing string) error {
+	generated, err := GenerateGoValues(yamlStr)
+	if err != nil {
+		return err
+	}
+	if generated != existing {
+		return fmt.Errorf("test/acceptance/framework/helmvalues/values.go is out of date, regenerate it with: make gen-go-values")
+	}
+	return nil
+}
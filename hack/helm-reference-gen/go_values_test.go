@@ -0,0 +1,121 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateGoValues(t *testing.T) {
+	out, err := GenerateGoValues(`---
+global:
+  # The name.
+  name: consul
+  # Enabled.
+  enabled: true
+  tls:
+    # @type: array<string>
+    serverAdditionalDNSSANs: []
+  # @deprecated: global.name
+  # @type: string
+  oldName: null
+server:
+  # @type: map
+  resources: {}
+  # Volumes.
+  # @type: array<map>
+  # @items:
+  #   # @type: string
+  #   # @required: true
+  #   name: null
+  extraVolumes: []
+  replicas: 3
+  # @type: string or integer
+  port: 8080
+`)
+	require.NoError(t, err)
+	require.Equal(t, strings.Replace(`// Code generated by helm-reference-gen from values.yaml. DO NOT EDIT.
+// Regenerate with: make gen-go-values
+
+package helmvalues
+
+// Values are the values of the Helm chart.
+type Values struct {
+	Global *GlobalValues $yaml:"global,omitempty"$
+
+	Server *ServerValues $yaml:"server,omitempty"$
+}
+
+// GlobalValues are the values under $global$.
+type GlobalValues struct {
+	// The name.
+	//
+	// Default: consul
+	Name *string $yaml:"name,omitempty"$
+
+	// Enabled.
+	//
+	// Default: true
+	Enabled *Boolean $yaml:"enabled,omitempty"$
+
+	TLS *GlobalTLSValues $yaml:"tls,omitempty"$
+
+	// Default: null
+	//
+	// Deprecated: use global.name instead.
+	OldName *string $yaml:"oldName,omitempty"$
+}
+
+// ServerValues are the values under $server$.
+type ServerValues struct {
+	Resources map[string]interface{} $yaml:"resources,omitempty"$
+
+	// Volumes.
+	ExtraVolumes []ServerExtraVolumesItem $yaml:"extraVolumes,omitempty"$
+
+	// Default: 3
+	Replicas *int $yaml:"replicas,omitempty"$
+
+	// Default: 8080
+	Port interface{} $yaml:"port,omitempty"$
+}
+
+// GlobalTLSValues are the values under $global.tls$.
+type GlobalTLSValues struct {
+	// Default: []
+	ServerAdditionalDNSSANs []string $yaml:"serverAdditionalDNSSANs,omitempty"$
+}
+
+// ServerExtraVolumesItem is an element of $server.extraVolumes$.
+type ServerExtraVolumesItem struct {
+	// Required.
+	Name *string $yaml:"name,omitempty"$
+}
+`, "$", "`", -1), out)
+}
+
+func TestGoName(t *testing.T) {
+	cases := map[string]string{
+		"connectInject":    "ConnectInject",
+		"tls":              "TLS",
+		"acls":             "ACLs",
+		"imageK8S":         "ImageK8S",
+		"pod-name":         "PodName",
+		"manageSystemACLs": "ManageSystemACLs",
+	}
+	for key, exp := range cases {
+		require.Equal(t, exp, goName(key), key)
+	}
+}
+
+// Test that the acceptance tests' Go values are up to date.
+func TestGoValuesUpToDate(t *testing.T) {
+	valuesBytes, err := ioutil.ReadFile(filepath.Join("..", "..", "values.yaml"))
+	require.NoError(t, err)
+	goValuesBytes, err := ioutil.ReadFile(filepath.Join("..", "..", "test", "acceptance", "framework", "helmvalues", "values.go"))
+	require.NoError(t, err)
+	require.NoError(t, ValidateGoValues(string(valuesBytes), string(goValuesBytes)))
+}
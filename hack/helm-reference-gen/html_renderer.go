@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bytes"
+	"html"
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+var (
+	// inlineCode matches inline code in documentation, e.g. `global.name`.
+	// It captures the code.
+	inlineCode = regexp.MustCompile("`([^`]+)`")
+
+	// markdownLink matches a markdown link in documentation, e.g.
+	// [text](https://example.com). It captures the text and the URL.
+	markdownLink = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+
+	// htmlTmpl is the go template used to render a standalone HTML page. Its
+	// data is an htmlPage. nodeErrors and expandDefaults are replaced when it's
+	// executed.
+	htmlTmpl = template.Must(template.New("").Funcs(template.FuncMap{
+		"docHTML":        docHTML,
+		"anchorForPath":  anchorForPath,
+		"nodeErrors":     func(DocNode) []string { return nil },
+		"expandDefaults": func() bool { return false },
+	}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Helm Chart Values Reference</title>
+<style>
+body { font-family: sans-serif; line-height: 1.5; margin: 0; display: flex; }
+nav { position: sticky; top: 0; height: 100vh; overflow-y: auto; min-width: 16em; padding: 1em; border-right: 1px solid #ddd; }
+main { padding: 1em 2em; max-width: 60em; }
+ul.values { list-style: none; padding-left: 1.5em; }
+pre { background: #f5f5f5; padding: 0.5em; overflow-x: auto; }
+code.kind { color: #555; }
+.required, .enterprise, .deprecated, .since { border-radius: 3px; padding: 0 0.3em; font-size: 0.85em; }
+.required { background: #fff0c0; }
+.enterprise { background: #eee0ff; }
+.deprecated { background: #ffe0e0; }
+.since { background: #e0f0ff; }
+.errors { background: #ffe0e0; border: 1px solid #c00; padding: 0.5em 1em; }
+p.error { color: #c00; }
+details > summary { cursor: pointer; }
+details > summary > h2 { display: inline; }
+#search { width: 100%; box-sizing: border-box; margin-bottom: 1em; }
+</style>
+</head>
+<body>
+<nav>
+<input id="search" type="search" placeholder="Search keys">
+<h2>Contents</h2>
+<ul>
+{{- range .Root.Children }}
+<li><a href="#{{ .Anchor }}">{{ .Key }}</a></li>
+{{- end }}
+</ul>
+</nav>
+<main>
+<h1>Helm Chart Values Reference</h1>
+{{- with .Errors }}
+<div class="errors">
+<h2>Errors</h2>
+<ul>
+{{- range . }}
+<li>{{ if .Line }}line {{ .Line }}:{{ .Column }}: <a href="#{{ anchorForPath .Path }}">{{ .Path }}</a>: {{ end }}{{ .Err }}</li>
+{{- end }}
+</ul>
+</div>
+{{- end }}
+{{- range .Root.Children }}
+<details open>
+<summary><h2>{{ .Key }}</h2></summary>
+<ul class="values">
+{{ template "node" . }}
+</ul>
+</details>
+{{- end }}
+</main>
+{{- /*
+Hide the keys that don't match the search, along with sections that have no
+matching keys. A key matches if its path or its own documentation contains
+the search. Keys are checked from the deepest up so that the parents of
+matching keys are shown too.
+*/}}
+<script>
+document.getElementById("search").addEventListener("input", function (e) {
+  var query = e.target.value.toLowerCase();
+  var keys = Array.prototype.slice.call(document.querySelectorAll("main li[data-path]"));
+  keys.reverse().forEach(function (li) {
+    var text = li.dataset.path.toLowerCase();
+    li.childNodes.forEach(function (n) {
+      if (n.nodeName !== "UL") {
+        text += " " + n.textContent.toLowerCase();
+      }
+    });
+    var childMatches = li.querySelector("li[data-path]:not([hidden])") !== null;
+    li.hidden = !(text.indexOf(query) !== -1 || childMatches);
+  });
+  document.querySelectorAll("main > details").forEach(function (section) {
+    section.hidden = section.querySelector("li[data-path]:not([hidden])") === null;
+  });
+});
+</script>
+{{- if .LiveReload }}
+{{- /* Reload the page when the values file is re-rendered. */}}
+<script>
+(function () {
+  var version = null;
+  setInterval(function () {
+    fetch("/version").then(function (resp) { return resp.text(); }).then(function (v) {
+      if (version !== null && v !== version) {
+        location.reload();
+      }
+      version = v;
+    }).catch(function () {});
+  }, 1000);
+})();
+</script>
+{{- end }}
+</body>
+</html>
+{{ define "node" -}}
+<li id="{{ .Anchor }}" data-path="{{ .Path }}">{{ range .AliasAnchors }}<span id="{{ . }}"></span>{{ end }}<a href="#{{ .Anchor }}"><code>{{ .Key }}</code></a>
+{{- if ne .FormattedKind "" }} <code class="kind">{{ .FormattedKind }}{{ if .FormattedDefault }}: {{ .FormattedDefault }}{{ end }}</code>{{ end }}
+{{- if .Required }} <span class="required">Required</span>{{ end }}
+{{- if .Enterprise }} <span class="enterprise">Enterprise Only</span>{{ end }}
+{{- if .Deprecated }} <span class="deprecated">Deprecated{{ with .ReplacedBy }}: use <code>{{ . }}</code> instead{{ end }}</span>{{ end }}
+{{- with .Since }} <span class="since">Since {{ . }}</span>{{ end }}
+{{- range nodeErrors . }}
+<p class="error">{{ . }}</p>
+{{- end }}
+{{- with .PlainDocumentation }}
+{{ docHTML . }}
+{{- end }}
+{{- with .Enum }}
+<p>Supported values: {{ range $i, $v := . }}{{ if $i }}, {{ end }}<code>{{ $v }}</code>{{ end }}</p>
+{{- end }}
+{{- if expandDefaults }}{{ with .ExpandedDefault }}
+<details class="default"><summary>Default value</summary><pre><code>{{ . }}</code></pre></details>
+{{- end }}{{ end }}
+{{- if .Children }}
+<ul class="values">
+{{- range .Children }}
+{{ template "node" . }}
+{{- end }}
+</ul>
+{{- end }}
+</li>
+{{- end }}`))
+)
+
+// htmlPage is the data for htmlTmpl.
+type htmlPage struct {
+	// Root is the root returned by Parse.
+	Root DocNode
+
+	// Errors are shown at the top of the page and under the keys they're
+	// for.
+	Errors []*ParseError
+
+	// LiveReload is true if the page should reload when the /version served
+	// by the PreviewServer changes.
+	LiveReload bool
+
+	// ExpandDefaults is RendererOptions.ExpandDefaults.
+	ExpandDefaults bool
+}
+
+// HTMLRenderer renders a standalone HTML page with a table of contents.
+type HTMLRenderer struct {
+	// ExpandDefaults is RendererOptions.ExpandDefaults.
+	ExpandDefaults bool
+}
+
+func (r HTMLRenderer) Render(node DocNode) (string, error) {
+	return renderHTMLPage(htmlPage{Root: node, ExpandDefaults: r.ExpandDefaults})
+}
+
+// renderHTMLPage renders page with htmlTmpl.
+func renderHTMLPage(page htmlPage) (string, error) {
+	errs := make(map[string][]string)
+	for _, err := range page.Errors {
+		errs[err.Anchor()] = append(errs[err.Anchor()], err.Err)
+	}
+	tmpl, err := htmlTmpl.Clone()
+	if err != nil {
+		return "", err
+	}
+	tmpl.Funcs(template.FuncMap{
+		"nodeErrors": func(n DocNode) []string {
+			return errs[n.HTMLAnchor()]
+		},
+		"expandDefaults": func() bool {
+			return page.ExpandDefaults
+		},
+	})
+
+	var out bytes.Buffer
+	err = tmpl.Execute(&out, page)
+	return linkHTMLReferences(out.String(), page.Root), err
+}
+
+// docHTML converts the subset of markdown used in values.yaml documentation
+// into HTML: paragraphs, fenced code blocks, inline code and links.
+func docHTML(doc string) template.HTML {
+	var out strings.Builder
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			out.WriteString("<p>" + inlineHTML(strings.Join(paragraph, "\n")) + "</p>\n")
+			paragraph = nil
+		}
+	}
+
+	inFence := false
+	for _, line := range strings.Split(doc, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```") && !inFence:
+			flush()
+			inFence = true
+			out.WriteString("<pre><code>")
+		case strings.HasPrefix(trimmed, "```") && inFence:
+			inFence = false
+			out.WriteString("</code></pre>\n")
+		case inFence:
+			out.WriteString(html.EscapeString(line) + "\n")
+		case trimmed == "":
+			flush()
+		default:
+			paragraph = append(paragraph, line)
+		}
+	}
+	if inFence {
+		out.WriteString("</code></pre>\n")
+	}
+	flush()
+	return template.HTML(strings.TrimSuffix(out.String(), "\n"))
+}
+
+// inlineHTML escapes text and converts inline code, links and the
+// [Enterprise Only] marker into HTML.
+func inlineHTML(text string) string {
+	escaped := html.EscapeString(text)
+	escaped = inlineCode.ReplaceAllString(escaped, "<code>$1</code>")
+	escaped = markdownLink.ReplaceAllString(escaped, `<a href="$2">$1</a>`)
+	return strings.ReplaceAll(escaped, "[Enterprise Only]", `<span class="enterprise">Enterprise Only</span>`)
+}
@@ -0,0 +1,397 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// imageKey matches the keys that hold container images, e.g. "image",
+// "imageK8S" and "imageEnvoy", but not "imagePullSecrets".
+var imageKey = regexp.MustCompile(`^image(?:[A-Z0-9]\w*)?$`)
+
+// defaultValues matches a template that defaults one values key to another,
+// e.g. `default .Values.global.image .Values.server.image`. It captures the
+// dotted path of the default and of the key that overrides it.
+var defaultValues = regexp.MustCompile(`default\s+\(?\s*(?:\$[A-Za-z0-9_]*)?\.Values((?:\.[A-Za-z0-9_]+)+)\s+(?:\$[A-Za-z0-9_]*)?\.Values((?:\.[A-Za-z0-9_]+)+)`)
+
+// literalImage matches an image that is hardcoded in a template rather than
+// coming from values, e.g. `image: "quay.io/prometheus/prometheus:v2.24.0"`.
+// It captures the image.
+var literalImage = regexp.MustCompile(`^\s*(?:-\s+)?image:\s*"?([^"{}\s]+)"?\s*$`)
+
+// templateCondition matches a template that is wrapped in an if, e.g.
+// `{{- if .Values.prometheus.enabled }}`. It captures the dotted path of the
+// key.
+var templateCondition = regexp.MustCompile(`^\{\{-?\s*if\s+(?:\$[A-Za-z0-9_]*)?\.Values((?:\.[A-Za-z0-9_]+)+)\s*-?\}\}`)
+
+// secretDataKey matches a data key in a `kubectl create secret` example, e.g.
+// `--from-literal=key=...` or `--from-file='tls.crt=./ca.pem'`. It captures
+// the data key.
+var secretDataKey = regexp.MustCompile(`--from-(?:literal|file)=['"]?([\w.-]+)=`)
+
+// ImageRef is a container image that the chart may deploy.
+type ImageRef struct {
+	// Name is the name of the image in the artifacthub.io/images annotation
+	// in Chart.yaml, if it's listed there.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// Image is the full image, e.g. "hashicorp/consul:1.10.0".
+	Image      string `json:"image" yaml:"image"`
+	Repository string `json:"repository" yaml:"repository"`
+	Tag        string `json:"tag,omitempty" yaml:"tag,omitempty"`
+	Digest     string `json:"digest,omitempty" yaml:"digest,omitempty"`
+
+	// Key is the values key whose default is the image, e.g. "global.image".
+	// It's empty if the image is hardcoded in a template.
+	Key string `json:"key,omitempty" yaml:"key,omitempty"`
+
+	// OverriddenBy are the values keys that override Key for a single
+	// component, e.g. "server.image".
+	OverriddenBy []string `json:"overriddenBy,omitempty" yaml:"overriddenBy,omitempty"`
+
+	// Template is the template that the image is hardcoded in, if it is.
+	Template string `json:"template,omitempty" yaml:"template,omitempty"`
+
+	// EnabledBy is the values key that the template with a hardcoded image
+	// is conditional on, e.g. "prometheus.enabled".
+	EnabledBy string `json:"enabledBy,omitempty" yaml:"enabledBy,omitempty"`
+}
+
+// SecretRef is a Kubernetes secret that the chart may read, configured via
+// a secretName key and usually a secretKey key.
+type SecretRef struct {
+	// Key is the values key of the map holding secretName, e.g.
+	// "global.tls.caCert".
+	Key string `json:"key" yaml:"key"`
+
+	// SecretNameKey and SecretKeyKey are the values keys that set the name
+	// of the secret and the key of the data within it.
+	SecretNameKey string `json:"secretNameKey" yaml:"secretNameKey"`
+	SecretKeyKey  string `json:"secretKeyKey,omitempty" yaml:"secretKeyKey,omitempty"`
+
+	// SecretName and SecretKey are the defaults of SecretNameKey and
+	// SecretKeyKey, if they have any.
+	SecretName string `json:"secretName,omitempty" yaml:"secretName,omitempty"`
+	SecretKey  string `json:"secretKey,omitempty" yaml:"secretKey,omitempty"`
+
+	// ExpectedDataKeys are the data keys from the `kubectl create secret`
+	// example in the documentation, e.g. ["tls.crt"].
+	ExpectedDataKeys []string `json:"expectedDataKeys,omitempty" yaml:"expectedDataKeys,omitempty"`
+
+	Enterprise bool `json:"enterprise,omitempty" yaml:"enterprise,omitempty"`
+
+	// Description is the first sentence of the map's documentation.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// ChartInfo is the chart metadata from Chart.yaml.
+type ChartInfo struct {
+	Name       string `json:"name" yaml:"name"`
+	Version    string `json:"version" yaml:"version"`
+	AppVersion string `json:"appVersion" yaml:"appVersion"`
+}
+
+// Inventory lists the images and secrets the chart may use, e.g. so they
+// can be mirrored or created ahead of an air-gapped install.
+type Inventory struct {
+	Chart   ChartInfo   `json:"chart" yaml:"chart"`
+	Images  []ImageRef  `json:"images" yaml:"images"`
+	Secrets []SecretRef `json:"secrets" yaml:"secrets"`
+}
+
+// JSON formats the inventory as indented JSON.
+func (inv Inventory) JSON() (string, error) {
+	// Output empty lists rather than null when there are no images or
+	// secrets.
+	if inv.Images == nil {
+		inv.Images = []ImageRef{}
+	}
+	if inv.Secrets == nil {
+		inv.Secrets = []SecretRef{}
+	}
+	out, err := json.MarshalIndent(inv, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out) + "\n", nil
+}
+
+// YAML formats the inventory as YAML.
+func (inv Inventory) YAML() (string, error) {
+	var out strings.Builder
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(inv); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// TemplateImage is a use of an image in a template, found by
+// FindTemplateImages.
+type TemplateImage struct {
+	// Default and Override are set when a template defaults one values key to
+	// another, e.g. "global.image" and "server.image".
+	Default  string
+	Override string
+
+	// Image, File and EnabledBy are set when an image is hardcoded in File.
+	Image     string
+	File      string
+	EnabledBy string
+}
+
+// FindTemplateImages returns the images hardcoded in the templates in
+// templatesDir and the values keys that override other values keys, which
+// is how component images like server.image default to global.image. File
+// is relative to the chart root, e.g. "templates/prometheus.yaml".
+func FindTemplateImages(templatesDir string) ([]TemplateImage, error) {
+	var images []TemplateImage
+	err := filepath.Walk(templatesDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(filepath.Dir(templatesDir), path)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		var enabledBy string
+		scanner := bufio.NewScanner(f)
+		line := 0
+		for scanner.Scan() {
+			line++
+			text := scanner.Text()
+			if line == 1 {
+				if match := templateCondition.FindStringSubmatch(text); match != nil {
+					enabledBy = strings.TrimPrefix(match[1], ".")
+				}
+			}
+			for _, match := range defaultValues.FindAllStringSubmatch(text, -1) {
+				images = append(images, TemplateImage{
+					Default:  strings.TrimPrefix(match[1], "."),
+					Override: strings.TrimPrefix(match[2], "."),
+				})
+			}
+			if match := literalImage.FindStringSubmatch(text); match != nil {
+				images = append(images, TemplateImage{
+					Image:     match[1],
+					File:      filepath.ToSlash(rel),
+					EnabledBy: enabledBy,
+				})
+			}
+		}
+		return scanner.Err()
+	})
+	return images, err
+}
+
+// BuildInventory returns the images and secrets that the chart with the
+// values.yaml yamlStr, the Chart.yaml chartYaml and the template images
+// templateImages may use. Images listed in Chart.yaml's
+// artifacthub.io/images annotation are named after it. A warning is
+// returned for each image listed there that the chart doesn't use so the
+// annotation can be kept up to date.
+func BuildInventory(yamlStr string, chartYaml string, templateImages []TemplateImage) (Inventory, []string, error) {
+	node, err := Parse(yamlStr)
+	if err != nil {
+		return Inventory{}, nil, err
+	}
+
+	var chart struct {
+		ChartInfo   `yaml:",inline"`
+		Annotations map[string]string `yaml:"annotations"`
+	}
+	if err := yaml.Unmarshal([]byte(chartYaml), &chart); err != nil {
+		return Inventory{}, nil, fmt.Errorf("parsing Chart.yaml: %s", err)
+	}
+	var chartImages []struct {
+		Name  string `yaml:"name"`
+		Image string `yaml:"image"`
+	}
+	if err := yaml.Unmarshal([]byte(chart.Annotations["artifacthub.io/images"]), &chartImages); err != nil {
+		return Inventory{}, nil, fmt.Errorf("parsing artifacthub.io/images in Chart.yaml: %s", err)
+	}
+
+	inv := Inventory{
+		Chart:   chart.ChartInfo,
+		Images:  inventoryImages(node, templateImages),
+		Secrets: inventorySecrets(node),
+	}
+
+	var warnings []string
+	for _, chartImage := range chartImages {
+		found := false
+		for i := range inv.Images {
+			if inv.Images[i].Image == chartImage.Image {
+				inv.Images[i].Name = chartImage.Name
+				found = true
+			}
+		}
+		if !found {
+			warnings = append(warnings, fmt.Sprintf("image %s (%s) in Chart.yaml isn't used by the chart", chartImage.Image, chartImage.Name))
+		}
+	}
+	return inv, warnings, nil
+}
+
+// inventoryImages returns the images that are the defaults of the image keys
+// in node and the images hardcoded in the templates.
+func inventoryImages(node DocNode, templateImages []TemplateImage) []ImageRef {
+	keys := node.Flatten()
+	var paths []string
+	for path := range keys {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	overrides := make(map[string][]string)
+	for _, t := range templateImages {
+		if t.Override != "" && !contains(overrides[t.Default], t.Override) {
+			overrides[t.Default] = append(overrides[t.Default], t.Override)
+		}
+	}
+
+	var images []ImageRef
+	for _, path := range paths {
+		n := keys[path]
+		if !imageKey.MatchString(n.Key) || n.KindTag != "!!str" || n.Default == "" {
+			continue
+		}
+		ref := newImageRef(n.Default)
+		ref.Key = path
+		ref.OverriddenBy = overrides[path]
+		sort.Strings(ref.OverriddenBy)
+		images = append(images, ref)
+	}
+
+	seen := make(map[string]bool)
+	for _, t := range templateImages {
+		if t.Image == "" || seen[t.Image] {
+			continue
+		}
+		seen[t.Image] = true
+		ref := newImageRef(t.Image)
+		ref.Template = t.File
+		ref.EnabledBy = t.EnabledBy
+		images = append(images, ref)
+	}
+	return images
+}
+
+// newImageRef splits image into its repository, tag and digest, e.g.
+// "hashicorp/consul:1.10.0" has the repository "hashicorp/consul" and the
+// tag "1.10.0".
+func newImageRef(image string) ImageRef {
+	ref := ImageRef{Image: image, Repository: image}
+	if i := strings.Index(ref.Repository, "@"); i != -1 {
+		ref.Digest = ref.Repository[i+1:]
+		ref.Repository = ref.Repository[:i]
+	}
+	// The tag comes after the last colon unless that colon is part of a
+	// registry's port, e.g. "localhost:5000/consul".
+	if i := strings.LastIndex(ref.Repository, ":"); i > strings.LastIndex(ref.Repository, "/") {
+		ref.Tag = ref.Repository[i+1:]
+		ref.Repository = ref.Repository[:i]
+	}
+	return ref
+}
+
+// inventorySecrets returns the secrets configured by the maps in node that
+// have a secretName key, in the order they're in values.yaml.
+func inventorySecrets(node DocNode) []SecretRef {
+	var secrets []SecretRef
+	var walk func(n DocNode)
+	walk = func(n DocNode) {
+		for _, child := range n.Children {
+			if secret, ok := secretRef(child); ok {
+				secrets = append(secrets, secret)
+			}
+			walk(child)
+		}
+	}
+	walk(node)
+	return secrets
+}
+
+// secretRef returns the secret configured by n if it has a secretName key.
+func secretRef(n DocNode) (SecretRef, bool) {
+	var name, key *DocNode
+	for i := range n.Children {
+		switch n.Children[i].Key {
+		case "secretName":
+			name = &n.Children[i]
+		case "secretKey":
+			key = &n.Children[i]
+		}
+	}
+	if name == nil {
+		return SecretRef{}, false
+	}
+
+	secret := SecretRef{
+		Key:           n.Path(),
+		SecretNameKey: name.Path(),
+		SecretName:    secretDefault(*name),
+		Enterprise:    n.Enterprise || name.Enterprise,
+		Description:   firstSentence(n.PlainDocumentation()),
+	}
+	if key != nil {
+		secret.SecretKeyKey = key.Path()
+		secret.SecretKey = secretDefault(*key)
+	}
+	for _, match := range secretDataKey.FindAllStringSubmatch(n.Comment, -1) {
+		if !contains(secret.ExpectedDataKeys, match[1]) {
+			secret.ExpectedDataKeys = append(secret.ExpectedDataKeys, match[1])
+		}
+	}
+	// Otherwise the data key is whatever secretKey is set to.
+	if len(secret.ExpectedDataKeys) == 0 && secret.SecretKey != "" {
+		secret.ExpectedDataKeys = []string{secret.SecretKey}
+	}
+	return secret, true
+}
+
+// secretDefault returns the default of a secretName or secretKey key, or ""
+// if it has none.
+func secretDefault(n DocNode) string {
+	if n.KindTag != "!!str" {
+		return ""
+	}
+	return n.Default
+}
+
+// firstSentence returns the first sentence of doc, joined onto one line.
+func firstSentence(doc string) string {
+	doc = strings.Join(strings.Fields(doc), " ")
+	if i := strings.Index(doc, ". "); i != -1 {
+		return doc[:i+1]
+	}
+	return doc
+}
+
+// contains returns true if list contains s.
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const inventoryChart = `apiVersion: v2
+name: consul
+version: 0.32.1
+appVersion: 1.10.0
+annotations:
+  artifacthub.io/images: |
+    - name: consul
+      image: hashicorp/consul:1.10.0
+`
+
+func TestBuildInventory(t *testing.T) {
+	cases := map[string]struct {
+		Values         string
+		TemplateImages []TemplateImage
+		ExpImages      []ImageRef
+		ExpSecrets     []SecretRef
+		ExpWarnings    []string
+	}{
+		"images": {
+			Values: `---
+global:
+  image: "hashicorp/consul:1.10.0"
+  imageK8S: "localhost:5000/consul-k8s@sha256:abc"
+  # @type: array<map>
+  imagePullSecrets: []
+server:
+  # @type: string
+  image: null
+`,
+			TemplateImages: []TemplateImage{
+				{Default: "global.image", Override: "server.image"},
+				{Default: "global.image", Override: "server.image"},
+				{Default: "global.enabled", Override: "server.enabled"},
+				{Image: "prom/prometheus:v2.24.0", File: "templates/prometheus.yaml", EnabledBy: "prometheus.enabled"},
+			},
+			ExpImages: []ImageRef{
+				{
+					Name:         "consul",
+					Image:        "hashicorp/consul:1.10.0",
+					Repository:   "hashicorp/consul",
+					Tag:          "1.10.0",
+					Key:          "global.image",
+					OverriddenBy: []string{"server.image"},
+				},
+				{
+					Image:      "localhost:5000/consul-k8s@sha256:abc",
+					Repository: "localhost:5000/consul-k8s",
+					Digest:     "sha256:abc",
+					Key:        "global.imageK8S",
+				},
+				{
+					Image:      "prom/prometheus:v2.24.0",
+					Repository: "prom/prometheus",
+					Tag:        "v2.24.0",
+					Template:   "templates/prometheus.yaml",
+					EnabledBy:  "prometheus.enabled",
+				},
+			},
+		},
+		"image in Chart.yaml not used": {
+			Values: `---
+global:
+  image: "hashicorp/consul:1.9.0"
+`,
+			ExpImages: []ImageRef{
+				{
+					Image:      "hashicorp/consul:1.9.0",
+					Repository: "hashicorp/consul",
+					Tag:        "1.9.0",
+					Key:        "global.image",
+				},
+			},
+			ExpWarnings: []string{"image hashicorp/consul:1.10.0 (consul) in Chart.yaml isn't used by the chart"},
+		},
+		"secrets": {
+			Values: `---
+global:
+  image: "hashicorp/consul:1.10.0"
+  # The gossip key. The secret can be created by running:
+  #
+  # ` + "```" + `shell
+  # $ kubectl create secret generic gossip --from-literal=key=$(consul keygen)
+  # ` + "```" + `
+  gossipEncryption:
+    secretName: ""
+    secretKey: ""
+  # The bootstrap token.
+  bootstrapToken:
+    secretName: null
+    secretKey: token
+server:
+  # The server certificate.
+  # @enterprise: true
+  serverCert:
+    # @type: string
+    secretName: null
+`,
+			ExpSecrets: []SecretRef{
+				{
+					Key:              "global.gossipEncryption",
+					SecretNameKey:    "global.gossipEncryption.secretName",
+					SecretKeyKey:     "global.gossipEncryption.secretKey",
+					ExpectedDataKeys: []string{"key"},
+					Description:      "The gossip key.",
+				},
+				{
+					Key:              "global.bootstrapToken",
+					SecretNameKey:    "global.bootstrapToken.secretName",
+					SecretKeyKey:     "global.bootstrapToken.secretKey",
+					SecretKey:        "token",
+					ExpectedDataKeys: []string{"token"},
+					Description:      "The bootstrap token.",
+				},
+				{
+					Key:           "server.serverCert",
+					SecretNameKey: "server.serverCert.secretName",
+					Enterprise:    true,
+					Description:   "The server certificate.",
+				},
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			inv, warnings, err := BuildInventory(c.Values, inventoryChart, c.TemplateImages)
+			require.NoError(t, err)
+			require.Equal(t, ChartInfo{Name: "consul", Version: "0.32.1", AppVersion: "1.10.0"}, inv.Chart)
+			if c.ExpImages != nil {
+				require.Equal(t, c.ExpImages, inv.Images)
+			}
+			require.Equal(t, c.ExpSecrets, inv.Secrets)
+			require.Equal(t, c.ExpWarnings, warnings)
+		})
+	}
+}
+
+func TestFindTemplateImages(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	templatesDir := filepath.Join(dir, "templates")
+	require.NoError(t, os.Mkdir(templatesDir, 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(templatesDir, "prometheus.yaml"), []byte(`{{- if .Values.prometheus.enabled }}
+containers:
+  - image: "prom/prometheus:v2.24.0"
+{{- end }}`), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(templatesDir, "server.yaml"), []byte(`containers:
+  - image: "{{ default .Values.global.image .Values.server.image }}"
+  - image: {{ $root.Values.global.imageEnvoy | quote }}`), 0644))
+
+	images, err := FindTemplateImages(templatesDir)
+	require.NoError(t, err)
+	require.Equal(t, []TemplateImage{
+		{Image: "prom/prometheus:v2.24.0", File: "templates/prometheus.yaml", EnabledBy: "prometheus.enabled"},
+		{Default: "global.image", Override: "server.image"},
+	}, images)
+}
+
+func TestInventory_Formats(t *testing.T) {
+	inv := Inventory{
+		Chart:  ChartInfo{Name: "consul", Version: "0.32.1", AppVersion: "1.10.0"},
+		Images: []ImageRef{{Image: "hashicorp/consul:1.10.0", Repository: "hashicorp/consul", Tag: "1.10.0", Key: "global.image"}},
+	}
+
+	out, err := inv.YAML()
+	require.NoError(t, err)
+	require.Equal(t, `chart:
+  name: consul
+  version: 0.32.1
+  appVersion: 1.10.0
+images:
+  - image: hashicorp/consul:1.10.0
+    repository: hashicorp/consul
+    tag: 1.10.0
+    key: global.image
+secrets: []
+`, out)
+
+	out, err = inv.JSON()
+	require.NoError(t, err)
+	require.Equal(t, `{
+  "chart": {
+    "name": "consul",
+    "version": "0.32.1",
+    "appVersion": "1.10.0"
+  },
+  "images": [
+    {
+      "image": "hashicorp/consul:1.10.0",
+      "repository": "hashicorp/consul",
+      "tag": "1.10.0",
+      "key": "global.image"
+    }
+  ],
+  "secrets": []
+}
+`, out)
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// splitItems splits comment, a YAML head comment, into the comment without
+// its @items block and the block itself, de-commented and un-indented. The
+// block is the lines after a line with only "@items:" that are indented more
+// than it. ok is false if there's no @items block. An error is returned if
+// there's anything in the comment after the block.
+//
+// For example:
+// ```
+// # A list of extra volumes.
+// # @items:
+// #   # The name of the volume.
+// #   # @type: string
+// #   # @required: true
+// #   name: null
+// ```
+// Has the block:
+// ```
+// # The name of the volume.
+// # @type: string
+// # @required: true
+// name: null
+// ```
+func splitItems(comment string) (head string, block string, ok bool, err error) {
+	lines := strings.Split(comment, "\n")
+	start := -1
+	for i, line := range lines {
+		if strings.TrimSpace(commentPrefix.ReplaceAllString(line, "")) == "@items:" {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		return comment, "", false, nil
+	}
+
+	var blockLines []string
+	for _, line := range lines[start+1:] {
+		text := commentPrefix.ReplaceAllString(line, "")
+		if strings.TrimSpace(text) == "" {
+			blockLines = append(blockLines, "")
+			continue
+		}
+		if !strings.HasPrefix(text, "  ") {
+			return "", "", false, fmt.Errorf("@items must be the last annotation, found %q after it", strings.TrimSpace(text))
+		}
+		blockLines = append(blockLines, text)
+	}
+	return strings.Join(lines[:start], "\n"), dedent(strings.Join(blockLines, "\n")), true, nil
+}
+
+// parseItems parses the @items block in the comment of docNode, if it has
+// one, into its children. The block is removed from its comment.
+func parseItems(docNode *DocNode, errs *[]*ParseError) error {
+	head, block, ok, err := splitItems(docNode.Comment)
+	if err != nil {
+		return &ParseError{
+			FullAnchor: docNode.HTMLAnchor(),
+			Path:       docNode.Path(),
+			Line:       docNode.Line,
+			Column:     docNode.Column,
+			Err:        err.Error(),
+		}
+	}
+	if !ok {
+		return nil
+	}
+	// The block is directly above the key so the @items line is the key's
+	// line minus the number of lines from @items to the end of the comment.
+	itemsLines := strings.Count(docNode.Comment, "\n") + 1
+	if head != "" {
+		itemsLines -= strings.Count(head, "\n") + 1
+	}
+	docNode.ItemsLine = docNode.Line - itemsLines
+	docNode.Comment = head
+
+	if kind := docNode.FormattedKind(); kind != "array<map>" {
+		return &ParseError{
+			FullAnchor: docNode.HTMLAnchor(),
+			Path:       docNode.Path(),
+			Line:       docNode.ItemsLine,
+			Column:     docNode.Column,
+			Err:        fmt.Sprintf("@items is only supported for array<map>, not %s", kind),
+		}
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(block), &node); err != nil || len(node.Content) == 0 || node.Content[0].Kind != yaml.MappingNode {
+		msg := "@items must be a YAML map"
+		if err != nil {
+			msg = fmt.Sprintf("@items is invalid: %s", err)
+		}
+		return &ParseError{
+			FullAnchor: docNode.HTMLAnchor(),
+			Path:       docNode.Path(),
+			Line:       docNode.ItemsLine,
+			Column:     docNode.Column,
+			Err:        msg,
+		}
+	}
+
+	// Move the nodes to the lines they're on in the values file so errors
+	// have the right position. The fields are parsed like the elements of an
+	// array<map> default, whose leading indent is their column minus 3, so
+	// they're moved to the column that indents them 2 spaces more than
+	// docNode when the docs are rendered.
+	column := len(docNode.LeadingIndent()) + 2 + 3
+	shiftNodes(node.Content[0], docNode.ItemsLine, column-1)
+	children, err := parseNodeContent(expandMerges(node.Content[0].Content), docNode.HTMLAnchor(), docNode.Path(), true, errs)
+	if err != nil {
+		return err
+	}
+	docNode.Children = children
+	return nil
+}
+
+// shiftNodes adds lines and columns to the position of n and its descendants.
+func shiftNodes(n *yaml.Node, lines int, columns int) {
+	n.Line += lines
+	n.Column += columns
+	for _, child := range n.Content {
+		shiftNodes(child, lines, columns)
+	}
+}
+
+// dedent removes the leading whitespace common to every non-empty line of
+// text.
+func dedent(text string) string {
+	lines := strings.Split(text, "\n")
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lineIndent := len(line) - len(strings.TrimLeft(line, " "))
+		if indent == -1 || lineIndent < indent {
+			indent = lineIndent
+		}
+	}
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			lines[i] = line[indent:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const itemsInput = `---
+server:
+  # A list of extra volumes.
+  # @type: array<map>
+  # @items:
+  #   # Type of the volume.
+  #   # @type: string
+  #   # @enum: configMap, secret
+  #   # @required: true
+  #   type: null
+  #
+  #   # Name of the volume.
+  #   # @type: string
+  #   # @required: true
+  #   name: null
+  #
+  #   # If true, load the config files in the volume.
+  #   load: false
+  extraVolumes: []
+`
+
+func TestItems(t *testing.T) {
+	out, err := GenerateDocs(itemsInput)
+	require.NoError(t, err)
+	require.Equal(t, strings.Replace(`### server
+
+- $server$ ((#v-server))
+
+  - $extraVolumes$ ((#v-server-extravolumes)) ($array<map>$) - A list of extra volumes.
+
+    - $type$ ((#v-server-extravolumes-type)) ($string$) - **Required**. Type of the volume.
+
+       Supported values: $configMap$, $secret$.
+
+    - $name$ ((#v-server-extravolumes-name)) ($string$) - **Required**. Name of the volume.
+
+    - $load$ ((#v-server-extravolumes-load)) ($boolean: false$) - If true, load the config files in the volume.`, "$", "`", -1), out)
+}
+
+func TestItems_Schema(t *testing.T) {
+	schema, err := GenerateSchema(itemsInput, nil)
+	require.NoError(t, err)
+	require.Contains(t, schema, `"extraVolumes": {
+          "type": "array",
+          "description": "A list of extra volumes.",
+          "items": {
+            "type": "object",
+            "properties": {`)
+	require.Contains(t, schema, `"required": [
+              "type",
+              "name"
+            ]`)
+}
+
+func TestItems_Errors(t *testing.T) {
+	cases := map[string]struct {
+		Input string
+		Err   string
+	}{
+		"not last": {
+			Input: `---
+# Docs
+# @items:
+#   name: a
+# @type: array<map>
+key: []
+`,
+			Err: `-key: @items must be the last annotation, found "@type: array<map>" after it`,
+		},
+		"not an array of maps": {
+			Input: `---
+# Docs
+# @type: array<string>
+# @items:
+#   name: a
+key: []
+`,
+			Err: `-key: @items is only supported for array<map>, not array<string>`,
+		},
+		"not a map": {
+			Input: `---
+# Docs
+# @type: array<map>
+# @items:
+#   - a
+key: []
+`,
+			Err: `-key: @items must be a YAML map`,
+		},
+		"invalid field": {
+			Input: `---
+# Docs
+# @type: array<map>
+# @items:
+#   # Name docs
+#   name: null
+key: []
+`,
+			Err: `-key-name: unknown kind '!!null'`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(c.Input)
+			require.EqualError(t, err, c.Err)
+		})
+	}
+}
+
+func TestItems_LintPositions(t *testing.T) {
+	input := `---
+# Docs
+# @type: array<map>
+# @foo: bar
+# @items:
+#   # Name docs
+#   # @required: yes
+#   # @type: string
+#   name: null
+key: []
+`
+	result, err := Lint(input, "values.yaml")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"values.yaml:4:3: error: key: unknown annotation @foo",
+		"values.yaml:7:7: error: key.name: @required must be true or false, got \"yes\"",
+	}, lintStrings(result))
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Severity is how serious a lint problem is.
+type Severity string
+
+const (
+	// SeverityError problems break the generated docs or schema.
+	SeverityError Severity = "error"
+
+	// SeverityWarning problems make the docs worse but don't break them.
+	SeverityWarning Severity = "warning"
+)
+
+var (
+	// annotationLine matches a line in a comment that looks like an annotation,
+	// e.g. "@type: string". It captures the annotation name and the rest of
+	// the line.
+	annotationLine = regexp.MustCompile(`^\s*@([A-Za-z]+)(.*)$`)
+
+	// wellFormedAnnotation matches the rest of an annotation line after its
+	// name when it's in the "@name: value" format.
+	wellFormedAnnotation = regexp.MustCompile(`^: \S`)
+
+	// knownAnnotations are the annotations that the generator understands.
+	knownAnnotations = map[string]bool{
+		"type":          true,
+		"default":       true,
+		"recurse":       true,
+		"enum":          true,
+		"deprecated":    true,
+		"since":         true,
+		"enterprise":    true,
+		"alias":         true,
+		"items":         true,
+		"required":      true,
+		"profile":       true,
+		"defineProfile": true,
+	}
+)
+
+// LintProblem is a single problem with the documentation in values.yaml.
+type LintProblem struct {
+	File     string   `json:"file"`
+	Line     int      `json:"line"`
+	Column   int      `json:"column"`
+	Severity Severity `json:"severity"`
+
+	// Key is the dotted path of the key with the problem and Anchor is its
+	// HTML anchor.
+	Key     string `json:"key"`
+	Anchor  string `json:"anchor"`
+	Message string `json:"message"`
+}
+
+// String formats the problem in the compiler style, e.g.
+// `values.yaml:123:5: error: global.name: unknown kind '!!null'`.
+func (p LintProblem) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s: %s", p.File, p.Line, p.Column, p.Severity, p.Key, p.Message)
+}
+
+// LintResult is the set of problems found by Lint.
+type LintResult struct {
+	Problems []LintProblem `json:"problems"`
+}
+
+// HasErrors returns true if any of the problems are errors rather than
+// warnings.
+func (r LintResult) HasErrors() bool {
+	for _, p := range r.Problems {
+		if p.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// String formats the problems one per line.
+func (r LintResult) String() string {
+	var b strings.Builder
+	for _, p := range r.Problems {
+		b.WriteString(p.String() + "\n")
+	}
+	return b.String()
+}
+
+// JSON formats the problems as indented JSON.
+func (r LintResult) JSON() (string, error) {
+	// Output an empty list rather than null when there are no problems.
+	if r.Problems == nil {
+		r.Problems = []LintProblem{}
+	}
+	out, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out) + "\n", nil
+}
+
+// Lint parses yamlStr and returns every problem with its documentation
+// rather than stopping at the first one. file is the name of the file used
+// in the problems. The returned error is only set if yamlStr isn't valid YAML.
+func Lint(yamlStr string, file string) (LintResult, error) {
+	node, parseErrs, err := ParseAll(yamlStr)
+	if err != nil {
+		return LintResult{}, err
+	}
+
+	var result LintResult
+	for _, parseErr := range parseErrs {
+		result.Problems = append(result.Problems, LintProblem{
+			File:     file,
+			Line:     parseErr.Line,
+			Column:   parseErr.Column,
+			Severity: SeverityError,
+			Key:      parseErr.Path,
+			Anchor:   parseErr.Anchor(),
+			Message:  parseErr.Err,
+		})
+	}
+
+	for _, refErr := range append(CheckReferences(node), CheckExamples(node)...) {
+		result.Problems = append(result.Problems, LintProblem{
+			File:     file,
+			Line:     refErr.Line,
+			Column:   refErr.Column,
+			Severity: SeverityError,
+			Key:      refErr.Path,
+			Anchor:   refErr.Anchor(),
+			Message:  refErr.Err,
+		})
+	}
+
+	fileLines := strings.Split(yamlStr, "\n")
+	var walk func(n DocNode)
+	walk = func(n DocNode) {
+		for _, child := range n.Children {
+			for _, p := range lintNode(child, fileLines) {
+				p.File = file
+				result.Problems = append(result.Problems, p)
+			}
+			walk(child)
+		}
+	}
+	walk(node)
+
+	sortLintProblems(result.Problems)
+	return result, nil
+}
+
+// lintNode returns the problems with a single node's documentation, other than
+// the ones found by DocNode.Validate. fileLines are the lines of the YAML file
+// the node is in.
+func lintNode(n DocNode, fileLines []string) []LintProblem {
+	var problems []LintProblem
+	problem := func(line int, column int, severity Severity, msg string) {
+		problems = append(problems, LintProblem{
+			Line:     line,
+			Column:   column,
+			Severity: severity,
+			Key:      n.Path(),
+			Anchor:   n.HTMLAnchor(),
+			Message:  msg,
+		})
+	}
+
+	commentLines := strings.Split(n.Comment, "\n")
+	if n.Comment == "" {
+		commentLines = nil
+	}
+	lineNumbers := commentLineNumbers(commentLines, fileLines, n.Line)
+	inFence := false
+	for i, rawLine := range commentLines {
+		line := commentPrefix.ReplaceAllString(rawLine, "")
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		match := annotationLine.FindStringSubmatch(line)
+		if len(match) == 0 {
+			continue
+		}
+		// Fall back to the key's position if the line wasn't found, assuming
+		// the comment is indented to the same column as the key.
+		lineNumber := lineNumbers[i]
+		column := n.Column + strings.Index(rawLine, "@")
+		if lineNumber == 0 {
+			lineNumber = n.Line
+		} else {
+			column = strings.Index(fileLines[lineNumber-1], "@") + 1
+		}
+		name, rest := match[1], strings.TrimRight(match[2], " ")
+		switch {
+		case !knownAnnotations[name]:
+			problem(lineNumber, column, SeverityError, fmt.Sprintf("unknown annotation @%s", name))
+		case !wellFormedAnnotation.MatchString(rest):
+			problem(lineNumber, column, SeverityError, fmt.Sprintf("malformed annotation, expected \"@%s: <value>\"", name))
+		case (name == "recurse" || name == "enterprise" || name == "required") && rest != ": true" && rest != ": false":
+			problem(lineNumber, column, SeverityError, fmt.Sprintf("@%s must be true or false, got %q", name, strings.TrimPrefix(rest, ": ")))
+		}
+	}
+
+	// @type and @default use the last value if they're set more than once
+	// but that's most likely a mistake.
+	if len(typeAnnotation.FindAllString(n.Comment, -1)) > 1 {
+		problem(n.Line, n.Column, SeverityWarning, "@type is set more than once, the last value is used")
+	}
+	if len(defaultAnnotation.FindAllString(n.Comment, -1)) > 1 {
+		problem(n.Line, n.Column, SeverityWarning, "@default is set more than once, the last value is used")
+	}
+
+	if len(n.Children) == 0 && n.PlainDocumentation() == "" {
+		problem(n.Line, n.Column, SeverityWarning, "missing description")
+	}
+	return problems
+}
+
+// commentLineNumbers returns the line in fileLines of each of the lines of a
+// comment above the key on keyLine, or 0 for the blank lines. yaml.v3 doesn't
+// keep the position of comments, and a comment can be separated from its key
+// by blank lines and have blank lines or an @items block removed from it, so
+// its lines are matched to the lines of the file from the key upwards. The
+// file's lines only have to end with the comment's lines because the comments
+// of the keys in an @items block are nested in the key's comment.
+func commentLineNumbers(commentLines []string, fileLines []string, keyLine int) []int {
+	numbers := make([]int, len(commentLines))
+	fileLine := keyLine - 1
+	for i := len(commentLines) - 1; i >= 0; i-- {
+		text := strings.TrimSpace(commentLines[i])
+		if text == "" {
+			continue
+		}
+		for fileLine >= 1 && !strings.HasSuffix(strings.TrimSpace(fileLines[fileLine-1]), text) {
+			fileLine--
+		}
+		if fileLine < 1 {
+			break
+		}
+		numbers[i] = fileLine
+		fileLine--
+	}
+	return numbers
+}
+
+// sortLintProblems sorts problems by their position in the file. Problems at
+// the same position stay in the order they were found.
+func sortLintProblems(problems []LintProblem) {
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Line != problems[j].Line {
+			return problems[i].Line < problems[j].Line
+		}
+		return problems[i].Column < problems[j].Column
+	})
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	input := `---
+# Enabled docs
+# @type: string
+# @type: boolean
+enabled: "-"
+# Name docs
+# @tpye: string
+name: null
+# Map docs
+# @recurse: maybe
+# @default:foo
+map:
+  # Key docs
+  # @enterprise: yes
+  key: 1
+  other: value
+`
+	result, err := Lint(input, "values.yaml")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"values.yaml:5:1: warning: enabled: @type is set more than once, the last value is used",
+		"values.yaml:7:3: error: name: unknown annotation @tpye",
+		"values.yaml:8:1: error: name: unknown kind '!!null'",
+		"values.yaml:10:3: error: map: @recurse must be true or false, got \"maybe\"",
+		"values.yaml:11:3: error: map: malformed annotation, expected \"@default: <value>\"",
+		"values.yaml:14:5: error: map.key: @enterprise must be true or false, got \"yes\"",
+		"values.yaml:16:3: warning: map.other: missing description",
+	}, lintStrings(result))
+	require.True(t, result.HasErrors())
+}
+
+func TestLint_References(t *testing.T) {
+	input := `---
+global:
+  # Requires $global.tls.enabled$.
+  enabled: true
+`
+	result, err := Lint(strings.Replace(input, "$", "`", -1), "values.yaml")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"values.yaml:4:3: error: global.enabled: references \"global.tls.enabled\" which doesn't exist",
+	}, lintStrings(result))
+}
+
+func TestLint_WarningsOnly(t *testing.T) {
+	input := `---
+# Map docs
+map:
+  key: value
+`
+	result, err := Lint(input, "values.yaml")
+	require.NoError(t, err)
+	require.Equal(t, []string{"values.yaml:4:3: warning: map.key: missing description"}, lintStrings(result))
+	require.False(t, result.HasErrors())
+}
+
+// Test that problems in comments with blank lines in them or between them and
+// their key are on the right lines.
+func TestLint_CommentsWithBlankLines(t *testing.T) {
+	input := `---
+# Name docs
+# @tpye: string
+
+
+name: value
+# Map docs
+
+# @recurse: maybe
+map:
+  # Key docs
+  key: 1
+`
+	result, err := Lint(input, "values.yaml")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"values.yaml:3:3: error: name: unknown annotation @tpye",
+		"values.yaml:9:3: error: map: @recurse must be true or false, got \"maybe\"",
+	}, lintStrings(result))
+}
+
+func TestLint_IgnoresAnnotationsInCodeFences(t *testing.T) {
+	input := `---
+# Docs
+#
+# $$$text
+# @notAnAnnotation
+# $$$
+key: value
+`
+	result, err := Lint(strings.Replace(input, "$", "`", -1), "values.yaml")
+	require.NoError(t, err)
+	require.Empty(t, result.Problems)
+}
+
+func TestLintResult_JSON(t *testing.T) {
+	out, err := LintResult{}.JSON()
+	require.NoError(t, err)
+	require.Equal(t, "{\n  \"problems\": []\n}\n", out)
+
+	out, err = LintResult{Problems: []LintProblem{{
+		File:     "values.yaml",
+		Line:     2,
+		Column:   3,
+		Severity: SeverityError,
+		Key:      "key",
+		Anchor:   "-key",
+		Message:  "unknown kind '!!null'",
+	}}}.JSON()
+	require.NoError(t, err)
+	require.Equal(t, `{
+  "problems": [
+    {
+      "file": "values.yaml",
+      "line": 2,
+      "column": 3,
+      "severity": "error",
+      "key": "key",
+      "anchor": "-key",
+      "message": "unknown kind '!!null'"
+    }
+  ]
+}
+`, out)
+}
+
+func lintStrings(result LintResult) []string {
+	var out []string
+	for _, p := range result.Problems {
+		out = append(out, p.String())
+	}
+	return out
+}
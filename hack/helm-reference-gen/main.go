@@ -6,18 +6,101 @@ package main
 // Usage: make gen-docs [consul-repo-path] [-validate]
 //        Where [consul-repo-path] is the location of the hashicorp/consul repo. Defaults to ../../../consul.
 //        If -validate is set, the generated docs won't be output anywhere.
-//        This is useful in CI to ensure the generation will succeed.
+//        This is useful in CI to ensure the generation will succeed. It also
+//        checks that values.schema.json, the Go values used by the
+//        acceptance tests and the profiles are up to date and that the
+//        examples in the docs are valid.
+//
+// Usage: make check-anchors
+//        Reports anchors in anchors.lock that the docs no longer have, which
+//        would break published links to them, and exits non-zero if there are
+//        any. New anchors that aren't in it yet are listed but don't fail.
+//        -validate also runs this check.
+//
+// Usage: make update-anchors
+//        Regenerates anchors.lock from values.yaml.
+//
+// Usage: go run ./... [-values=<path>] [-out=<path>|-] [-check]
+//        Reads values.yaml from -values instead of the root of this repo and
+//        writes the docs to -out instead of the Consul repo. If -out is "-",
+//        the docs are printed to stdout. If the -out file has codegen markers,
+//        only the contents between them are replaced.
+//        If -check is set, nothing is written. Instead the docs are compared
+//        against the existing target file and, if it's out of date, a diff is
+//        printed and it exits non-zero.
+//
+// Usage: make gen-schema
+//        Generates the values.schema.json file from values.yaml. Helm uses this
+//        file to validate values at install and upgrade time.
+//
+// Usage: make gen-go-values
+//        Generates test/acceptance/framework/helmvalues/values.go from
+//        values.yaml. It has typed structs for the values so that acceptance
+//        tests can't set keys that don't exist.
+//
+// Usage: make gen-profiles
+//        Generates a values file for each profile in profiles/ from the
+//        @profile annotations in values.yaml, and a README.md comparing them.
+//
+// Usage: make check-drift
+//        Reports keys that the templates use but that aren't documented in
+//        values.yaml and keys documented in values.yaml that no template uses.
+//        Exits non-zero if there are any.
+//
+// Usage: make diff-values from=<ref-or-path> [to=<ref-or-path>] [format=markdown|json]
+//        Reports the changes to values.yaml between two revisions, classified
+//        as breaking or non-breaking. Each revision is either a path to a
+//        values file or a git ref. [to] defaults to the current values.yaml.
+//        If -fail-on-breaking is set, exits non-zero if there are breaking
+//        changes.
+//
+// Usage: make lint-values [format=text|json]
+//        Reports every problem with the documentation in values.yaml, with its
+//        line and column, instead of stopping at the first one. Exits non-zero
+//        if there are any errors. Warnings alone don't fail.
+//
+// Usage: make inventory [format=yaml|json]
+//        Lists the container images the chart may deploy, with the values key
+//        that controls each, and the Kubernetes secrets it may read, with the
+//        values keys that configure them and the data keys they should have.
+//        Images are named after Chart.yaml's artifacthub.io/images annotation.
+//
+// Usage: make coverage [format=table|json] [min=<percent>]
+//        Reports how many of the leaf keys in values.yaml have a description,
+//        a type and a default, per top-level key. If -min-coverage is set,
+//        exits non-zero if the percentage of keys with all three is below it.
+//
+// Usage: make examples [out=<dir>]
+//        Writes each yaml code block in the docs to its own values file in
+//        <dir>, by default hack/helm-reference-gen/examples. The blocks are
+//        wrapped under the path of the key they document. Exits non-zero if
+//        an example sets a key that doesn't exist or has the wrong type. The
+//        examples are also checked by -validate.
+//
+// Usage: make serve [addr=<address>]
+//        Serves a live preview of the docs as HTML, by default on :8080.
+//        values.yaml is watched and the page reloads when it changes. Errors
+//        are shown in the page instead of stopping the server.
+//
+// Usage: go run ./... -format=<mdx|markdown|html|json|search-index> [-template=<path>] [-expand-defaults]
+//        Renders the docs in another format and prints them to stdout instead
+//        of updating the Consul repo. If -template is set, the Go template at
+//        that path is executed for each key instead. If -expand-defaults is
+//        set, defaults that are too big to show inline are rendered as YAML
+//        blocks.
 
 import (
-	"bytes"
 	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
 	"text/template"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -32,6 +115,39 @@ var (
 	// recurseAnnotation matches the @recurse annotation. It captures the value of @recurse.
 	recurseAnnotation = regexp.MustCompile(`(?m).*@recurse: (.*)$`)
 
+	// enumAnnotation matches the @enum annotation. It captures the value of
+	// @enum, a comma separated list of the allowed values.
+	enumAnnotation = regexp.MustCompile(`(?m).*@enum: (.*)$`)
+
+	// deprecatedAnnotation matches the @deprecated annotation. It captures the
+	// value of @deprecated, either the key that replaces this one or "true".
+	deprecatedAnnotation = regexp.MustCompile(`(?m).*@deprecated: (.*)$`)
+
+	// sinceAnnotation matches the @since annotation. It captures the value of
+	// @since, the chart version the key was added in.
+	sinceAnnotation = regexp.MustCompile(`(?m).*@since: (.*)$`)
+
+	// enterpriseAnnotation matches the @enterprise annotation. It captures the
+	// value of @enterprise.
+	enterpriseAnnotation = regexp.MustCompile(`(?m).*@enterprise: (.*)$`)
+
+	// requiredAnnotation matches the @required annotation. It captures the
+	// value of @required.
+	requiredAnnotation = regexp.MustCompile(`(?m).*@required: (.*)$`)
+
+	// aliasAnnotation matches the @alias annotation. It captures the value of
+	// @alias, a comma separated list of the keys' previous paths.
+	aliasAnnotation = regexp.MustCompile(`(?m).*@alias: (.*)$`)
+
+	// profileAnnotation matches the @profile annotation. It captures the value
+	// of @profile, a comma separated list of <profile>=<value>. Unlike other
+	// annotations, it can be set more than once.
+	profileAnnotation = regexp.MustCompile(`(?m).*@profile: (.*)$`)
+
+	// defineProfileAnnotation matches the @defineProfile annotation at the
+	// top of values.yaml. It captures its value, <profile>=<description>.
+	defineProfileAnnotation = regexp.MustCompile(`(?m).*@defineProfile: (.*)$`)
+
 	// commentPrefix matches on the YAML comment prefix, e.g.
 	// ```
 	// # comment here
@@ -53,49 +169,361 @@ var (
 	// We use $ instead of ` in the template so we can use the golang raw string
 	// format. We then do the replace from $ => `.
 	docNodeTmpl = template.Must(
-		template.New("").Parse(
+		template.New("").Funcs(defaultFuncs).Parse(
 			strings.Replace(
 				`{{- if eq .Column 1 }}### {{ .Key }}
 
-{{ end }}{{ .LeadingIndent }}- ${{ .Key }}$ ((#v{{ .HTMLAnchor }})){{ if ne .FormattedKind "" }} (${{ .FormattedKind }}{{ if .FormattedDefault }}: {{ .FormattedDefault }}{{ end }}$){{ end }}{{ if .FormattedDocumentation}} - {{ .FormattedDocumentation }}{{ end }}`,
+{{ end }}{{ .LeadingIndent }}- ${{ .Key }}$ ((#{{ .Anchor }})){{ range .AliasAnchors }} <a id="{{ . }}" />{{ end }}{{ if ne .FormattedKind "" }} (${{ .FormattedKind }}{{ if .FormattedDefault }}: {{ .FormattedDefault }}{{ end }}$){{ end }}{{ if .FormattedDocumentation}} - {{ .FormattedDocumentation }}{{ end }}{{ if expandDefaults }}{{ with .FormattedDefaultBlock true }}
+
+{{ . }}{{ end }}{{ end }}`,
 				"$", "`", -1)),
 	)
 )
 
 func main() {
 	validateFlag := flag.Bool("validate", false, "only validate that the markdown can be generated, don't actually generate anything")
+	schemaFlag := flag.Bool("schema", false, "generate values.schema.json instead of the markdown docs")
+	goValuesFlag := flag.Bool("go-values", false, "generate the Go structs for the values used by the acceptance tests instead of the markdown docs")
+	profilesFlag := flag.Bool("profiles", false, "generate the values files for the profiles from the @profile annotations instead of the markdown docs")
+	driftFlag := flag.Bool("drift", false, "report differences between the keys in values.yaml and the keys used by the templates")
+	diffFlag := flag.String("diff", "", "report the changes to values.yaml since this git ref or values file")
+	diffToFlag := flag.String("diff-to", "", "git ref or values file to compare -diff against, defaults to the current values.yaml")
+	diffFormatFlag := flag.String("diff-format", "markdown", "format of the -diff report, one of markdown or json")
+	failOnBreakingFlag := flag.Bool("fail-on-breaking", false, "exit non-zero if -diff finds breaking changes")
+	lintFlag := flag.Bool("lint", false, "report every problem with the documentation in values.yaml")
+	lintFormatFlag := flag.String("lint-format", "text", "format of the -lint report, one of text or json")
+	formatFlag := flag.String("format", "mdx", fmt.Sprintf("format to render the docs in, one of: %s", strings.Join(RendererFormats(), ", ")))
+	expandDefaultsFlag := flag.Bool("expand-defaults", false, "render defaults that are too big to show inline, e.g. maps and multi-line strings, as YAML blocks")
+	templateFlag := flag.String("template", "", "path to a Go template that is executed for each key to render the docs in a custom format")
+	valuesFlag := flag.String("values", "../../values.yaml", "path to the values.yaml file to document")
+	outFlag := flag.String("out", "", "file to write the docs to instead of the Consul repo, or - for stdout")
+	checkFlag := flag.Bool("check", false, "don't write the docs, exit non-zero with a diff if the target file is out of date")
+	inventoryFlag := flag.Bool("inventory", false, "list the images and secrets the chart may use")
+	inventoryFormatFlag := flag.String("inventory-format", "yaml", "format of the -inventory report, one of yaml or json")
+	coverageFlag := flag.Bool("coverage", false, "report how much of values.yaml is documented")
+	coverageFormatFlag := flag.String("coverage-format", "table", "format of the -coverage report, one of table or json")
+	minCoverageFlag := flag.Float64("min-coverage", 0, "exit non-zero if the percentage of fully documented keys is below this, implies -coverage")
+	examplesFlag := flag.String("examples", "", "write each yaml example in the docs to its own values file in this directory")
+	serveFlag := flag.String("serve", "", "serve a live preview of the docs as HTML on this address, e.g. :8080")
+	anchorsFlag := flag.Bool("anchors", false, "report anchors in anchors.lock that were removed from the docs")
+	updateAnchorsFlag := flag.Bool("update-anchors", false, "regenerate anchors.lock from values.yaml")
 	consulRepoPath := "../../../consul"
+	schemaPath := "../../values.schema.json"
+	goValuesPath := "../../test/acceptance/framework/helmvalues/values.go"
+	profilesPath := "../../profiles"
+	anchorsPath := "anchors.lock"
+	templatesPath := "../../templates"
+	chartPath := "../../Chart.yaml"
 	flag.Parse()
+	valuesPath := *valuesFlag
 
-	if len(os.Args) > 3 {
+	if flag.NArg() > 1 {
 		fmt.Println("Error: extra arguments")
 		os.Exit(1)
 	}
 
-	if !*validateFlag {
+	// Unless -out is set, formats other than the consul.io MDX are printed to
+	// stdout.
+	toStdout := *outFlag == "-" || (*outFlag == "" && (*formatFlag != "mdx" || *templateFlag != ""))
+
+	if !*validateFlag && !*schemaFlag && !*goValuesFlag && !*profilesFlag && !*driftFlag && *diffFlag == "" && !*lintFlag && !*inventoryFlag && !*coverageFlag && *minCoverageFlag == 0 && *examplesFlag == "" && *serveFlag == "" && !*anchorsFlag && !*updateAnchorsFlag && !toStdout && *outFlag == "" {
 		// Only argument is path to Consul repo. If not set then we default.
-		if len(os.Args) < 2 {
+		if flag.NArg() == 0 {
 			abs, _ := filepath.Abs(consulRepoPath)
 			fmt.Printf("Defaulting to Consul repo path: %s\n", abs)
 		} else {
 			// Support absolute and relative paths to the Consul repo.
-			if filepath.IsAbs(os.Args[1]) {
-				consulRepoPath = os.Args[1]
+			if filepath.IsAbs(flag.Arg(0)) {
+				consulRepoPath = flag.Arg(0)
 			} else {
-				consulRepoPath = filepath.Join("../..", os.Args[1])
+				consulRepoPath = filepath.Join("../..", flag.Arg(0))
 			}
 			abs, _ := filepath.Abs(consulRepoPath)
 			fmt.Printf("Using Consul repo path: %s\n", abs)
 		}
 	}
 
+	if *serveFlag != "" {
+		server, err := NewPreviewServer(valuesPath)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		go server.Watch(500*time.Millisecond, nil)
+		fmt.Printf("Serving a preview of %s on %s\n", valuesPath, *serveFlag)
+		if err := http.ListenAndServe(*serveFlag, server); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// Parse the values.yaml file.
-	inputBytes, err := ioutil.ReadFile("../../values.yaml")
+	inputBytes, err := ioutil.ReadFile(valuesPath)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	if *lintFlag {
+		result, err := Lint(string(inputBytes), valuesPath)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		switch *lintFormatFlag {
+		case "text":
+			fmt.Print(result.String())
+		case "json":
+			out, err := result.JSON()
+			if err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+			fmt.Print(out)
+		default:
+			fmt.Printf("Error: unknown -lint-format %q\n", *lintFormatFlag)
+			os.Exit(1)
+		}
+		if result.HasErrors() {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if *diffFlag != "" {
+		// Git refs are read relative to the root of this repo.
+		oldValues, err := readValuesRevision(*diffFlag, "../..", "values.yaml")
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		newValues := string(inputBytes)
+		if *diffToFlag != "" {
+			newValues, err = readValuesRevision(*diffToFlag, "../..", "values.yaml")
+			if err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+		}
+		diff, err := DiffValues(oldValues, newValues)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		switch *diffFormatFlag {
+		case "markdown":
+			fmt.Print(diff.Markdown())
+		case "json":
+			out, err := diff.JSON()
+			if err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+			fmt.Print(out)
+		default:
+			fmt.Printf("Error: unknown -diff-format %q\n", *diffFormatFlag)
+			os.Exit(1)
+		}
+		if *failOnBreakingFlag && diff.HasBreaking() {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if *driftFlag {
+		refs, err := FindValuesReferences(templatesPath)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		report, err := DetectDrift(string(inputBytes), valuesPath, refs, undocumentedKeys)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		if report.HasDrift() {
+			fmt.Print(report.String())
+			os.Exit(1)
+		}
+		fmt.Println("No drift between values.yaml and templates")
+		os.Exit(0)
+	}
+
+	if *inventoryFlag {
+		chartBytes, err := ioutil.ReadFile(chartPath)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		templateImages, err := FindTemplateImages(templatesPath)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		inv, warnings, err := BuildInventory(string(inputBytes), string(chartBytes), templateImages)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		for _, warning := range warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+		}
+		var out string
+		switch *inventoryFormatFlag {
+		case "yaml":
+			out, err = inv.YAML()
+		case "json":
+			out, err = inv.JSON()
+		default:
+			err = fmt.Errorf("unknown -inventory-format %q, must be one of: yaml, json", *inventoryFormatFlag)
+		}
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		fmt.Print(out)
+		os.Exit(0)
+	}
+
+	if *coverageFlag || *minCoverageFlag != 0 {
+		report, err := Coverage(string(inputBytes))
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		switch *coverageFormatFlag {
+		case "table":
+			fmt.Print(report.Table())
+		case "json":
+			out, err := report.JSON()
+			if err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+			fmt.Print(out)
+		default:
+			fmt.Printf("Error: unknown -coverage-format %q\n", *coverageFormatFlag)
+			os.Exit(1)
+		}
+		if report.Total.Coverage < *minCoverageFlag {
+			fmt.Fprintf(os.Stderr, "Error: coverage %.1f%% is below the minimum of %.1f%%\n", report.Total.Coverage, *minCoverageFlag)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if *examplesFlag != "" {
+		node, err := Parse(string(inputBytes))
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		examples, errs := FindExamples(node)
+		if len(errs) > 0 {
+			for _, err := range errs {
+				fmt.Println(err.Error())
+			}
+			os.Exit(1)
+		}
+		if err := WriteExamples(*examplesFlag, examples); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		abs, _ := filepath.Abs(*examplesFlag)
+		fmt.Printf("Wrote %d examples to %s\n", len(examples), abs)
+		os.Exit(0)
+	}
+
+	if *anchorsFlag {
+		lockBytes, err := ioutil.ReadFile(anchorsPath)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		report, err := CheckAnchors(string(inputBytes), string(lockBytes))
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		// The report also lists the new anchors, which don't fail the check.
+		fmt.Print(report.String())
+		if !report.OK() {
+			os.Exit(1)
+		}
+		fmt.Println("All anchors in anchors.lock are in the docs")
+		os.Exit(0)
+	}
+
+	if *updateAnchorsFlag {
+		lock, err := GenerateAnchorLock(string(inputBytes))
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		err = ioutil.WriteFile(anchorsPath, []byte(lock), 0644)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		abs, _ := filepath.Abs(anchorsPath)
+		fmt.Printf("Updated with generated anchors: %s\n", abs)
+		os.Exit(0)
+	}
+
+	if *schemaFlag {
+		schema, err := GenerateSchema(string(inputBytes), undocumentedKeys)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		err = ioutil.WriteFile(schemaPath, []byte(schema), 0644)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		abs, _ := filepath.Abs(schemaPath)
+		fmt.Printf("Updated with generated schema: %s\n", abs)
+		os.Exit(0)
+	}
+
+	if *goValuesFlag {
+		goValues, err := GenerateGoValues(string(inputBytes))
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		err = ioutil.WriteFile(goValuesPath, []byte(goValues), 0644)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		abs, _ := filepath.Abs(goValuesPath)
+		fmt.Printf("Updated with generated Go values: %s\n", abs)
+		os.Exit(0)
+	}
+
+	if *profilesFlag {
+		files, err := GenerateProfiles(string(inputBytes))
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		if err := WriteProfiles(profilesPath, files); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		abs, _ := filepath.Abs(profilesPath)
+		fmt.Printf("Updated with generated profiles: %s\n", abs)
+		os.Exit(0)
+	}
+
+	renderer, err := NewRenderer(*formatFlag, *templateFlag, RendererOptions{ExpandDefaults: *expandDefaultsFlag})
 	if err != nil {
 		fmt.Println(err.Error())
 		os.Exit(1)
 	}
-	out, err := GenerateDocs(string(inputBytes))
+	out, err := RenderDocs(string(inputBytes), renderer)
 	if err != nil {
 		fmt.Println(err.Error())
 		os.Exit(1)
@@ -103,35 +531,98 @@ func main() {
 
 	// If we're just validating that generation will succeed then we're done.
 	if *validateFlag {
+		schemaBytes, err := ioutil.ReadFile(schemaPath)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		if err := ValidateSchema(string(inputBytes), undocumentedKeys, string(schemaBytes)); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		goValuesBytes, err := ioutil.ReadFile(goValuesPath)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		if err := ValidateGoValues(string(inputBytes), string(goValuesBytes)); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		if err := ValidateProfiles(string(inputBytes), profilesPath); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		lockBytes, err := ioutil.ReadFile(anchorsPath)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		report, err := CheckAnchors(string(inputBytes), string(lockBytes))
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		if !report.OK() {
+			fmt.Print(report.String())
+			os.Exit(1)
+		}
+		node, err := Parse(string(inputBytes))
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		if errs := CheckExamples(node); len(errs) > 0 {
+			for _, err := range errs {
+				fmt.Println(err.Error())
+			}
+			os.Exit(1)
+		}
 		fmt.Println("Validation successful")
 		os.Exit(0)
 	}
 
-	// Otherwise we'll go on to write the changes to the helm docs.
-	helmReferenceFile := filepath.Join(consulRepoPath, "website/content/docs/k8s/helm.mdx")
+	if toStdout {
+		if *checkFlag {
+			fmt.Println("Error: -check needs a file to compare against, set -out")
+			os.Exit(1)
+		}
+		fmt.Print(out)
+		os.Exit(0)
+	}
+
+	// Otherwise we'll go on to write the changes to the helm docs. The
+	// helm.mdx file in the Consul repo must have the codegen markers but
+	// other files are replaced completely if they don't.
+	helmReferenceFile := *outFlag
+	requireMarkers := false
+	if helmReferenceFile == "" {
+		helmReferenceFile = filepath.Join(consulRepoPath, "website/content/docs/k8s/helm.mdx")
+		requireMarkers = true
+	}
 	helmReferenceBytes, err := ioutil.ReadFile(helmReferenceFile)
-	if err != nil {
+	if err != nil && (requireMarkers || !os.IsNotExist(err)) {
 		fmt.Println(err.Error())
 		os.Exit(1)
 	}
 	helmReferenceContents := string(helmReferenceBytes)
-
-	// Swap out the contents between the codegen markers.
-	startStr := "<!-- codegen: start -->\n\n"
-	endStr := "\n  <!-- codegen: end -->"
-	start := strings.Index(helmReferenceContents, startStr)
-	if start == -1 {
-		fmt.Printf("%q not found in %q\n", startStr, helmReferenceFile)
+	newContents, err := UpdateTarget(helmReferenceContents, out, requireMarkers)
+	if err != nil {
+		fmt.Printf("%s in %q\n", err, helmReferenceFile)
 		os.Exit(1)
 	}
-	end := strings.Index(helmReferenceContents, endStr)
-	if end == -1 {
-		fmt.Printf("%q not found in %q\n", endStr, helmReferenceFile)
-		os.Exit(1)
+
+	if *checkFlag {
+		if diff := LineDiff(helmReferenceContents, newContents, helmReferenceFile, "generated"); diff != "" {
+			fmt.Print(diff)
+			fmt.Printf("%s is out of date, regenerate it without -check\n", helmReferenceFile)
+			os.Exit(1)
+		}
+		fmt.Printf("%s is up to date\n", helmReferenceFile)
+		os.Exit(0)
 	}
 
-	newMdx := helmReferenceContents[0:start+len(startStr)] + out + helmReferenceContents[end:]
-	err = ioutil.WriteFile(helmReferenceFile, []byte(newMdx), 0644)
+	err = ioutil.WriteFile(helmReferenceFile, []byte(newContents), 0644)
 	if err != nil {
 		fmt.Println(err.Error())
 		os.Exit(1)
@@ -140,39 +631,258 @@ func main() {
 	fmt.Printf("Updated with generated docs: %s\n", abs)
 }
 
+// GenerateDocs parses yamlStr and renders it as the MDX used on consul.io.
 func GenerateDocs(yamlStr string) (string, error) {
-	node, err := Parse(yamlStr)
-	if err != nil {
-		return "", err
-	}
-
-	children, err := generateDocsFromNode(docNodeTmpl, node)
-	return strings.ReplaceAll(strings.Join(children, "\n\n"), "[Enterprise Only]", "<EnterpriseAlert inline />"), err
+	return RenderDocs(yamlStr, MDXRenderer{})
 }
 
-// Parse parses yamlStr into a tree of DocNode's.
+// Parse parses yamlStr into a tree of DocNode's. It returns the first error
+// found.
 func Parse(yamlStr string) (DocNode, error) {
-	var node yaml.Node
-	err := yaml.Unmarshal([]byte(yamlStr), &node)
-	if err != nil {
-		return DocNode{}, err
+	return parse(yamlStr, nil)
+}
+
+// ParseAll parses yamlStr into a tree of DocNode's like Parse, but instead of
+// stopping at the first invalid node it returns every error found. Nodes that
+// fail validation are still included in the tree. The returned error is only
+// set if yamlStr isn't valid YAML.
+func ParseAll(yamlStr string) (DocNode, []*ParseError, error) {
+	var errs []*ParseError
+	node, err := parse(yamlStr, &errs)
+	return node, errs, err
+}
+
+// parse parses yamlStr into a tree of DocNode's. If errs is nil, it returns
+// the first error found. Otherwise, errors are appended to errs and parsing
+// continues.
+func parse(yamlStr string, errs *[]*ParseError) (DocNode, error) {
+	// yamlStr can have multiple documents. Their keys are combined as if
+	// they were in a single document.
+	var rootNode []*yaml.Node
+	var headComments []string
+	seen := make(map[string]bool)
+	decoder := yaml.NewDecoder(strings.NewReader(yamlStr))
+	for docIdx := 1; ; docIdx++ {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return DocNode{}, err
+		}
+
+		// Due to how the YAML is parsed this is the first real node. Empty
+		// documents don't have one or it's null.
+		if len(node.Content) == 0 || node.Content[0].Tag == "!!null" {
+			continue
+		}
+		if node.Content[0].Kind != yaml.MappingNode {
+			return DocNode{}, fmt.Errorf("document %d is not a map", docIdx)
+		}
+		content := expandMerges(node.Content[0].Content)
+		for i := 0; i < len(content); i += 2 {
+			if seen[content[i].Value] {
+				return DocNode{}, fmt.Errorf("key %q is in more than one document", content[i].Value)
+			}
+			seen[content[i].Value] = true
+		}
+		rootNode = append(rootNode, content...)
+		if node.HeadComment != "" {
+			headComments = append(headComments, node.HeadComment)
+		}
 	}
 
-	// Due to how the YAML is parsed this is the first real node.
-	rootNode := node.Content[0].Content
-	children, err := parseNodeContent(rootNode, "", false)
+	children, err := parseNodeContent(rootNode, "", "", false, errs)
 	if err != nil {
 		return DocNode{}, err
 	}
-	return DocNode{
-		Column:   0,
-		Children: children,
-	}, nil
+	root := DocNode{
+		Column:      0,
+		Children:    children,
+		HeadComment: strings.Join(headComments, "\n"),
+	}
+	resolveAliasPaths(&root)
+	return root, nil
+}
+
+// resolveAlias returns the node that n is an alias of, e.g. the node with
+// &anchor if n is *anchor, or n if it isn't an alias.
+func resolveAlias(n *yaml.Node) *yaml.Node {
+	for n.Kind == yaml.AliasNode && n.Alias != nil {
+		n = n.Alias
+	}
+	return n
+}
+
+// resolveAliases returns content with each alias replaced by the node it's
+// an alias of.
+func resolveAliases(content []*yaml.Node) []*yaml.Node {
+	resolved := make([]*yaml.Node, len(content))
+	for i, n := range content {
+		resolved[i] = resolveAlias(n)
+	}
+	return resolved
+}
+
+// expandAlias returns a copy of the node that n is an alias of whose content
+// is moved to column, or n if it isn't an alias. The anchored node keeps the
+// columns of where it's defined, which may be at a different depth than the
+// alias, so without moving it its keys would be documented at the wrong
+// indentation.
+func expandAlias(n *yaml.Node, column int) *yaml.Node {
+	target := resolveAlias(n)
+	if target == n {
+		return n
+	}
+	expanded := copyNode(target)
+	if len(expanded.Content) > 0 {
+		shiftNodes(expanded, 0, column-expanded.Content[0].Column)
+	}
+	return expanded
+}
+
+// expandAliases returns content, the elements of a list, with each alias
+// expanded at the column of the alias, which is where the element's content
+// would start if it was written out in full.
+func expandAliases(content []*yaml.Node) []*yaml.Node {
+	expanded := make([]*yaml.Node, len(content))
+	for i, n := range content {
+		expanded[i] = expandAlias(n, n.Column)
+	}
+	return expanded
+}
+
+// contentColumn returns the column that the content of value starts at when
+// it's the value of a key at keyColumn, e.g. 3 for `key:\n  child: 1` and 5
+// for `key:\n  - child: 1`.
+func contentColumn(keyColumn int, value *yaml.Node) int {
+	if resolveAlias(value).Kind == yaml.SequenceNode {
+		return keyColumn + 4
+	}
+	return keyColumn + 2
+}
+
+// copyNode returns a deep copy of n. Aliases in n aren't followed so they
+// still refer to the nodes with their anchors.
+func copyNode(n *yaml.Node) *yaml.Node {
+	copied := *n
+	copied.Content = make([]*yaml.Node, len(n.Content))
+	for i, child := range n.Content {
+		copied.Content[i] = copyNode(child)
+	}
+	return &copied
+}
+
+// expandMerges returns the content of a map with any merge keys, e.g.
+// `<<: *anchor`, replaced by the keys of the maps they merge. Keys that are
+// set in the map itself take precedence over merged keys.
+func expandMerges(content []*yaml.Node) []*yaml.Node {
+	hasMerge := false
+	keys := make(map[string]bool)
+	for i := 0; i+1 < len(content); i += 2 {
+		if content[i].Tag == "!!merge" {
+			hasMerge = true
+		} else {
+			keys[content[i].Value] = true
+		}
+	}
+	if !hasMerge {
+		return content
+	}
+
+	var expanded []*yaml.Node
+	for i := 0; i+1 < len(content); i += 2 {
+		if content[i].Tag != "!!merge" {
+			expanded = append(expanded, content[i], content[i+1])
+			continue
+		}
+		// The value is either a single map or a list of maps. Their keys are
+		// merged at the column of the merge key.
+		merged := []*yaml.Node{content[i+1]}
+		if resolveAlias(merged[0]).Kind == yaml.SequenceNode {
+			merged = resolveAlias(merged[0]).Content
+		}
+		for _, m := range merged {
+			m = expandAlias(m, content[i].Column)
+			if m.Kind != yaml.MappingNode {
+				continue
+			}
+			mContent := expandMerges(m.Content)
+			for j := 0; j+1 < len(mContent); j += 2 {
+				if !keys[mContent[j].Value] {
+					keys[mContent[j].Value] = true
+					expanded = append(expanded, mContent[j], mContent[j+1])
+				}
+			}
+		}
+	}
+	return expanded
+}
+
+// setYAMLDetails sets the fields of docNode that come from its key node and
+// value node other than the ones set by buildDocNode: its line and foot
+// comments and its YAML anchor or alias.
+func setYAMLDetails(docNode *DocNode, key *yaml.Node, value *yaml.Node) {
+	// Line comments on scalars are on the value, e.g. `key: value # docs`,
+	// but on maps and lists they're on the key, e.g. `key: # docs`.
+	docNode.LineComment = key.LineComment
+	if docNode.LineComment == "" {
+		docNode.LineComment = value.LineComment
+	}
+	docNode.FootComment = key.FootComment
+	if value.Kind == yaml.AliasNode {
+		docNode.YAMLAlias = value.Value
+		return
+	}
+	docNode.YAMLAnchor = value.Anchor
+	// List elements aren't documented themselves so an alias used as an
+	// element, e.g. `key: [*base]`, is documented on the list.
+	if value.Kind == yaml.SequenceNode {
+		for _, element := range value.Content {
+			if element.Kind == yaml.AliasNode {
+				docNode.YAMLAlias = element.Value
+				docNode.AliasIsElement = true
+				return
+			}
+		}
+	}
+}
+
+// resolveAliasPaths sets AliasOf for each node in the tree rooted at root
+// whose value is a YAML alias to the path of the node with the anchor.
+func resolveAliasPaths(root *DocNode) {
+	anchorPaths := make(map[string]string)
+	var collect func(n DocNode)
+	collect = func(n DocNode) {
+		for _, child := range n.Children {
+			// Anchors must be defined before they're used so the first node
+			// with the anchor is the one that defines it.
+			if _, ok := anchorPaths[child.YAMLAnchor]; child.YAMLAnchor != "" && !ok {
+				anchorPaths[child.YAMLAnchor] = child.Path()
+			}
+			collect(child)
+		}
+	}
+	collect(*root)
+
+	var set func(n *DocNode)
+	set = func(n *DocNode) {
+		for i := range n.Children {
+			child := &n.Children[i]
+			if child.YAMLAlias != "" {
+				child.AliasOf = anchorPaths[child.YAMLAlias]
+			}
+			set(child)
+		}
+	}
+	set(root)
 }
 
 // parseNodeContent recursively parses the yaml nodes and outputs a DocNode
-// tree.
-func parseNodeContent(nodeContent []*yaml.Node, parentBreadcrumb string, parentWasMap bool) ([]DocNode, error) {
+// tree. If errs is non-nil, errors are appended to it instead of being
+// returned.
+func parseNodeContent(nodeContent []*yaml.Node, parentBreadcrumb string, parentPath string, parentWasMap bool, errs *[]*ParseError) ([]DocNode, error) {
 	var docNodes []DocNode
 
 	// This is a special type of node where it's an array of maps.
@@ -188,7 +898,7 @@ func parseNodeContent(nodeContent []*yaml.Node, parentBreadcrumb string, parentW
 	//
 	// To do that, we actually need to skip the map node.
 	if len(nodeContent) == 1 {
-		return parseNodeContent(nodeContent[0].Content, parentBreadcrumb, true)
+		return parseNodeContent(expandMerges(nodeContent[0].Content), parentBreadcrumb, parentPath, true, errs)
 	}
 
 	// skipNext is true if we should skip the next node. Due to how the YAML is
@@ -203,16 +913,39 @@ func parseNodeContent(nodeContent []*yaml.Node, parentBreadcrumb string, parentW
 			continue
 		}
 
-		docNode, err := buildDocNode(i, child, nodeContent, parentBreadcrumb, parentWasMap)
+		docNode, err := buildDocNode(i, child, nodeContent, parentBreadcrumb, parentPath, parentWasMap, errs)
 		if err != nil {
-			return nil, err
+			parseErr, ok := err.(*ParseError)
+			if errs == nil || !ok {
+				return nil, err
+			}
+			// Skip this node since we couldn't build it.
+			*errs = append(*errs, parseErr)
+			skipNext = true
+			continue
+		}
+		if err := parseItems(&docNode, errs); err != nil {
+			parseErr, ok := err.(*ParseError)
+			if errs == nil || !ok {
+				return nil, err
+			}
+			*errs = append(*errs, parseErr)
 		}
+		setYAMLDetails(&docNode, child, nodeContent[i+1])
+		docNode.parseAnnotations()
 
 		if err := docNode.Validate(); err != nil {
-			return nil, &ParseError{
+			parseErr := &ParseError{
 				FullAnchor: docNode.HTMLAnchor(),
+				Path:       docNode.Path(),
+				Line:       docNode.Line,
+				Column:     docNode.Column,
 				Err:        err.Error(),
 			}
+			if errs == nil {
+				return nil, parseErr
+			}
+			*errs = append(*errs, parseErr)
 		}
 
 		docNodes = append(docNodes, docNode)
@@ -222,23 +955,6 @@ func parseNodeContent(nodeContent []*yaml.Node, parentBreadcrumb string, parentW
 	return docNodes, nil
 }
 
-func generateDocsFromNode(tm *template.Template, node DocNode) ([]string, error) {
-	var out []string
-	for _, child := range node.Children {
-		var nodeOut bytes.Buffer
-		err := tm.Execute(&nodeOut, child)
-		if err != nil {
-			return nil, err
-		}
-		childOut, err := generateDocsFromNode(tm, child)
-		if err != nil {
-			return nil, err
-		}
-		out = append(append(out, nodeOut.String()), childOut...)
-	}
-	return out, nil
-}
-
 // allScalars returns true if content contains only scalar nodes
 // with no chidren.
 func allScalars(content []*yaml.Node) bool {
@@ -250,6 +966,15 @@ func allScalars(content []*yaml.Node) bool {
 	return true
 }
 
+// elementKindTags returns the YAML kind tag of each node in content.
+func elementKindTags(content []*yaml.Node) []string {
+	var tags []string
+	for _, n := range content {
+		tags = append(tags, n.Tag)
+	}
+	return tags
+}
+
 // toInlineYaml will return the yaml string representation for content
 // using the inline representation, i.e. `["a", "b"]`
 // instead of:
@@ -276,13 +1001,15 @@ func toInlineYaml(content []*yaml.Node) (string, error) {
 	return strings.TrimPrefix(string(out), "arr: "), nil
 }
 
-func buildDocNode(nodeContentIdx int, currNode *yaml.Node, nodeContent []*yaml.Node, parentBreadcrumb string, parentWasMap bool) (DocNode, error) {
+func buildDocNode(nodeContentIdx int, currNode *yaml.Node, nodeContent []*yaml.Node, parentBreadcrumb string, parentPath string, parentWasMap bool, errs *[]*ParseError) (DocNode, error) {
 	// Check for the @recurse: false annotation.
 	// In this case we construct our node and then don't recurse further.
 	if match := recurseAnnotation.FindStringSubmatch(currNode.HeadComment); len(match) > 0 && match[1] == "false" {
 		return DocNode{
 			Column:           currNode.Column,
+			Line:             currNode.Line,
 			ParentBreadcrumb: parentBreadcrumb,
+			ParentPath:       parentPath,
 			ParentWasMap:     false,
 			Key:              currNode.Value,
 			Comment:          currNode.HeadComment,
@@ -294,11 +1021,16 @@ func buildDocNode(nodeContentIdx int, currNode *yaml.Node, nodeContent []*yaml.N
 		return DocNode{}, &ParseError{
 			ParentAnchor: parentBreadcrumb,
 			CurrAnchor:   currNode.Value,
+			Path:         joinPath(parentPath, currNode.Value),
+			Line:         currNode.Line,
+			Column:       currNode.Column,
 			Err:          fmt.Sprintf("content length incorrect, expected %d got %d", nodeContentIdx+1, len(nodeContent)),
 		}
 	}
 
-	next := nodeContent[nodeContentIdx+1]
+	// If the value is an alias, e.g. *anchor, we document the value it's an
+	// alias of as if it was written out in full here.
+	next := expandAlias(nodeContent[nodeContentIdx+1], contentColumn(currNode.Column, nodeContent[nodeContentIdx+1]))
 
 	switch next.Kind {
 
@@ -306,8 +1038,10 @@ func buildDocNode(nodeContentIdx int, currNode *yaml.Node, nodeContent []*yaml.N
 	case yaml.ScalarNode:
 		return DocNode{
 			ParentBreadcrumb: parentBreadcrumb,
+			ParentPath:       parentPath,
 			ParentWasMap:     parentWasMap,
 			Column:           currNode.Column,
+			Line:             currNode.Line,
 			Key:              currNode.Value,
 			Comment:          currNode.HeadComment,
 			KindTag:          next.Tag,
@@ -318,14 +1052,27 @@ func buildDocNode(nodeContentIdx int, currNode *yaml.Node, nodeContent []*yaml.N
 	case yaml.MappingNode:
 		docNode := DocNode{
 			ParentBreadcrumb: parentBreadcrumb,
+			ParentPath:       parentPath,
 			ParentWasMap:     parentWasMap,
 			Column:           currNode.Column,
+			Line:             currNode.Line,
 			Key:              currNode.Value,
 			Comment:          currNode.HeadComment,
 			KindTag:          next.Tag,
 		}
 		var err error
-		docNode.Children, err = parseNodeContent(next.Content, docNode.HTMLAnchor(), false)
+		docNode.DefaultYAML, err = defaultYAML(next)
+		if err != nil {
+			return DocNode{}, &ParseError{
+				ParentAnchor: parentBreadcrumb,
+				CurrAnchor:   currNode.Value,
+				Path:         joinPath(parentPath, currNode.Value),
+				Line:         currNode.Line,
+				Column:       currNode.Column,
+				Err:          err.Error(),
+			}
+		}
+		docNode.Children, err = parseNodeContent(expandMerges(next.Content), docNode.HTMLAnchor(), docNode.Path(), false, errs)
 		if err != nil {
 			return DocNode{}, err
 		}
@@ -334,12 +1081,15 @@ func buildDocNode(nodeContentIdx int, currNode *yaml.Node, nodeContent []*yaml.N
 	// If it's a sequence, i.e. array, then we have to handle it differently
 	// depending on its contents.
 	case yaml.SequenceNode:
+		content := expandAliases(next.Content)
 		// If it's empty then its just a key with a default of empty array.
-		if len(next.Content) == 0 {
+		if len(content) == 0 {
 			return DocNode{
 				ParentBreadcrumb: parentBreadcrumb,
+				ParentPath:       parentPath,
 				ParentWasMap:     parentWasMap,
 				Column:           currNode.Column,
+				Line:             currNode.Line,
 				Key:              currNode.Value,
 				// Default is empty array.
 				Default: "[]",
@@ -349,43 +1099,70 @@ func buildDocNode(nodeContentIdx int, currNode *yaml.Node, nodeContent []*yaml.N
 
 			// If it's full of scalars, e.g. key: [a, b] then we can stop recursing
 			// and use the value as the default.
-		} else if allScalars(next.Content) {
-			inlineYaml, err := toInlineYaml(next.Content)
+		} else if allScalars(content) {
+			inlineYaml, err := toInlineYaml(content)
 			if err != nil {
 				return DocNode{}, &ParseError{
 					ParentAnchor: parentBreadcrumb,
 					CurrAnchor:   currNode.Value,
+					Path:         joinPath(parentPath, currNode.Value),
+					Line:         currNode.Line,
+					Column:       currNode.Column,
 					Err:          err.Error(),
 				}
 			}
 			return DocNode{
 				ParentBreadcrumb: parentBreadcrumb,
+				ParentPath:       parentPath,
 				ParentWasMap:     parentWasMap,
 				Column:           currNode.Column,
+				Line:             currNode.Line,
 				Key:              currNode.Value,
 				// Default will be the yaml value.
-				Default: inlineYaml,
-				Comment: currNode.HeadComment,
-				KindTag: next.Tag,
+				Default:         inlineYaml,
+				Comment:         currNode.HeadComment,
+				KindTag:         next.Tag,
+				ElementKindTags: elementKindTags(content),
 			}, nil
 		} else {
 
 			// Otherwise we need to recurse into each element of the array.
 			docNode := DocNode{
 				ParentBreadcrumb: parentBreadcrumb,
+				ParentPath:       parentPath,
 				ParentWasMap:     parentWasMap,
 				Column:           currNode.Column,
+				Line:             currNode.Line,
 				Key:              currNode.Value,
 				Comment:          currNode.HeadComment,
 				KindTag:          next.Tag,
+				ElementKindTags:  elementKindTags(content),
 			}
 			var err error
-			docNode.Children, err = parseNodeContent(next.Content, docNode.HTMLAnchor(), false)
+			docNode.DefaultYAML, err = defaultYAML(next)
+			if err != nil {
+				return DocNode{}, &ParseError{
+					ParentAnchor: parentBreadcrumb,
+					CurrAnchor:   currNode.Value,
+					Path:         joinPath(parentPath, currNode.Value),
+					Line:         currNode.Line,
+					Column:       currNode.Column,
+					Err:          err.Error(),
+				}
+			}
+			docNode.Children, err = parseNodeContent(content, docNode.HTMLAnchor(), docNode.Path(), false, errs)
 			if err != nil {
 				return DocNode{}, err
 			}
 			return docNode, nil
 		}
 	}
-	return DocNode{}, fmt.Errorf("fell through cases unexpectedly at breadcrumb: %s", parentBreadcrumb)
+	return DocNode{}, &ParseError{
+		ParentAnchor: parentBreadcrumb,
+		CurrAnchor:   currNode.Value,
+		Path:         joinPath(parentPath, currNode.Value),
+		Line:         currNode.Line,
+		Column:       currNode.Column,
+		Err:          "fell through cases unexpectedly",
+	}
 }
@@ -278,6 +278,297 @@ key: value
 			Exp: `### key
 
 - $key$ ((#v-key)) ($string: value$) - <EnterpriseAlert inline /> line 1\n  line 2`,
+		},
+		"enum": {
+			Input: `---
+# Log level.
+# @enum: debug, info, warn
+logLevel: info
+`,
+			Exp: `### logLevel
+
+- $logLevel$ ((#v-loglevel)) ($string: info$) - Log level.\n\n  Supported values: $debug$, $info$, $warn$.`,
+		},
+		"deprecated": {
+			Input: `---
+# Old docs.
+# @deprecated: true
+old: value
+# Older docs.
+# @deprecated: global.new
+older: value
+global:
+  # New docs.
+  new: value
+`,
+			Exp: `### old
+
+- $old$ ((#v-old)) ($string: value$) - **Deprecated**. Old docs.
+
+### older
+
+- $older$ ((#v-older)) ($string: value$) - **Deprecated**: use [$global.new$](#v-global-new) instead. Older docs.
+
+### global
+
+- $global$ ((#v-global))
+
+  - $new$ ((#v-global-new)) ($string: value$) - New docs.`,
+		},
+		"since": {
+			Input: `---
+# New docs.
+# @since: 0.30.0
+key: value
+`,
+			Exp: `### key
+
+- $key$ ((#v-key)) ($string: value$) - New docs.\n\n  Added in chart version $0.30.0$.`,
+		},
+		"enterprise annotation": {
+			Input: `---
+# Namespace docs.
+# @enterprise: true
+key: value
+`,
+			Exp: `### key
+
+- $key$ ((#v-key)) ($string: value$) - <EnterpriseAlert inline /> Namespace docs.`,
+		},
+		"alias": {
+			Input: `---
+# Key docs.
+# @alias: oldKey, global.key
+key: value
+`,
+			Exp: `### key
+
+- $key$ ((#v-key)) <a id="v-oldkey" /> <a id="v-global-key" /> ($string: value$) - Key docs.`,
+		},
+		"line comment": {
+			Input: `---
+key: value # Line docs
+# Head docs
+other: value # more docs
+map: # Map docs
+  nested: 1 # Nested docs
+`,
+			Exp: `### key
+
+- $key$ ((#v-key)) ($string: value$) - Line docs
+
+### other
+
+- $other$ ((#v-other)) ($string: value$) - Head docs
+  more docs
+
+### map
+
+- $map$ ((#v-map)) - Map docs
+
+  - $nested$ ((#v-map-nested)) ($integer: 1$) - Nested docs`,
+		},
+		"foot comment": {
+			Input: `---
+# Head docs
+key: value
+# Foot docs
+
+other: value
+`,
+			Exp: `### key
+
+- $key$ ((#v-key)) ($string: value$) - Head docs
+
+  Foot docs
+
+### other
+
+- $other$ ((#v-other)) ($string: value$)`,
+		},
+		"yaml alias": {
+			Input: `---
+# Base docs
+base: &base
+  # Image docs
+  image: consul
+# Name docs
+name: &name consul
+# Copy docs
+copy: *base
+# Other name docs
+otherName: *name
+`,
+			Exp: `### base
+
+- $base$ ((#v-base)) - Base docs
+
+  - $image$ ((#v-base-image)) ($string: consul$) - Image docs
+
+### name
+
+- $name$ ((#v-name)) ($string: consul$) - Name docs
+
+### copy
+
+- $copy$ ((#v-copy)) - Copy docs
+
+  Defaults to the value of $base$ via the YAML alias $*base$.
+
+  - $image$ ((#v-copy-image)) ($string: consul$) - Image docs
+
+### otherName
+
+- $otherName$ ((#v-othername)) ($string: consul$) - Other name docs
+
+  Defaults to the value of $name$ via the YAML alias $*name$.`,
+		},
+		"yaml alias of anchor not on a key": {
+			Input: `---
+# @type: array<string>
+list: [&first a, b]
+# Key docs
+key: *first
+`,
+			Exp: `### list
+
+- $list$ ((#v-list)) ($array<string>: [&first a, b]$)
+
+### key
+
+- $key$ ((#v-key)) ($string: a$) - Key docs
+
+  Defaults to the value of the YAML anchor $&first$.`,
+		},
+		"yaml alias deeper than its anchor": {
+			Input: `---
+# Base docs
+base: &base
+  # Port docs
+  port: 1
+# Outer docs
+outer:
+  # Inner docs
+  inner: *base
+  # Merged docs
+  merged:
+    <<: *base
+`,
+			Exp: `### base
+
+- $base$ ((#v-base)) - Base docs
+
+  - $port$ ((#v-base-port)) ($integer: 1$) - Port docs
+
+### outer
+
+- $outer$ ((#v-outer)) - Outer docs
+
+  - $inner$ ((#v-outer-inner)) - Inner docs
+
+    Defaults to the value of $base$ via the YAML alias $*base$.
+
+    - $port$ ((#v-outer-inner-port)) ($integer: 1$) - Port docs
+
+  - $merged$ ((#v-outer-merged)) - Merged docs
+
+    - $port$ ((#v-outer-merged-port)) ($integer: 1$) - Port docs`,
+		},
+		"yaml alias shallower than its anchor": {
+			Input: `---
+outer:
+  # Inner docs
+  inner: &inner
+    # Port docs
+    port: 1
+# Copy docs
+copy: *inner
+`,
+			Exp: `### outer
+
+- $outer$ ((#v-outer))
+
+  - $inner$ ((#v-outer-inner)) - Inner docs
+
+    - $port$ ((#v-outer-inner-port)) ($integer: 1$) - Port docs
+
+### copy
+
+- $copy$ ((#v-copy)) - Copy docs
+
+  Defaults to the value of [$outer.inner$](#v-outer-inner) via the YAML alias $*inner$.
+
+  - $port$ ((#v-copy-port)) ($integer: 1$) - Port docs`,
+		},
+		"yaml alias as a list element": {
+			Input: `---
+# Base docs
+base: &base
+  # Port docs
+  port: 1
+# Gateways docs
+# @type: array<map>
+gateways:
+  - *base
+`,
+			Exp: `### base
+
+- $base$ ((#v-base)) - Base docs
+
+  - $port$ ((#v-base-port)) ($integer: 1$) - Port docs
+
+### gateways
+
+- $gateways$ ((#v-gateways)) ($array<map>$) - Gateways docs
+
+  Its elements include the value of $base$ via the YAML alias $*base$.
+
+  - $port$ ((#v-gateways-port)) ($integer: 1$) - Port docs`,
+		},
+		"merge keys": {
+			Input: `---
+defaults: &defaults
+  # Replicas docs
+  replicas: 1
+  # Image docs
+  image: consul
+gateway:
+  <<: *defaults
+  # Override docs
+  replicas: 2
+`,
+			Exp: `### defaults
+
+- $defaults$ ((#v-defaults))
+
+  - $replicas$ ((#v-defaults-replicas)) ($integer: 1$) - Replicas docs
+
+  - $image$ ((#v-defaults-image)) ($string: consul$) - Image docs
+
+### gateway
+
+- $gateway$ ((#v-gateway))
+
+  - $image$ ((#v-gateway-image)) ($string: consul$) - Image docs
+
+  - $replicas$ ((#v-gateway-replicas)) ($integer: 2$) - Override docs`,
+		},
+		"multiple documents": {
+			Input: `---
+# First docs
+first: 1
+---
+---
+# Second docs
+second: 2
+`,
+			Exp: `### first
+
+- $first$ ((#v-first)) ($integer: 1$) - First docs
+
+### second
+
+- $second$ ((#v-second)) ($integer: 2$) - Second docs`,
 		},
 		"yaml comments in examples": {
 			Input: `---
@@ -373,6 +664,114 @@ key:
 	}
 }
 
+func TestParse_MultipleDocumentErrors(t *testing.T) {
+	_, err := Parse("---\nkey: 1\n---\nkey: 2\n")
+	require.EqualError(t, err, `key "key" is in more than one document`)
+
+	_, err = Parse("---\nkey: 1\n---\n- a\n")
+	require.EqualError(t, err, "document 2 is not a map")
+
+	node, err := Parse("")
+	require.NoError(t, err)
+	require.Empty(t, node.Children)
+}
+
+func TestEnumValidation(t *testing.T) {
+	_, err := GenerateDocs(`---
+# Log level.
+# @enum: debug, info
+logLevel: trace
+`)
+	require.EqualError(t, err, `-loglevel: default "trace" is not one of the @enum values: debug, info`)
+}
+
+func TestAnnotationValidation(t *testing.T) {
+	cases := map[string]struct {
+		Input string
+		Err   string
+	}{
+		"type matches": {
+			Input: `---
+# @type: integer
+replicas: 3
+# @type: boolean
+# @default: global.enabled
+enabled: "-"
+# @type: array<string>
+hosts: [a, b]
+# @type: string
+name: null
+`,
+		},
+		"type mismatch": {
+			Input: `---
+# @type: integer
+replicas: "3"
+`,
+			Err: `-replicas: @type is integer but the default "3" is a string`,
+		},
+		"type mismatch with map": {
+			Input: `---
+# @type: string
+affinity: {}
+`,
+			Err: `-affinity: @type is string but the default is a map`,
+		},
+		"array element mismatch": {
+			Input: `---
+# @type: array<string>
+hosts: [a, 1]
+`,
+			Err: `-hosts: @type is array<string> but element 1 is an integer`,
+		},
+		"array of maps element mismatch": {
+			Input: `---
+# @type: array<map>
+gateways:
+  - name: a
+  - b
+`,
+			Err: `-gateways: @type is array<map> but element 1 is a string`,
+		},
+		"default not parseable": {
+			Input: `---
+# @type: integer
+# @default: three
+replicas: null
+`,
+			Err: `-replicas: @default "three" is not a valid integer`,
+		},
+		"default disagrees": {
+			Input: `---
+# @default: 5
+replicas: 3
+`,
+			Err: `-replicas: @default "5" doesn't match the default "3"`,
+		},
+		"array default element mismatch": {
+			Input: `---
+# @type: array<map>
+# @default: [{port: 8080}, 8443]
+# @recurse: false
+ports:
+- port: 8080
+`,
+			Err: `-ports: @default "[{port: 8080}, 8443]": @type is array<map> but element 1 is an integer`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := GenerateDocs(c.Input)
+			if c.Err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, c.Err)
+			}
+		})
+	}
+}
+
 // Test against a full values file and compare against a golden file.
 func TestFullValues(t *testing.T) {
 	inputBytes, err := ioutil.ReadFile(filepath.Join("fixtures", "full-values.yaml"))
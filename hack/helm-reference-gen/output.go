@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// codegenStart and codegenEnd mark where the generated docs go in
+	// the helm.mdx file in the Consul repo.
+	codegenStart = "<!-- codegen: start -->\n\n"
+	codegenEnd   = "\n  <!-- codegen: end -->"
+
+	// diffContext is the number of unchanged lines shown around each change
+	// in a diff.
+	diffContext = 3
+)
+
+// UpdateTarget returns the new contents of a file whose current contents are
+// existing after the generated docs out are written to it. If existing has
+// codegen markers, only the contents between them are replaced. Otherwise the
+// whole file is replaced, unless requireMarkers is set in which case an error
+// is returned.
+func UpdateTarget(existing string, out string, requireMarkers bool) (string, error) {
+	start := strings.Index(existing, codegenStart)
+	if start == -1 {
+		if requireMarkers {
+			return "", fmt.Errorf("%q not found", codegenStart)
+		}
+		if !strings.HasSuffix(out, "\n") {
+			out += "\n"
+		}
+		return out, nil
+	}
+	end := strings.Index(existing, codegenEnd)
+	if end == -1 {
+		return "", fmt.Errorf("%q not found", codegenEnd)
+	}
+	return existing[0:start+len(codegenStart)] + out + existing[end:], nil
+}
+
+// LineDiff returns a unified diff from a to b, labelled with the names
+// aName and bName. It returns an empty string if a and b are equal.
+func LineDiff(a, b, aName, bName string) string {
+	if a == b {
+		return ""
+	}
+	aLines := strings.Split(a, "\n")
+	bLines := strings.Split(b, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of
+	// aLines[i:] and bLines[j:].
+	lcs := make([][]int, len(aLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bLines)+1)
+	}
+	for i := len(aLines) - 1; i >= 0; i-- {
+		for j := len(bLines) - 1; j >= 0; j-- {
+			if aLines[i] == bLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	// Walk the table to build the list of edits. Each edit is a line
+	// prefixed with ' ', '-' or '+'.
+	type edit struct {
+		op   byte
+		line string
+		// aIdx and bIdx are the line numbers in a and b before this edit.
+		aIdx, bIdx int
+	}
+	var edits []edit
+	i, j := 0, 0
+	for i < len(aLines) || j < len(bLines) {
+		switch {
+		case i < len(aLines) && j < len(bLines) && aLines[i] == bLines[j]:
+			edits = append(edits, edit{' ', aLines[i], i, j})
+			i++
+			j++
+		case j == len(bLines) || (i < len(aLines) && lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, edit{'-', aLines[i], i, j})
+			i++
+		default:
+			edits = append(edits, edit{'+', bLines[j], i, j})
+			j++
+		}
+	}
+
+	// Group the edits into hunks with diffContext lines of context.
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", aName, bName)
+	for k := 0; k < len(edits); {
+		if edits[k].op == ' ' {
+			k++
+			continue
+		}
+		start := k - diffContext
+		if start < 0 {
+			start = 0
+		}
+		// Extend the hunk to the last change that's within 2*diffContext
+		// unchanged lines of the previous one.
+		last := k
+		for next := k + 1; next < len(edits) && next-last <= 2*diffContext+1; next++ {
+			if edits[next].op != ' ' {
+				last = next
+			}
+		}
+		end := last + 1 + diffContext
+		if end > len(edits) {
+			end = len(edits)
+		}
+
+		var aCount, bCount int
+		for _, e := range edits[start:end] {
+			if e.op != '+' {
+				aCount++
+			}
+			if e.op != '-' {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", edits[start].aIdx+1, aCount, edits[start].bIdx+1, bCount)
+		for _, e := range edits[start:end] {
+			fmt.Fprintf(&out, "%c%s\n", e.op, e.line)
+		}
+		k = end
+	}
+	return out.String()
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateTarget(t *testing.T) {
+	cases := map[string]struct {
+		Existing       string
+		RequireMarkers bool
+		Exp            string
+		Err            string
+	}{
+		"replaces between markers": {
+			Existing: "# Helm\n\n<!-- codegen: start -->\n\nold docs\n  <!-- codegen: end -->\n\nfooter\n",
+			Exp:      "# Helm\n\n<!-- codegen: start -->\n\nnew docs\n  <!-- codegen: end -->\n\nfooter\n",
+		},
+		"replaces whole file without markers": {
+			Existing: "old docs\n",
+			Exp:      "new docs\n",
+		},
+		"new file": {
+			Existing: "",
+			Exp:      "new docs\n",
+		},
+		"markers required": {
+			Existing:       "old docs\n",
+			RequireMarkers: true,
+			Err:            `"<!-- codegen: start -->\n\n" not found`,
+		},
+		"missing end marker": {
+			Existing: "<!-- codegen: start -->\n\nold docs\n",
+			Err:      `"\n  <!-- codegen: end -->" not found`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			out, err := UpdateTarget(c.Existing, "new docs", c.RequireMarkers)
+			if c.Err != "" {
+				require.EqualError(t, err, c.Err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.Exp, out)
+		})
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	require.Equal(t, "", LineDiff("a\nb\n", "a\nb\n", "old", "new"))
+
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n16\n17\n18\n19\n20\n"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n16\n17\n18\n20\n"
+	require.Equal(t, `--- old
++++ new
+@@ -1,6 +1,6 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+ 6
+@@ -16,6 +16,5 @@
+ 16
+ 17
+ 18
+-19
+ 20
+ 
+`, LineDiff(a, b, "old", "new"))
+}
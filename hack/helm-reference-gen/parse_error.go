@@ -8,13 +8,25 @@ type ParseError struct {
 	ParentAnchor string
 	CurrAnchor   string
 	FullAnchor   string
-	Err          string
+
+	// Path is the dotted path of the node, e.g. "global.tls.enabled".
+	Path string
+
+	// Line and Column are the position of the node's key in the YAML file.
+	Line   int
+	Column int
+
+	Err string
 }
 
 func (p *ParseError) Error() string {
-	anchor := p.FullAnchor
-	if anchor == "" {
-		anchor = fmt.Sprintf("%s-%s", p.ParentAnchor, p.CurrAnchor)
+	return fmt.Sprintf("%s: %s", p.Anchor(), p.Err)
+}
+
+// Anchor returns the HTML anchor of the node that failed to parse.
+func (p *ParseError) Anchor() string {
+	if p.FullAnchor == "" {
+		return fmt.Sprintf("%s-%s", p.ParentAnchor, p.CurrAnchor)
 	}
-	return fmt.Sprintf("%s: %s", anchor, p.Err)
+	return p.FullAnchor
 }
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// profilesHeader is the start of each generated file.
+const profilesHeader = `Code generated by helm-reference-gen from the @profile annotations in
+values.yaml. DO NOT EDIT. Regenerate with: make gen-profiles`
+
+// Profile is a preset of values for a common topology. Profiles are defined
+// by @defineProfile annotations at the top of values.yaml and keys are added
+// to them with the @profile annotation.
+type Profile struct {
+	Name        string
+	Description string
+}
+
+// parseProfileDefinitions returns the profiles defined by the
+// @defineProfile annotations in comment, in the order they're defined.
+func parseProfileDefinitions(comment string) ([]Profile, error) {
+	var profiles []Profile
+	for _, match := range defineProfileAnnotation.FindAllStringSubmatch(comment, -1) {
+		parts := strings.SplitN(strings.TrimSpace(match[1]), "=", 2)
+		if len(parts) != 2 || parts[0] == "" || strings.TrimSpace(parts[1]) == "" {
+			return nil, fmt.Errorf("@defineProfile %q must be <profile>=<description>", strings.TrimSpace(match[1]))
+		}
+		if contains(profileNames(profiles), parts[0]) {
+			return nil, fmt.Errorf("@defineProfile %q is defined more than once", parts[0])
+		}
+		profiles = append(profiles, Profile{Name: parts[0], Description: strings.TrimSpace(parts[1])})
+	}
+	return profiles, nil
+}
+
+// profileNames returns the names of profiles.
+func profileNames(profiles []Profile) []string {
+	var names []string
+	for _, p := range profiles {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// parseProfiles returns the values from the @profile annotations in comment
+// keyed by profile name, e.g. {"production": "true"} for
+// `@profile: production=true`. An error is returned if an annotation is
+// malformed. Whether the profiles are defined is checked by
+// GenerateProfiles since they're defined at the top of values.yaml.
+func parseProfiles(comment string) (map[string]string, error) {
+	var values map[string]string
+	for _, match := range profileAnnotation.FindAllStringSubmatch(comment, -1) {
+		for _, entry := range strings.Split(match[1], ",") {
+			parts := strings.SplitN(strings.TrimSpace(entry), "=", 2)
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				return nil, fmt.Errorf("@profile %q must be <profile>=<value>", strings.TrimSpace(entry))
+			}
+			name := parts[0]
+			if _, ok := values[name]; ok {
+				return nil, fmt.Errorf("@profile %q is set more than once", name)
+			}
+			if values == nil {
+				values = make(map[string]string)
+			}
+			values[name] = parts[1]
+		}
+	}
+	return values, nil
+}
+
+// validateProfiles returns an error if this node's @profile annotations are
+// malformed, set a value that doesn't match its type or set its default,
+// which would be a no-op.
+func (n DocNode) validateProfiles() error {
+	values, err := parseProfiles(n.Comment)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value, err := profileValue(values[name])
+		if err != nil {
+			return fmt.Errorf("@profile %s: %s", name, err)
+		}
+		if msgs := checkExampleValue(n, value, n.Path()); len(msgs) > 0 {
+			return fmt.Errorf("@profile %s: %s", name, msgs[0])
+		}
+		if value.Kind == yaml.ScalarNode && value.ShortTag() == n.KindTag && value.Value == n.Default {
+			return fmt.Errorf("@profile %s: sets %q to its default %s", name, n.Key, values[name])
+		}
+	}
+	return nil
+}
+
+// profileValue parses the value of a key in a profile.
+func profileValue(v string) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(v), &doc); err != nil {
+		return nil, fmt.Errorf("value %q isn't valid YAML: %s", v, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, fmt.Errorf("value %q is empty", v)
+	}
+	return doc.Content[0], nil
+}
+
+// GenerateProfiles returns the files for the profiles keyed by their names:
+// a values file for each profile, e.g. "dev.yaml", with the keys in the
+// profile, and a README.md comparing the profiles.
+func GenerateProfiles(yamlStr string) (map[string]string, error) {
+	node, err := Parse(yamlStr)
+	if err != nil {
+		return nil, err
+	}
+	profiles, err := parseProfileDefinitions(node.HeadComment)
+	if err != nil {
+		return nil, err
+	}
+
+	// keys are the keys that are in at least one profile, in the order
+	// they're in values.yaml.
+	var keys []DocNode
+	var walk func(n DocNode, inArray bool) error
+	walk = func(n DocNode, inArray bool) error {
+		for _, child := range n.Children {
+			if len(child.Profiles) > 0 {
+				// The fields of @items are set per element so they can't be
+				// set by a profile.
+				if inArray {
+					return fmt.Errorf("%s: @profile can't be used on the fields of @items", child.Path())
+				}
+				for name := range child.Profiles {
+					if !contains(profileNames(profiles), name) {
+						return fmt.Errorf("%s: unknown @profile %q, must be one of: %s", child.Path(), name, strings.Join(profileNames(profiles), ", "))
+					}
+				}
+				keys = append(keys, child)
+			}
+			if err := walk(child, inArray || strings.HasPrefix(child.FormattedKind(), "array<")); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(node, false); err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]string)
+	for _, p := range profiles {
+		values := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, key := range keys {
+			v, ok := key.Profiles[p.Name]
+			if !ok {
+				continue
+			}
+			value, err := profileValue(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s", key.Path(), err)
+			}
+			setPath(values, strings.Split(key.Path(), "."), value)
+		}
+
+		var out bytes.Buffer
+		lines := append(strings.Split(profilesHeader, "\n"), "")
+		for _, line := range append(lines, wrapWords(p.Description, 78)...) {
+			out.WriteString(strings.TrimRight("# "+line, " ") + "\n")
+		}
+		out.WriteString("#\n")
+		fmt.Fprintf(&out, "# Use with: helm install consul hashicorp/consul --values %s.yaml\n", p.Name)
+		enc := yaml.NewEncoder(&out)
+		enc.SetIndent(2)
+		if err := enc.Encode(values); err != nil {
+			return nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+		files[p.Name+".yaml"] = out.String()
+	}
+	files["README.md"] = profilesReadme(profiles, keys)
+	return files, nil
+}
+
+// wrapWords splits text into lines of at most width characters, breaking
+// between words. Words longer than width are put on their own line.
+func wrapWords(text string, width int) []string {
+	var lines []string
+	var line string
+	for _, word := range strings.Fields(text) {
+		switch {
+		case line == "":
+			line = word
+		case len(line)+1+len(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// setPath sets the key at path in the map m to value, creating the maps
+// along the path that don't exist.
+func setPath(m *yaml.Node, path []string, value *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == path[0] {
+			if len(path) == 1 {
+				m.Content[i+1] = value
+			} else {
+				setPath(m.Content[i+1], path[1:], value)
+			}
+			return
+		}
+	}
+	child := value
+	if len(path) > 1 {
+		child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		setPath(child, path[1:], value)
+	}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: path[0]}, child)
+}
+
+// profilesReadme returns a markdown page describing profiles with a table
+// comparing the values of keys in them.
+func profilesReadme(profiles []Profile, keys []DocNode) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<!-- %s -->\n\n", profilesHeader)
+	b.WriteString("# Values Profiles\n\n")
+	b.WriteString("Each profile is a values file with the values to set for a common topology.\n")
+	b.WriteString("Keys that aren't in a profile keep their default.\n\n")
+	for _, p := range profiles {
+		fmt.Fprintf(&b, "- [`%s`](%s.yaml): %s\n", p.Name, p.Name, p.Description)
+	}
+
+	b.WriteString("\n| Key | Default |")
+	for _, p := range profiles {
+		fmt.Fprintf(&b, " `%s` |", p.Name)
+	}
+	b.WriteString("\n| --- | --- |")
+	b.WriteString(strings.Repeat(" --- |", len(profiles)))
+	b.WriteString("\n")
+	for _, key := range keys {
+		fmt.Fprintf(&b, "| `%s` | %s |", key.Path(), markdownCell(key.FormattedDefault()))
+		for _, p := range profiles {
+			fmt.Fprintf(&b, " %s |", markdownCell(key.Profiles[p.Name]))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// markdownCell formats v as inline code for a markdown table cell. Empty
+// values are left empty.
+func markdownCell(v string) string {
+	if v == "" {
+		return ""
+	}
+	return "`" + strings.ReplaceAll(v, "|", `\|`) + "`"
+}
+
+// WriteProfiles writes the files generated by GenerateProfiles to dir,
+// creating dir if it doesn't exist. Files in dir that aren't generated, e.g.
+// the values file of a profile that was removed, are deleted.
+func WriteProfiles(dir string, files map[string]string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	stale, err := staleProfileFiles(dir, files)
+	if err != nil {
+		return err
+	}
+	for _, name := range stale {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
+	for name, contents := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// staleProfileFiles returns the sorted names of the files in dir that aren't
+// in files.
+func staleProfileFiles(dir string, files map[string]string) ([]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var stale []string
+	for _, entry := range entries {
+		if _, ok := files[entry.Name()]; !ok && !entry.IsDir() {
+			stale = append(stale, entry.Name())
+		}
+	}
+	sort.Strings(stale)
+	return stale, nil
+}
+
+// ValidateProfiles returns an error if the files in dir don't match the
+// files generated from yamlStr, including if dir has files that aren't
+// generated.
+func ValidateProfiles(yamlStr string, dir string) error {
+	files, err := GenerateProfiles(yamlStr)
+	if err != nil {
+		return err
+	}
+	stale, err := staleProfileFiles(dir, files)
+	if err != nil {
+		return err
+	}
+	if len(stale) > 0 {
+		return fmt.Errorf("profiles/%s isn't generated from values.yaml, delete it by regenerating the profiles with: make gen-profiles", stale[0])
+	}
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		existing, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil || string(existing) != files[name] {
+			return fmt.Errorf("profiles/%s is out of date, regenerate it with: make gen-profiles", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProfiles(t *testing.T) {
+	cases := map[string]struct {
+		Comment string
+		Exp     map[string]string
+		ExpErr  string
+	}{
+		"none": {
+			Comment: "# Docs.",
+			Exp:     nil,
+		},
+		"one": {
+			Comment: "# @profile: production=3",
+			Exp:     map[string]string{"production": "3"},
+		},
+		"list": {
+			Comment: "# @profile: dev=1, production=3",
+			Exp:     map[string]string{"dev": "1", "production": "3"},
+		},
+		"more than one annotation": {
+			Comment: "# @profile: dev=1\n# @profile: production=\"a=b\"",
+			Exp:     map[string]string{"dev": "1", "production": `"a=b"`},
+		},
+		"malformed": {
+			Comment: "# @profile: dev",
+			ExpErr:  `@profile "dev" must be <profile>=<value>`,
+		},
+		"missing value": {
+			Comment: "# @profile: dev=",
+			ExpErr:  `@profile "dev=" must be <profile>=<value>`,
+		},
+		"set more than once": {
+			Comment: "# @profile: dev=1\n# @profile: dev=2",
+			ExpErr:  `@profile "dev" is set more than once`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			values, err := parseProfiles(c.Comment)
+			if c.ExpErr != "" {
+				require.EqualError(t, err, c.ExpErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.Exp, values)
+		})
+	}
+}
+
+func TestParse_InvalidProfiles(t *testing.T) {
+	cases := map[string]struct {
+		Input  string
+		ExpErr string
+	}{
+		"wrong type": {
+			Input: `---
+# @profile: dev=yes please
+enabled: false
+`,
+			ExpErr: `-enabled: @profile dev: sets "enabled" to a string but its type is boolean`,
+		},
+		"invalid YAML": {
+			Input: `---
+# @profile: dev=[
+enabled: false
+`,
+			ExpErr: `-enabled: @profile dev: value "[" isn't valid YAML: yaml: line 1: did not find expected node content`,
+		},
+		"sets the default": {
+			Input: `---
+# @profile: dev=false
+enabled: false
+`,
+			ExpErr: `-enabled: @profile dev: sets "enabled" to its default false`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(c.Input)
+			require.EqualError(t, err, c.ExpErr)
+		})
+	}
+}
+
+func TestParseProfileDefinitions(t *testing.T) {
+	cases := map[string]struct {
+		Comment string
+		Exp     []Profile
+		ExpErr  string
+	}{
+		"none": {
+			Comment: "# Docs.",
+			Exp:     nil,
+		},
+		"in order": {
+			Comment: "# Docs.\n# @defineProfile: production=Three servers.\n# @defineProfile: dev=One server, with the UI.",
+			Exp: []Profile{
+				{Name: "production", Description: "Three servers."},
+				{Name: "dev", Description: "One server, with the UI."},
+			},
+		},
+		"malformed": {
+			Comment: "# @defineProfile: dev",
+			ExpErr:  `@defineProfile "dev" must be <profile>=<description>`,
+		},
+		"defined more than once": {
+			Comment: "# @defineProfile: dev=One server.\n# @defineProfile: dev=Two servers.",
+			ExpErr:  `@defineProfile "dev" is defined more than once`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			profiles, err := parseProfileDefinitions(c.Comment)
+			if c.ExpErr != "" {
+				require.EqualError(t, err, c.ExpErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.Exp, profiles)
+		})
+	}
+}
+
+func TestGenerateProfiles(t *testing.T) {
+	input := `# @defineProfile: dev=A single server with the UI, Connect and the controller enabled for trying out Consul, e.g. on kind or minikube.
+# @defineProfile: production=Three servers with TLS, auto-encrypt and ACLs enabled.
+# @defineProfile: tproxy=Transparent proxy.
+
+server:
+  # The number of servers.
+  # @profile: dev=1, production=5
+  replicas: 3
+
+  # Whether to use TLS.
+  # @profile: production=true
+  tls: false
+
+ui:
+  # @type: string
+  # @profile: dev="LoadBalancer"
+  type: null
+`
+	files, err := GenerateProfiles(input)
+	require.NoError(t, err)
+	require.Len(t, files, 4)
+	require.Equal(t, `# Code generated by helm-reference-gen from the @profile annotations in
+# values.yaml. DO NOT EDIT. Regenerate with: make gen-profiles
+#
+# A single server with the UI, Connect and the controller enabled for trying out
+# Consul, e.g. on kind or minikube.
+#
+# Use with: helm install consul hashicorp/consul --values dev.yaml
+server:
+  replicas: 1
+ui:
+  type: "LoadBalancer"
+`, files["dev.yaml"])
+	require.Contains(t, files["tproxy.yaml"], "# Use with: helm install consul hashicorp/consul --values tproxy.yaml\n{}\n")
+	require.Contains(t, files["README.md"], "- [`production`](production.yaml): Three servers with TLS, auto-encrypt and ACLs enabled.\n")
+	require.Contains(t, files["README.md"], "| Key | Default | `dev` | `production` | `tproxy` |\n"+
+		"| --- | --- | --- | --- | --- |\n"+
+		"| `server.replicas` | `3` | `1` | `5` |  |\n"+
+		"| `server.tls` | `false` |  | `true` |  |\n"+
+		"| `ui.type` | `null` | `\"LoadBalancer\"` |  |  |\n")
+}
+
+func TestGenerateProfiles_UnknownProfile(t *testing.T) {
+	input := `# @defineProfile: dev=A single server.
+
+# @profile: staging=true
+enabled: false
+`
+	_, err := GenerateProfiles(input)
+	require.EqualError(t, err, `enabled: unknown @profile "staging", must be one of: dev`)
+}
+
+func TestGenerateProfiles_ItemsField(t *testing.T) {
+	input := `---
+# @type: array<map>
+# @items:
+#   # The name.
+#   # @type: string
+#   # @profile: dev=data
+#   name: null
+volumes: []
+`
+	_, err := GenerateProfiles(input)
+	require.EqualError(t, err, "volumes.name: @profile can't be used on the fields of @items")
+}
+
+func TestWrapWords(t *testing.T) {
+	require.Equal(t, []string{"one two", "three", "fourteen"}, wrapWords("one two three fourteen", 7))
+	require.Nil(t, wrapWords("", 7))
+}
+
+func TestValidateProfiles_StaleFile(t *testing.T) {
+	input := `# @defineProfile: dev=A single server.
+
+# @profile: dev=true
+enabled: false
+`
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	files, err := GenerateProfiles(input)
+	require.NoError(t, err)
+	require.NoError(t, WriteProfiles(dir, files))
+	require.NoError(t, ValidateProfiles(input, dir))
+
+	// A file left behind by a profile that was removed is reported, and
+	// deleted when the profiles are regenerated.
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "tproxy.yaml"), []byte("{}\n"), 0644))
+	require.EqualError(t, ValidateProfiles(input, dir), "profiles/tproxy.yaml isn't generated from values.yaml, delete it by regenerating the profiles with: make gen-profiles")
+	require.NoError(t, WriteProfiles(dir, files))
+	require.NoError(t, ValidateProfiles(input, dir))
+}
+
+func TestProfilesUpToDate(t *testing.T) {
+	valuesBytes, err := ioutil.ReadFile(filepath.Join("..", "..", "values.yaml"))
+	require.NoError(t, err)
+	require.NoError(t, ValidateProfiles(string(valuesBytes), filepath.Join("..", "..", "profiles")))
+}
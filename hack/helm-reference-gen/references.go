@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// keyInBackticks matches a dotted key in backticks in documentation, e.g.
+	// `global.tls.enabled`. It captures the key.
+	keyInBackticks = regexp.MustCompile("`([a-zA-Z]\\w*(?:\\.[a-zA-Z]\\w*)+)`")
+
+	// keyInHTMLCode matches a dotted key in inline HTML code, e.g.
+	// <code>global.tls.enabled</code>. It captures the key.
+	keyInHTMLCode = regexp.MustCompile(`<code>([a-zA-Z]\w*(?:\.[a-zA-Z]\w*)+)</code>`)
+)
+
+// References returns the keys that doc refers to, in the order they're
+// found. A reference is a dotted key in backticks whose first part is one of
+// topLevelKeys, e.g. `global.tls.enabled`. Code blocks are ignored.
+func References(doc string, topLevelKeys map[string]bool) []string {
+	var refs []string
+	forEachLineOutsideFences(doc, func(line string) string {
+		for _, match := range keyInBackticks.FindAllStringSubmatch(line, -1) {
+			if topLevelKeys[strings.Split(match[1], ".")[0]] {
+				refs = append(refs, match[1])
+			}
+		}
+		return line
+	})
+	return refs
+}
+
+// CheckReferences returns an error for each reference in the documentation
+// of the tree rooted at node to a key that doesn't exist in the tree. The
+// replacement key of @deprecated is also checked.
+func CheckReferences(node DocNode) []*ParseError {
+	keys := node.Flatten()
+	topLevelKeys := topLevelKeys(node)
+
+	var errs []*ParseError
+	var walk func(n DocNode)
+	walk = func(n DocNode) {
+		for _, child := range n.Children {
+			refs := References(child.PlainDocumentation(), topLevelKeys)
+			if child.ReplacedBy != "" {
+				refs = append(refs, child.ReplacedBy)
+			}
+			for _, ref := range refs {
+				if _, ok := keys[ref]; !ok {
+					errs = append(errs, &ParseError{
+						ParentAnchor: child.ParentBreadcrumb,
+						CurrAnchor:   child.Key,
+						Path:         child.Path(),
+						Line:         child.Line,
+						Column:       child.Column,
+						Err:          fmt.Sprintf("references %q which doesn't exist", ref),
+					})
+				}
+			}
+			walk(child)
+		}
+	}
+	walk(node)
+	return errs
+}
+
+// LinkReferences rewrites each reference to a key in out, rendered markdown,
+// into a link to the key's anchor, e.g. `global.name` =>
+// [`global.name`](#v-global-name). Only keys in node are linked.
+func LinkReferences(out string, node DocNode) string {
+	keys := node.Flatten()
+	return forEachLineOutsideFences(out, func(line string) string {
+		return keyInBackticks.ReplaceAllStringFunc(line, func(match string) string {
+			key := strings.Trim(match, "`")
+			if _, ok := keys[key]; !ok {
+				return match
+			}
+			return fmt.Sprintf("[%s](#%s)", match, anchorForPath(key))
+		})
+	})
+}
+
+// linkHTMLReferences is like LinkReferences but for rendered HTML.
+func linkHTMLReferences(out string, node DocNode) string {
+	keys := node.Flatten()
+	return keyInHTMLCode.ReplaceAllStringFunc(out, func(match string) string {
+		key := keyInHTMLCode.FindStringSubmatch(match)[1]
+		if _, ok := keys[key]; !ok {
+			return match
+		}
+		return fmt.Sprintf(`<a href="#%s">%s</a>`, anchorForPath(key), match)
+	})
+}
+
+// topLevelKeys returns the keys of node's children.
+func topLevelKeys(node DocNode) map[string]bool {
+	keys := make(map[string]bool)
+	for _, child := range node.Children {
+		keys[child.Key] = true
+	}
+	return keys
+}
+
+// forEachLineOutsideFences calls fn for each line of text that isn't in a
+// fenced code block and replaces the line with its result.
+func forEachLineOutsideFences(text string, fn func(line string) string) string {
+	lines := strings.Split(text, "\n")
+	inFence := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if !inFence {
+			lines[i] = fn(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const referencesInput = `---
+global:
+  # Set $server.enabled$ or $client.enabled$, not $server$, $tls.enabled$
+  # or $prometheus.io/port$.
+  #
+  # $$$yaml
+  # # Ignored: $server.missing$
+  # $$$
+  enabled: true
+server:
+  # Defaults to $global.enabled$.
+  enabled: "-"
+client:
+  # Image docs.
+  image: consul
+`
+
+func TestReferences(t *testing.T) {
+	node, err := Parse(strings.Replace(referencesInput, "$", "`", -1))
+	require.NoError(t, err)
+	require.Equal(t,
+		[]string{"server.enabled", "client.enabled"},
+		References(node.Children[0].Children[0].PlainDocumentation(), topLevelKeys(node)))
+}
+
+func TestCheckReferences(t *testing.T) {
+	node, err := Parse(strings.Replace(referencesInput, "$", "`", -1))
+	require.NoError(t, err)
+	errs := CheckReferences(node)
+	require.Len(t, errs, 1)
+	require.EqualError(t, errs[0], `-global-enabled: references "client.enabled" which doesn't exist`)
+	require.Equal(t, 9, errs[0].Line)
+
+	_, err = GenerateDocs(strings.Replace(referencesInput, "$", "`", -1))
+	require.EqualError(t, err, `-global-enabled: references "client.enabled" which doesn't exist`)
+}
+
+func TestLinkReferences(t *testing.T) {
+	input := strings.Replace(referencesInput, "$client.enabled$", "$server.enabled$", -1)
+	out, err := GenerateDocs(strings.Replace(input, "$", "`", -1))
+	require.NoError(t, err)
+	require.Equal(t, strings.Replace(`### global
+
+- $global$ ((#v-global))
+
+  - $enabled$ ((#v-global-enabled)) ($boolean: true$) - Set [$server.enabled$](#v-server-enabled) or [$server.enabled$](#v-server-enabled), not $server$, $tls.enabled$
+    or $prometheus.io/port$.
+
+    $$$yaml
+    # Ignored: $server.missing$
+    $$$
+
+### server
+
+- $server$ ((#v-server))
+
+  - $enabled$ ((#v-server-enabled)) ($string: -$) - Defaults to [$global.enabled$](#v-global-enabled).
+
+### client
+
+- $client$ ((#v-client))
+
+  - $image$ ((#v-client-image)) ($string: consul$) - Image docs.`, "$", "`", -1), out)
+
+	html, err := RenderDocs(strings.Replace(input, "$", "`", -1), HTMLRenderer{})
+	require.NoError(t, err)
+	require.Contains(t, html, `<p>Defaults to <a href="#v-global-enabled"><code>global.enabled</code></a>.</p>`)
+}
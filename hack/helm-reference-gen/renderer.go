@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Renderer renders a tree of DocNode's into a reference document.
+type Renderer interface {
+	// Render renders the tree rooted at node. node is the root returned
+	// by Parse.
+	Render(node DocNode) (string, error)
+}
+
+var (
+	// markdownNodeTmpl is the go template used to print a DocNode node as
+	// CommonMark. Unlike docNodeTmpl, it uses HTML anchors since CommonMark
+	// doesn't support the `((#v-...))` syntax.
+	// We use $ instead of ` in the template so we can use the golang raw string
+	// format. We then do the replace from $ => `.
+	markdownNodeTmpl = template.Must(
+		template.New("").Funcs(defaultFuncs).Parse(
+			strings.Replace(
+				`{{- if eq .Column 1 }}### {{ .Key }}
+
+{{ end }}{{ .LeadingIndent }}- <a id="{{ .Anchor }}"></a>{{ range .AliasAnchors }}<a id="{{ . }}"></a>{{ end }}${{ .Key }}${{ if ne .FormattedKind "" }} (${{ .FormattedKind }}{{ if .FormattedDefault }}: {{ .FormattedDefault }}{{ end }}$){{ end }}{{ if .FormattedDocumentation}} - {{ .FormattedDocumentation }}{{ end }}{{ if expandDefaults }}{{ with .FormattedDefaultBlock false }}
+
+{{ . }}{{ end }}{{ end }}`,
+				"$", "`", -1)),
+	)
+
+	// defaultFuncs are the template functions used by the templates of the
+	// renderers. expandDefaults is replaced by withExpandDefaults.
+	defaultFuncs = template.FuncMap{
+		"expandDefaults": func() bool { return false },
+	}
+)
+
+// RendererOptions are the options for the built-in renderers.
+type RendererOptions struct {
+	// ExpandDefaults renders the defaults that are too big to show inline,
+	// e.g. maps and multi-line strings, as YAML blocks after the
+	// documentation. They're collapsible in the MDX and HTML formats.
+	ExpandDefaults bool
+}
+
+// renderers are the built-in renderers keyed by the name used to select them
+// with the -format flag.
+var renderers = map[string]func(opts RendererOptions) Renderer{
+	"mdx":          func(opts RendererOptions) Renderer { return MDXRenderer{ExpandDefaults: opts.ExpandDefaults} },
+	"markdown":     func(opts RendererOptions) Renderer { return MarkdownRenderer{ExpandDefaults: opts.ExpandDefaults} },
+	"html":         func(opts RendererOptions) Renderer { return HTMLRenderer{ExpandDefaults: opts.ExpandDefaults} },
+	"json":         func(RendererOptions) Renderer { return JSONRenderer{} },
+	"search-index": func(RendererOptions) Renderer { return SearchIndexRenderer{} },
+}
+
+// RendererFormats returns the names of the built-in renderers.
+func RendererFormats() []string {
+	var formats []string
+	for format := range renderers {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// NewRenderer returns the renderer for format configured with opts. If
+// templatePath is set, then a TemplateRenderer using the Go template at that
+// path is returned instead.
+func NewRenderer(format string, templatePath string, opts RendererOptions) (Renderer, error) {
+	if templatePath != "" {
+		return NewTemplateRenderer(templatePath)
+	}
+	newRenderer, ok := renderers[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown format %q, must be one of: %s", format, strings.Join(RendererFormats(), ", "))
+	}
+	return newRenderer(opts), nil
+}
+
+// RenderDocs parses yamlStr and renders it with r. It returns an error if the
+// documentation references a key that doesn't exist.
+func RenderDocs(yamlStr string, r Renderer) (string, error) {
+	node, err := Parse(yamlStr)
+	if err != nil {
+		return "", err
+	}
+	if errs := CheckReferences(node); len(errs) > 0 {
+		return "", errs[0]
+	}
+	return r.Render(node)
+}
+
+// MDXRenderer renders the MDX used on consul.io.
+type MDXRenderer struct {
+	// ExpandDefaults is RendererOptions.ExpandDefaults.
+	ExpandDefaults bool
+}
+
+func (r MDXRenderer) Render(node DocNode) (string, error) {
+	tmpl, err := withExpandDefaults(docNodeTmpl, r.ExpandDefaults)
+	if err != nil {
+		return "", err
+	}
+	children, err := generateDocsFromNode(tmpl, node)
+	out := LinkReferences(strings.Join(children, "\n\n"), node)
+	return strings.ReplaceAll(out, "[Enterprise Only]", "<EnterpriseAlert inline />"), err
+}
+
+// MarkdownRenderer renders plain CommonMark.
+type MarkdownRenderer struct {
+	// ExpandDefaults is RendererOptions.ExpandDefaults.
+	ExpandDefaults bool
+}
+
+func (r MarkdownRenderer) Render(node DocNode) (string, error) {
+	tmpl, err := withExpandDefaults(markdownNodeTmpl, r.ExpandDefaults)
+	if err != nil {
+		return "", err
+	}
+	children, err := generateDocsFromNode(tmpl, node)
+	return LinkReferences(strings.Join(children, "\n\n"), node), err
+}
+
+// withExpandDefaults returns a copy of tmpl whose expandDefaults function
+// returns expand.
+func withExpandDefaults(tmpl *template.Template, expand bool) (*template.Template, error) {
+	clone, err := tmpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return clone.Funcs(template.FuncMap{
+		"expandDefaults": func() bool { return expand },
+	}), nil
+}
+
+// TemplateRenderer renders using a user-supplied Go template. The template
+// is executed once for each DocNode, with the DocNode as its data, and the
+// results are joined with blank lines.
+type TemplateRenderer struct {
+	tmpl *template.Template
+}
+
+// NewTemplateRenderer parses the Go template at path.
+func NewTemplateRenderer(path string) (*TemplateRenderer, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(path).Parse(string(contents))
+	if err != nil {
+		return nil, err
+	}
+	return &TemplateRenderer{tmpl: tmpl}, nil
+}
+
+func (r *TemplateRenderer) Render(node DocNode) (string, error) {
+	children, err := generateDocsFromNode(r.tmpl, node)
+	return strings.Join(children, "\n\n"), err
+}
+
+// JSONRenderer renders the DocNode tree as JSON.
+type JSONRenderer struct{}
+
+// jsonDocNode is the JSON representation of a DocNode.
+type jsonDocNode struct {
+	Key            string        `json:"key"`
+	Path           string        `json:"path"`
+	Anchor         string        `json:"anchor"`
+	Kind           string        `json:"kind,omitempty"`
+	Default        string        `json:"default,omitempty"`
+	Documentation  string        `json:"documentation,omitempty"`
+	Enum           []string      `json:"enum,omitempty"`
+	Deprecated     bool          `json:"deprecated,omitempty"`
+	ReplacedBy     string        `json:"replacedBy,omitempty"`
+	Since          string        `json:"since,omitempty"`
+	Enterprise     bool          `json:"enterprise,omitempty"`
+	Aliases        []string      `json:"aliases,omitempty"`
+	AliasOf        string        `json:"aliasOf,omitempty"`
+	AliasIsElement bool          `json:"aliasIsElement,omitempty"`
+	Required       bool          `json:"required,omitempty"`
+	Children       []jsonDocNode `json:"children,omitempty"`
+}
+
+func (JSONRenderer) Render(node DocNode) (string, error) {
+	out, err := json.MarshalIndent(toJSONDocNodes(node.Children), "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out) + "\n", nil
+}
+
+// toJSONDocNodes recursively converts nodes into their JSON representation.
+func toJSONDocNodes(nodes []DocNode) []jsonDocNode {
+	var out []jsonDocNode
+	for _, n := range nodes {
+		j := jsonDocNode{
+			Key:            n.Key,
+			Path:           n.Path(),
+			Anchor:         n.Anchor(),
+			Kind:           n.FormattedKind(),
+			Documentation:  n.PlainDocumentation(),
+			Enum:           n.Enum,
+			Deprecated:     n.Deprecated,
+			ReplacedBy:     n.ReplacedBy,
+			Since:          n.Since,
+			Enterprise:     n.Enterprise,
+			Aliases:        n.Aliases,
+			AliasOf:        n.AliasOf,
+			AliasIsElement: n.AliasIsElement,
+			Required:       n.Required,
+			Children:       toJSONDocNodes(n.Children),
+		}
+		if j.Kind != "" {
+			j.Default = n.FormattedDefault()
+		}
+		out = append(out, j)
+	}
+	return out
+}
+
+// generateDocsFromNode executes tm for each node in the tree below node, in
+// depth-first order.
+func generateDocsFromNode(tm *template.Template, node DocNode) ([]string, error) {
+	var out []string
+	for _, child := range node.Children {
+		var nodeOut bytes.Buffer
+		err := tm.Execute(&nodeOut, child)
+		if err != nil {
+			return nil, err
+		}
+		childOut, err := generateDocsFromNode(tm, child)
+		if err != nil {
+			return nil, err
+		}
+		out = append(append(out, nodeOut.String()), childOut...)
+	}
+	return out, nil
+}
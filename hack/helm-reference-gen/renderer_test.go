@@ -0,0 +1,186 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const rendererInput = `---
+# Map docs
+map:
+  # [Enterprise Only] Key docs with $code$.
+  key: value
+`
+
+func TestRenderers(t *testing.T) {
+	cases := map[string]struct {
+		Renderer Renderer
+		Exp      string
+	}{
+		"mdx": {
+			Renderer: MDXRenderer{},
+			Exp: `### map
+
+- $map$ ((#v-map)) - Map docs
+
+  - $key$ ((#v-map-key)) ($string: value$) - <EnterpriseAlert inline /> Key docs with $code$.`,
+		},
+		"markdown": {
+			Renderer: MarkdownRenderer{},
+			Exp: `### map
+
+- <a id="v-map"></a>$map$ - Map docs
+
+  - <a id="v-map-key"></a>$key$ ($string: value$) - [Enterprise Only] Key docs with $code$.`,
+		},
+		"json": {
+			Renderer: JSONRenderer{},
+			Exp: `[
+  {
+    "key": "map",
+    "path": "map",
+    "anchor": "v-map",
+    "documentation": "Map docs",
+    "children": [
+      {
+        "key": "key",
+        "path": "map.key",
+        "anchor": "v-map-key",
+        "kind": "string",
+        "default": "value",
+        "documentation": "Key docs with $code$.",
+        "enterprise": true
+      }
+    ]
+  }
+]
+`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			out, err := RenderDocs(strings.Replace(rendererInput, "$", "`", -1), c.Renderer)
+			require.NoError(t, err)
+			require.Equal(t, strings.Replace(c.Exp, "$", "`", -1), out)
+		})
+	}
+}
+
+func TestHTMLRenderer(t *testing.T) {
+	out, err := RenderDocs(strings.Replace(rendererInput, "$", "`", -1), HTMLRenderer{})
+	require.NoError(t, err)
+	require.Contains(t, out, `<li><a href="#v-map">map</a></li>`)
+	require.Contains(t, out, `<li id="v-map-key" data-path="map.key"><a href="#v-map-key"><code>key</code></a> <code class="kind">string: value</code> <span class="enterprise">Enterprise Only</span>
+<p>Key docs with <code>code</code>.</p>
+</li>`)
+}
+
+func TestRenderers_ExpandDefaults(t *testing.T) {
+	input := `---
+# Map docs
+map:
+  # Key docs.
+  # @type: array<map>
+  key:
+    - name: "<b>"
+`
+	cases := map[string]struct {
+		Renderer Renderer
+		Exp      string
+	}{
+		"mdx": {
+			Renderer: MDXRenderer{ExpandDefaults: true},
+			Exp: `### map
+
+- $map$ ((#v-map)) - Map docs
+
+  - $key$ ((#v-map-key)) ($array<map>$) - Key docs.
+
+    <details>
+    <summary>Default value</summary>
+
+    $$$yaml
+    - name: "<b>"
+    $$$
+
+    </details>
+
+    - $name$ ((#v-map-key-name)) ($string: <b>$)`,
+		},
+		"markdown": {
+			Renderer: MarkdownRenderer{ExpandDefaults: true},
+			Exp: `### map
+
+- <a id="v-map"></a>$map$ - Map docs
+
+  - <a id="v-map-key"></a>$key$ ($array<map>$) - Key docs.
+
+    Default value:
+
+    $$$yaml
+    - name: "<b>"
+    $$$
+
+    - <a id="v-map-key-name"></a>$name$ ($string: <b>$)`,
+		},
+		"mdx without ExpandDefaults": {
+			Renderer: MDXRenderer{},
+			Exp: `### map
+
+- $map$ ((#v-map)) - Map docs
+
+  - $key$ ((#v-map-key)) ($array<map>$) - Key docs.
+
+    - $name$ ((#v-map-key-name)) ($string: <b>$)`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			out, err := RenderDocs(input, c.Renderer)
+			require.NoError(t, err)
+			require.Equal(t, strings.Replace(c.Exp, "$", "`", -1), out)
+		})
+	}
+
+	t.Run("html", func(t *testing.T) {
+		out, err := RenderDocs(input, HTMLRenderer{ExpandDefaults: true})
+		require.NoError(t, err)
+		require.Contains(t, out, `<p>Key docs.</p>
+<details class="default"><summary>Default value</summary><pre><code>- name: &#34;&lt;b&gt;&#34;</code></pre></details>
+<ul class="values">`)
+	})
+}
+
+func TestDocHTML(t *testing.T) {
+	doc := "Line with [a link](https://example.com) & <html>.\n\nExample:\n```yaml\nkey: \"<value>\"\n```"
+	require.Equal(t, `<p>Line with <a href="https://example.com">a link</a> &amp; &lt;html&gt;.</p>
+<p>Example:</p>
+<pre><code>key: &#34;&lt;value&gt;&#34;
+</code></pre>`, string(docHTML(doc)))
+}
+
+func TestTemplateRenderer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "custom.tmpl")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{{ .Path }}={{ .FormattedKind }}`), 0644))
+
+	r, err := NewRenderer("mdx", path, RendererOptions{})
+	require.NoError(t, err)
+	out, err := RenderDocs(rendererInput, r)
+	require.NoError(t, err)
+	require.Equal(t, "map=\n\nmap.key=string", out)
+}
+
+func TestNewRenderer_UnknownFormat(t *testing.T) {
+	_, err := NewRenderer("pdf", "", RendererOptions{})
+	require.EqualError(t, err, `unknown format "pdf", must be one of: html, json, markdown, mdx, search-index`)
+}
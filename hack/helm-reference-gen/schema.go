@@ -0,0 +1,347 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonSchemaDraft is the JSON Schema draft that the generated schema
+// declares. Helm validates values.schema.json against draft 7.
+const jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// undocumentedKeys are keys that the templates read but that aren't in
+// values.yaml. Most are removed or renamed settings that the templates check
+// for so they can fail with a helpful message, so the schema must allow them
+// through rather than rejecting them as unknown keys.
+var undocumentedKeys = []string{
+	"fullnameOverride",
+	"nameOverride",
+	"global.bootstrapACLs",
+	"global.lifecycleSidecarContainer",
+	"server.disableFsGroupSecurityContext",
+	"connectInject.centralConfig",
+	"connectInject.imageEnvoy",
+	"meshGateway.globalMode",
+	"meshGateway.imageEnvoy",
+}
+
+// Schema is a JSON Schema node. Only the subset of JSON Schema needed
+// to describe values.yaml is supported.
+type Schema struct {
+	// SchemaURI is the $schema keyword. It's only set on the root node.
+	SchemaURI string `json:"$schema,omitempty"`
+
+	// Type is either a single JSON Schema type, e.g. "string", or a list of
+	// types, e.g. ["string", "null"], when the YAML default doesn't match
+	// the annotated type.
+	Type interface{} `json:"type,omitempty"`
+
+	Description string `json:"description,omitempty"`
+
+	// Default is the YAML default converted to its JSON equivalent. It's only
+	// set for scalar values.
+	Default interface{} `json:"default,omitempty"`
+
+	// Enum is the list of allowed values from the @enum annotation.
+	Enum []interface{} `json:"enum,omitempty"`
+
+	// Deprecated is set from the @deprecated annotation. It isn't part of
+	// draft 7 so Helm ignores it, but editors use it to flag deprecated keys.
+	Deprecated bool `json:"deprecated,omitempty"`
+
+	// Enterprise and Since are set from the @enterprise and @since
+	// annotations. They're x- extensions so validators ignore them.
+	Enterprise bool   `json:"x-enterprise,omitempty"`
+	Since      string `json:"x-since,omitempty"`
+
+	// Properties are the documented sub-keys of a map.
+	Properties map[string]*Schema `json:"properties,omitempty"`
+
+	// Required are the keys of a map that must be set, from @required.
+	Required []string `json:"required,omitempty"`
+
+	// Pattern constrains string values. It's ignored for other types.
+	Pattern string `json:"pattern,omitempty"`
+
+	// AdditionalProperties is set to false for maps whose keys are all
+	// documented so that misspelled keys fail validation.
+	AdditionalProperties *bool `json:"additionalProperties,omitempty"`
+
+	// Items is the schema for each element of an array.
+	Items *Schema `json:"items,omitempty"`
+}
+
+// GenerateSchema parses yamlStr and returns the JSON Schema for it, formatted
+// for writing to values.schema.json. extraKeys are dotted paths to keys that
+// aren't in yamlStr but should still be allowed.
+func GenerateSchema(yamlStr string, extraKeys []string) (string, error) {
+	node, err := Parse(yamlStr)
+	if err != nil {
+		return "", err
+	}
+
+	schema := schemaFromNode(node)
+	schema.SchemaURI = jsonSchemaDraft
+	for _, key := range extraKeys {
+		schema.allowKey(strings.Split(key, "."))
+	}
+	out, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out) + "\n", nil
+}
+
+// schemaFromNode recursively converts a DocNode into a Schema.
+func schemaFromNode(n DocNode) *Schema {
+	s := &Schema{
+		Description: n.PlainDocumentation(),
+		Deprecated:  n.Deprecated,
+		Enterprise:  n.Enterprise,
+		Since:       n.Since,
+	}
+	if n.ReplacedBy != "" {
+		s.Description = strings.TrimSpace(fmt.Sprintf("Deprecated: use %s instead. %s", n.ReplacedBy, s.Description))
+	}
+	// The root node has no kind and maps that aren't annotated have an empty
+	// kind.
+	kind := "map"
+	if n.Column != 0 {
+		if k := n.FormattedKind(); k != "" {
+			kind = k
+		}
+	}
+
+	switch {
+	case kind == "map":
+		s.Type = schemaTypes("object", n.KindTag)
+		// Special case for resources since setting them as a YAML string is
+		// deprecated but still supported.
+		if n.Key == "resources" {
+			s.Type = []string{"object", "string"}
+		}
+		if len(n.Children) > 0 {
+			s.Properties = schemaProperties(n.Children)
+
+			// Only lock down the keys of maps that aren't explicitly annotated
+			// with @type: map. An explicit annotation means the map is free-form
+			// and the documented keys are only examples or defaults.
+			if !typeAnnotation.MatchString(n.Comment) {
+				f := false
+				s.AdditionalProperties = &f
+			}
+		}
+	case strings.HasPrefix(kind, "array"):
+		s.Type = schemaTypes("array", n.KindTag)
+		itemKind := strings.TrimSuffix(strings.TrimPrefix(kind, "array<"), ">")
+		if itemKind != kind {
+			s.Items = &Schema{Type: jsonSchemaType(itemKind)}
+			// If there were example elements in the default, then the children
+			// document the fields of each element. We don't set
+			// additionalProperties here because the elements commonly support
+			// more keys than are shown in the default.
+			if itemKind == "map" && len(n.Children) > 0 {
+				s.Items.Properties = schemaProperties(n.Children)
+				s.Items.Required = schemaRequired(n.Children)
+			}
+		}
+	case kind == "boolean":
+		// Booleans can also be set to "-" which means they inherit their
+		// value, e.g. from global.enabled. Pattern only applies to strings
+		// so "-" is the only string that's allowed.
+		types := []string{"boolean", "string"}
+		if n.KindTag == "!!null" {
+			types = append(types, "null")
+		}
+		s.Type = types
+		s.Pattern = "^-$"
+		s.Default = schemaDefault(n)
+	default:
+		if t := jsonSchemaType(kind); t != "" {
+			s.Type = schemaTypes(t, n.KindTag)
+			s.Default = schemaDefault(n)
+			s.Enum = schemaEnum(n, t)
+		}
+		// Special case for resource quantities since --set turns plain
+		// numbers, e.g. --set server.resources.requests.cpu=1, into integers.
+		if kind == "string" && isResourceQuantity(n) {
+			s.Type = addSchemaType(s.Type, "integer")
+		}
+	}
+	return s
+}
+
+// isResourceQuantity returns true if n is the cpu or memory of a resources
+// map's requests or limits, e.g. server.resources.requests.cpu.
+func isResourceQuantity(n DocNode) bool {
+	if n.Key != "cpu" && n.Key != "memory" {
+		return false
+	}
+	parents := strings.Split(n.ParentPath, ".")
+	return len(parents) >= 2 && parents[len(parents)-2] == "resources"
+}
+
+// addSchemaType adds t to the JSON Schema type(s) types after the first one.
+func addSchemaType(types interface{}, t string) interface{} {
+	switch v := types.(type) {
+	case string:
+		return []string{v, t}
+	case []string:
+		return append([]string{v[0], t}, v[1:]...)
+	}
+	return types
+}
+
+// schemaEnum converts the @enum values of n to JSON values of type t. If the
+// YAML default is null or "-", then it's also allowed so that the chart's own
+// default passes validation.
+func schemaEnum(n DocNode, t string) []interface{} {
+	if len(n.Enum) == 0 {
+		return nil
+	}
+	var enum []interface{}
+	for _, v := range n.Enum {
+		if t == "integer" {
+			if i, err := strconv.Atoi(v); err == nil {
+				enum = append(enum, i)
+				continue
+			}
+		}
+		enum = append(enum, v)
+	}
+	switch {
+	case n.KindTag == "!!null":
+		enum = append(enum, nil)
+	case n.KindTag == "!!str" && n.Default == "-" && !contains(n.Enum, "-"):
+		enum = append(enum, "-")
+	}
+	return enum
+}
+
+// allowKey adds an unconstrained property for the key at path so that it
+// passes validation even if its parent doesn't allow additional properties.
+func (s *Schema) allowKey(path []string) {
+	if s.Properties == nil {
+		s.Properties = make(map[string]*Schema)
+	}
+	child, ok := s.Properties[path[0]]
+	if !ok {
+		child = &Schema{}
+		s.Properties[path[0]] = child
+	}
+	if len(path) > 1 {
+		child.allowKey(path[1:])
+	}
+}
+
+// schemaProperties returns the properties map for a list of child nodes.
+func schemaProperties(children []DocNode) map[string]*Schema {
+	props := make(map[string]*Schema)
+	for _, child := range children {
+		props[child.Key] = schemaFromNode(child)
+	}
+	return props
+}
+
+// schemaRequired returns the keys of nodes with @required: true.
+func schemaRequired(nodes []DocNode) []string {
+	var required []string
+	for _, n := range nodes {
+		if n.Required {
+			required = append(required, n.Key)
+		}
+	}
+	return required
+}
+
+// jsonSchemaType converts a kind as returned by FormattedKind into a JSON
+// Schema type. It returns an empty string if the kind has no JSON Schema
+// equivalent, in which case the type is left unconstrained.
+func jsonSchemaType(kind string) string {
+	switch kind {
+	case "string":
+		return "string"
+	case "integer", "int":
+		return "integer"
+	case "boolean":
+		return "boolean"
+	case "map":
+		return "object"
+	default:
+		if strings.HasPrefix(kind, "array") {
+			return "array"
+		}
+		return ""
+	}
+}
+
+// schemaTypes returns the JSON Schema type(s) for a node whose annotated type
+// is t. If the YAML default is a different type, e.g. null or "-", then that
+// type is also allowed so that the chart's own defaults pass validation.
+func schemaTypes(t string, kindTag string) interface{} {
+	yamlType := jsonSchemaType(yamlKind(kindTag))
+	if kindTag == "!!null" {
+		yamlType = "null"
+	}
+	if yamlType == "" || yamlType == t {
+		return t
+	}
+	return []string{t, yamlType}
+}
+
+// yamlKind converts a YAML kind tag, e.g. "!!str" into the kind names used by
+// FormattedKind.
+func yamlKind(kindTag string) string {
+	switch strings.TrimLeft(kindTag, "!") {
+	case "str":
+		return "string"
+	case "int":
+		return "integer"
+	case "bool":
+		return "boolean"
+	case "map":
+		return "map"
+	case "seq":
+		return "array"
+	default:
+		return ""
+	}
+}
+
+// schemaDefault converts the YAML default of a scalar node into its JSON
+// equivalent. It returns nil if there is no default.
+func schemaDefault(n DocNode) interface{} {
+	switch strings.TrimLeft(n.KindTag, "!") {
+	case "str":
+		// Don't include multi-line defaults, e.g. affinity, since they
+		// are templated strings and not useful as a default.
+		if strings.Contains(strings.TrimSpace(n.Default), "\n") {
+			return nil
+		}
+		return n.Default
+	case "int":
+		if i, err := strconv.Atoi(n.Default); err == nil {
+			return i
+		}
+	case "bool":
+		if b, err := strconv.ParseBool(n.Default); err == nil {
+			return b
+		}
+	}
+	return nil
+}
+
+// ValidateSchema returns an error if the existing schema contents don't match
+// the schema generated from yamlStr.
+func ValidateSchema(yamlStr string, extraKeys []string, existing string) error {
+	generated, err := GenerateSchema(yamlStr, extraKeys)
+	if err != nil {
+		return err
+	}
+	if generated != existing {
+		return fmt.Errorf("values.schema.json is out of date, regenerate it with: make gen-schema")
+	}
+	return nil
+}
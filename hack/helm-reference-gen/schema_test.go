@@ -0,0 +1,307 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateSchema(t *testing.T) {
+	cases := map[string]struct {
+		Input string
+		Exp   string
+	}{
+		"scalars": {
+			Input: `---
+# String docs
+key: value
+# Int docs
+replicas: 3
+# @type: string
+name: null
+`,
+			Exp: `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "properties": {
+    "key": {
+      "type": "string",
+      "description": "String docs",
+      "default": "value"
+    },
+    "name": {
+      "type": [
+        "string",
+        "null"
+      ]
+    },
+    "replicas": {
+      "type": "integer",
+      "description": "Int docs",
+      "default": 3
+    }
+  },
+  "additionalProperties": false
+}
+`,
+		},
+		"enum and deprecated": {
+			Input: `---
+# Log level
+# @enum: debug, info
+logLevel: info
+# Port docs
+# @deprecated: server.port
+port: 8500
+`,
+			Exp: `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "properties": {
+    "logLevel": {
+      "type": "string",
+      "description": "Log level",
+      "default": "info",
+      "enum": [
+        "debug",
+        "info"
+      ]
+    },
+    "port": {
+      "type": "integer",
+      "description": "Deprecated: use server.port instead. Port docs",
+      "default": 8500,
+      "deprecated": true
+    }
+  },
+  "additionalProperties": false
+}
+`,
+		},
+		"boolean allows dash": {
+			Input: `---
+# @type: boolean
+enabled: "-"
+`,
+			Exp: `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "properties": {
+    "enabled": {
+      "type": [
+        "boolean",
+        "string"
+      ],
+      "default": "-",
+      "pattern": "^-$"
+    }
+  },
+  "additionalProperties": false
+}
+`,
+		},
+		"enum allows dash default": {
+			Input: `---
+# @enum: http, https
+scheme: "-"
+`,
+			Exp: `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "properties": {
+    "scheme": {
+      "type": "string",
+      "default": "-",
+      "enum": [
+        "http",
+        "https",
+        "-"
+      ]
+    }
+  },
+  "additionalProperties": false
+}
+`,
+		},
+		"enterprise and since": {
+			Input: `---
+# @enterprise: true
+# @since: 0.32.0
+adminPartitions: false
+`,
+			Exp: `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "properties": {
+    "adminPartitions": {
+      "type": [
+        "boolean",
+        "string"
+      ],
+      "default": false,
+      "x-enterprise": true,
+      "x-since": "0.32.0",
+      "pattern": "^-$"
+    }
+  },
+  "additionalProperties": false
+}
+`,
+		},
+		"map is strict": {
+			Input: `---
+tls:
+  # @type: string
+  caCert: null
+`,
+			Exp: `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "properties": {
+    "tls": {
+      "type": "object",
+      "properties": {
+        "caCert": {
+          "type": [
+            "string",
+            "null"
+          ]
+        }
+      },
+      "additionalProperties": false
+    }
+  },
+  "additionalProperties": false
+}
+`,
+		},
+		"@type: map is free-form": {
+			Input: `---
+# @type: map
+nodeMeta:
+  pod-name: name
+`,
+			Exp: `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "properties": {
+    "nodeMeta": {
+      "type": "object",
+      "properties": {
+        "pod-name": {
+          "type": "string",
+          "default": "name"
+        }
+      }
+    }
+  },
+  "additionalProperties": false
+}
+`,
+		},
+		"resource quantities allow integers": {
+			Input: `---
+resources:
+  # Requests docs
+  requests:
+    # Memory docs
+    memory: "100Mi"
+    # @type: string
+    cpu: null
+`,
+			Exp: `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "properties": {
+    "resources": {
+      "type": [
+        "object",
+        "string"
+      ],
+      "properties": {
+        "requests": {
+          "type": "object",
+          "description": "Requests docs",
+          "properties": {
+            "cpu": {
+              "type": [
+                "string",
+                "integer",
+                "null"
+              ]
+            },
+            "memory": {
+              "type": [
+                "string",
+                "integer"
+              ],
+              "description": "Memory docs",
+              "default": "100Mi"
+            }
+          },
+          "additionalProperties": false
+        }
+      },
+      "additionalProperties": false
+    }
+  },
+  "additionalProperties": false
+}
+`,
+		},
+		"arrays": {
+			Input: `---
+# @type: array<string>
+hosts: []
+# @type: array<map>
+gateways:
+  - name: ingress-gateway
+`,
+			Exp: `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "properties": {
+    "gateways": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "properties": {
+          "name": {
+            "type": "string",
+            "default": "ingress-gateway"
+          }
+        }
+      }
+    },
+    "hosts": {
+      "type": "array",
+      "items": {
+        "type": "string"
+      }
+    }
+  },
+  "additionalProperties": false
+}
+`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			out, err := GenerateSchema(c.Input, nil)
+			require.NoError(t, err)
+			require.Equal(t, c.Exp, out)
+		})
+	}
+}
+
+// Test that the chart's values.schema.json is up to date.
+func TestSchemaUpToDate(t *testing.T) {
+	valuesBytes, err := ioutil.ReadFile(filepath.Join("..", "..", "values.yaml"))
+	require.NoError(t, err)
+	schemaBytes, err := ioutil.ReadFile(filepath.Join("..", "..", "values.schema.json"))
+	require.NoError(t, err)
+	require.NoError(t, ValidateSchema(string(valuesBytes), undocumentedKeys, string(schemaBytes)))
+}
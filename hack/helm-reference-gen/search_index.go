@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// SearchIndexRenderer renders a flat JSON list with a record for every key,
+// for client-side search over the values.
+type SearchIndexRenderer struct{}
+
+// searchRecord is a key's record in the search index.
+type searchRecord struct {
+	Path       string `json:"path"`
+	Key        string `json:"key"`
+	ParentPath string `json:"parentPath"`
+	Anchor     string `json:"anchor"`
+	Kind       string `json:"kind,omitempty"`
+	Default    string `json:"default,omitempty"`
+
+	// Description is the documentation as plain text on one line, without
+	// markdown or code blocks.
+	Description string   `json:"description,omitempty"`
+	Enterprise  bool     `json:"enterprise,omitempty"`
+	Deprecated  bool     `json:"deprecated,omitempty"`
+	Aliases     []string `json:"aliases,omitempty"`
+}
+
+// Render renders a record per key in depth-first order. Each record is on
+// its own line to keep the index small but still diffable.
+func (SearchIndexRenderer) Render(node DocNode) (string, error) {
+	var records []string
+	var walk func(n DocNode) error
+	walk = func(n DocNode) error {
+		for _, child := range n.Children {
+			r := searchRecord{
+				Path:        child.Path(),
+				Key:         child.Key,
+				ParentPath:  child.ParentPath,
+				Anchor:      child.Anchor(),
+				Kind:        child.FormattedKind(),
+				Description: plainText(child.PlainDocumentation()),
+				Enterprise:  child.Enterprise,
+				Deprecated:  child.Deprecated,
+				Aliases:     child.Aliases,
+			}
+			if r.Kind != "" {
+				r.Default = child.FormattedDefault()
+			}
+			// Don't escape HTML characters since descriptions often have
+			// placeholders like <name>.
+			var out strings.Builder
+			enc := json.NewEncoder(&out)
+			enc.SetEscapeHTML(false)
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+			records = append(records, strings.TrimSuffix(out.String(), "\n"))
+			if err := walk(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(node); err != nil {
+		return "", err
+	}
+	if len(records) == 0 {
+		return "[]\n", nil
+	}
+	return "[\n" + strings.Join(records, ",\n") + "\n]\n", nil
+}
+
+// plainText converts documentation into plain text on one line. Fenced code
+// blocks are removed and inline code and links are replaced by their text.
+func plainText(doc string) string {
+	var lines []string
+	inFence := false
+	for _, line := range strings.Split(doc, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if !inFence {
+			lines = append(lines, line)
+		}
+	}
+	text := strings.Join(lines, " ")
+	text = markdownLink.ReplaceAllString(text, "$1")
+	text = inlineCode.ReplaceAllString(text, "$1")
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchIndexRenderer(t *testing.T) {
+	input := `---
+# Global docs.
+global:
+  # [Enterprise Only] Set the $<name>$ with
+  # [a link](https://example.com).
+  #
+  # $$$yaml
+  # name: consul
+  # $$$
+  # @alias: server.name
+  name: consul
+  # @deprecated: global.name
+  # @type: string
+  oldName: null
+`
+	out, err := RenderDocs(strings.Replace(input, "$", "`", -1), SearchIndexRenderer{})
+	require.NoError(t, err)
+	require.Equal(t, `[
+{"path":"global","key":"global","parentPath":"","anchor":"v-global","description":"Global docs."},
+{"path":"global.name","key":"name","parentPath":"global","anchor":"v-global-name","kind":"string","default":"consul","description":"Set the <name> with a link.","enterprise":true,"aliases":["server.name"]},
+{"path":"global.oldName","key":"oldName","parentPath":"global","anchor":"v-global-oldname","kind":"string","default":"null","deprecated":true}
+]
+`, out)
+
+	// The output must be valid JSON.
+	var records []searchRecord
+	require.NoError(t, json.Unmarshal([]byte(out), &records))
+	require.Len(t, records, 3)
+}
+
+func TestSearchIndexRenderer_Empty(t *testing.T) {
+	out, err := RenderDocs("---\n", SearchIndexRenderer{})
+	require.NoError(t, err)
+	require.Equal(t, "[]\n", out)
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// PreviewServer serves the docs for a values file as an HTML page that
+// reloads itself when the file changes. Errors are shown in the page rather
+// than stopping the server so the docs can be fixed while it's running.
+type PreviewServer struct {
+	valuesPath string
+
+	mu sync.RWMutex
+	// page is the rendered HTML page.
+	page string
+	// version is incremented each time page is re-rendered. The page polls
+	// it to know when to reload.
+	version int
+	// modTime is the modification time of the values file when page was
+	// rendered.
+	modTime time.Time
+}
+
+// NewPreviewServer returns a PreviewServer for the values file at
+// valuesPath with the page already rendered.
+func NewPreviewServer(valuesPath string) (*PreviewServer, error) {
+	s := &PreviewServer{valuesPath: valuesPath}
+	if _, err := s.Refresh(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Refresh re-renders the page if the values file has been modified since it
+// was last rendered. It returns true if it was re-rendered. An error is only
+// returned if the file can't be read, in which case the page is unchanged.
+func (s *PreviewServer) Refresh() (bool, error) {
+	info, err := os.Stat(s.valuesPath)
+	if err != nil {
+		return false, err
+	}
+	s.mu.RLock()
+	modified := !info.ModTime().Equal(s.modTime)
+	s.mu.RUnlock()
+	if !modified {
+		return false, nil
+	}
+
+	valuesBytes, err := ioutil.ReadFile(s.valuesPath)
+	if err != nil {
+		return false, err
+	}
+	page := RenderPreview(string(valuesBytes))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.page = page
+	s.version++
+	s.modTime = info.ModTime()
+	return true, nil
+}
+
+// Watch calls Refresh every interval until stop is closed. Errors are logged
+// since the file may briefly not exist while an editor saves it.
+func (s *PreviewServer) Watch(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			refreshed, err := s.Refresh()
+			if err != nil {
+				log.Printf("Error reading %s: %s", s.valuesPath, err)
+			} else if refreshed {
+				log.Printf("Re-rendered %s", s.valuesPath)
+			}
+		}
+	}
+}
+
+// ServeHTTP serves the page at / and its version at /version.
+func (s *PreviewServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	page, version := s.page, s.version
+	s.mu.RUnlock()
+
+	switch r.URL.Path {
+	case "/":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, page)
+	case "/version":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		fmt.Fprint(w, strconv.Itoa(version))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// RenderPreview renders yamlStr as an HTML page like HTMLRenderer but with
+// every parse, reference and example error shown in the page instead of
+// returned. If yamlStr isn't valid YAML, the page only has the error.
+func RenderPreview(yamlStr string) string {
+	page := htmlPage{LiveReload: true}
+	node, errs, err := ParseAll(yamlStr)
+	if err != nil {
+		page.Errors = []*ParseError{{Err: err.Error()}}
+	} else {
+		page.Root = node
+		page.Errors = append(append(errs, CheckReferences(node)...), CheckExamples(node)...)
+	}
+
+	out, err := renderHTMLPage(page)
+	if err != nil {
+		// The template itself failed so there's no page to show the error in.
+		return fmt.Sprintf("<!DOCTYPE html>\n<p>Error rendering page: %s</p>\n", html.EscapeString(err.Error()))
+	}
+	return out
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderPreview(t *testing.T) {
+	cases := map[string]struct {
+		Values string
+		Exp    []string
+	}{
+		"valid": {
+			Values: `---
+# Map docs
+map:
+  # Key docs.
+  key: value
+`,
+			Exp: []string{
+				`<li id="v-map-key" data-path="map.key">`,
+				`fetch("/version")`,
+			},
+		},
+		"parse errors": {
+			Values: `---
+map:
+  # @type: integer
+  key: value
+  # See $map.other$.
+  ref: value
+`,
+			Exp: []string{
+				`<li>line 4:3: <a href="#v-map-key">map.key</a>: @type is integer but the default &#34;value&#34; is a string</li>`,
+				`<li id="v-map-key" data-path="map.key"><a href="#v-map-key"><code>key</code></a> <code class="kind">integer: value</code>
+<p class="error">@type is integer but the default &#34;value&#34; is a string</p>`,
+				`<p class="error">references &#34;map.other&#34; which doesn&#39;t exist</p>`,
+			},
+		},
+		"invalid YAML": {
+			Values: "map: [\n",
+			Exp: []string{
+				`<li>yaml: line 1: did not find expected node content</li>`,
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			out := RenderPreview(strings.Replace(c.Values, "$", "`", -1))
+			for _, exp := range c.Exp {
+				require.Contains(t, out, exp)
+			}
+		})
+	}
+}
+
+func TestPreviewServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	valuesPath := filepath.Join(dir, "values.yaml")
+	require.NoError(t, ioutil.WriteFile(valuesPath, []byte("# Key docs.\nkey: one\n"), 0644))
+
+	s, err := NewPreviewServer(valuesPath)
+	require.NoError(t, err)
+	get := func(path string) string {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		return rec.Body.String()
+	}
+	require.Contains(t, get("/"), "string: one")
+	require.Equal(t, "1", get("/version"))
+
+	// Nothing changed so it's not re-rendered.
+	refreshed, err := s.Refresh()
+	require.NoError(t, err)
+	require.False(t, refreshed)
+
+	require.NoError(t, ioutil.WriteFile(valuesPath, []byte("# Key docs.\nkey: two\n"), 0644))
+	// Make sure the modification time changes even on file systems with a
+	// coarse resolution.
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(valuesPath, future, future))
+	refreshed, err = s.Refresh()
+	require.NoError(t, err)
+	require.True(t, refreshed)
+	require.Contains(t, get("/"), "string: two")
+	require.Equal(t, "2", get("/version"))
+}
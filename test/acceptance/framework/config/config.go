@@ -17,6 +17,11 @@ const (
 	HelmChartPath     = "../../../.."
 	LicenseSecretName = "license"
 	LicenseSecretKey  = "key"
+
+	// KindStorageClassName is the name of the storage class that kind
+	// installs in its clusters. It uses the local-path provisioner that comes
+	// with kind's node image so volumes don't need any network access.
+	KindStorageClassName = "standard"
 )
 
 // TestConfig holds configuration for the test suite.
@@ -30,6 +35,11 @@ type TestConfig struct {
 	SecondaryKubeContext   string
 	SecondaryKubeNamespace string
 
+	// Contexts are named Kubernetes contexts. If they're set, they replace
+	// the default and secondary contexts above and the first two of them are
+	// the default and secondary contexts.
+	Contexts []KubeContextConfig
+
 	EnableEnterprise  bool
 	EnterpriseLicense string
 
@@ -47,19 +57,44 @@ type TestConfig struct {
 
 	UseKind bool
 
+	// KindClusters is the number of kind clusters the suite creates and
+	// deletes. If it's 0, the tests run against existing clusters.
+	KindClusters  int
+	KindNodeImage string
+
+	// HelmValues are extra Helm values to set on every install, e.g. from
+	// the config file. The values set by the other options override them.
+	HelmValues map[string]string
+
 	helmChartPath string
 }
 
+// KubeContextConfig is the configuration of a named Kubernetes context.
+type KubeContextConfig struct {
+	Name          string
+	Kubeconfig    string
+	KubeContext   string
+	KubeNamespace string
+}
+
 // HelmValuesFromConfig returns a map of Helm values
 // that includes any non-empty values from the TestConfig.
 func (t *TestConfig) HelmValuesFromConfig() (map[string]string, error) {
 	helmValues := map[string]string{}
+	for k, v := range t.HelmValues {
+		helmValues[k] = v
+	}
 
 	// If Kind is being used they use a pod to provision the underlying PV which will hang if we
 	// use "Fail" for the webhook failurePolicy.
 	if t.UseKind {
 		setIfNotEmpty(helmValues, "connectInject.failurePolicy", "Ignore")
 	}
+	// Use the storage class that kind installs in the clusters created by
+	// the suite, which doesn't need network access.
+	if t.KindClusters > 0 {
+		setIfNotEmpty(helmValues, "server.storageClass", KindStorageClassName)
+	}
 	// Set the enterprise image first if enterprise tests are enabled.
 	// It can be overwritten by the -consul-image flag later.
 	if t.EnableEnterprise {
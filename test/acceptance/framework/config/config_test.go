@@ -104,6 +104,31 @@ func TestConfig_HelmValuesFromConfig(t *testing.T) {
 				"connectInject.transparentProxy.defaultEnabled": "true",
 			},
 		},
+		{
+			"sets the storage class of the kind clusters when they're created by the suite",
+			TestConfig{
+				KindClusters: 2,
+			},
+			map[string]string{
+				"server.storageClass":                           "standard",
+				"connectInject.transparentProxy.defaultEnabled": "false",
+			},
+		},
+		{
+			"sets extra helm values, which are overridden by the other options",
+			TestConfig{
+				ConsulImage: "consul:test-version",
+				HelmValues: map[string]string{
+					"global.logLevel": "debug",
+					"global.image":    "consul:from-helm-values",
+				},
+			},
+			map[string]string{
+				"global.logLevel": "debug",
+				"global.image":    "consul:test-version",
+				"connectInject.transparentProxy.defaultEnabled": "false",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -13,6 +13,7 @@ import (
 	terratestLogger "github.com/gruntwork-io/terratest/modules/logger"
 	"github.com/hashicorp/consul-helm/test/acceptance/framework/config"
 	"github.com/hashicorp/consul-helm/test/acceptance/framework/environment"
+	"github.com/hashicorp/consul-helm/test/acceptance/framework/helmvalues"
 	"github.com/hashicorp/consul-helm/test/acceptance/framework/helpers"
 	"github.com/hashicorp/consul-helm/test/acceptance/framework/k8s"
 	"github.com/hashicorp/consul-helm/test/acceptance/framework/logger"
@@ -81,6 +82,12 @@ func NewHelmCluster(
 		// (false positive).
 		"dns.enabled": "false",
 	}
+	// A release in a namespace of its own, which lets tests run in parallel,
+	// only injects and syncs the pods and services in its namespace.
+	// Otherwise the releases of other tests would inject their pods too.
+	if ctx.Isolated() {
+		mergeMaps(values, isolatedNamespaceValues(ctx.KubectlOptions(t).Namespace))
+	}
 	valuesFromConfig, err := cfg.HelmValuesFromConfig()
 	require.NoError(t, err)
 
@@ -88,6 +95,9 @@ func NewHelmCluster(
 	mergeMaps(values, valuesFromConfig)
 	mergeMaps(values, helmValues)
 
+	// Fail now rather than have a misspelled key silently do nothing.
+	require.NoError(t, helmvalues.ValidateSetValues(config.HelmChartPath, values))
+
 	logger := terratestLogger.New(logger.TestLogger{})
 
 	// Wait up to 15 min for K8s resources to be in a ready state. Increasing
@@ -114,6 +124,28 @@ func NewHelmCluster(
 	}
 }
 
+// NewHelmClusterFromValues is like NewHelmCluster but takes typed Helm values.
+// Their string fields are set with --set-string so that Helm doesn't turn
+// strings like "3" into numbers.
+func NewHelmClusterFromValues(
+	t *testing.T,
+	helmValues *helmvalues.Values,
+	ctx environment.TestContext,
+	cfg *config.TestConfig,
+	releaseName string,
+) Cluster {
+	setValues, setStrValues := helmvalues.ToSetValues(helmValues)
+	require.NoError(t, helmvalues.ValidateSetValues(config.HelmChartPath, setStrValues))
+
+	cluster := NewHelmCluster(t, setValues, ctx, cfg, releaseName).(*HelmCluster)
+	// The typed values override the defaults set by NewHelmCluster.
+	for key := range setStrValues {
+		delete(cluster.helmOptions.SetValues, key)
+	}
+	cluster.helmOptions.SetStrValues = setStrValues
+	return cluster
+}
+
 func (h *HelmCluster) Create(t *testing.T) {
 	t.Helper()
 
@@ -229,6 +261,12 @@ func (h *HelmCluster) Upgrade(t *testing.T, helmValues map[string]string) {
 	t.Helper()
 
 	mergeMaps(h.helmOptions.SetValues, helmValues)
+	// --set-string values from NewHelmClusterFromValues would take
+	// precedence over the new values.
+	for key := range helmValues {
+		delete(h.helmOptions.SetStrValues, key)
+	}
+	require.NoError(t, helmvalues.ValidateSetValues(config.HelmChartPath, h.helmOptions.SetValues))
 	helm.Upgrade(t, h.helmOptions, config.HelmChartPath, h.releaseName)
 	helpers.WaitForAllPodsToBeReady(t, h.kubernetesClient, h.helmOptions.KubectlOptions.Namespace, fmt.Sprintf("release=%s", h.releaseName))
 }
@@ -321,7 +359,7 @@ func (h *HelmCluster) checkForPriorInstallations(t *testing.T) {
 		require.NotContains(t, r["chart"], "consul", fmt.Sprintf("detected an existing installation of Consul %s, release name: %s", r["chart"], r["name"]))
 	}
 
-	// Wait for all pods in the "default" namespace to exit. A previous
+	// Wait for all pods in the installation namespace to exit. A previous
 	// release may not be listed by Helm but its pods may still be terminating.
 	retry.RunWith(&retry.Counter{Wait: 1 * time.Second, Count: 60}, t, func(r *retry.R) {
 		consulPods, err := h.kubernetesClient.CoreV1().Pods(h.helmOptions.KubectlOptions.Namespace).List(context.Background(), metav1.ListOptions{})
@@ -341,8 +379,10 @@ func (h *HelmCluster) checkForPriorInstallations(t *testing.T) {
 // and a role binding that binds the default service account in the helm installation namespace to the cluster role.
 // We bind the default service account for tests that are spinning up pods without a service account set so that
 // they will not be rejected by the kube pod security policy controller.
+// The pod security policy and cluster role are named after the namespace so that tests running in parallel
+// in other namespaces don't delete them from under each other.
 func configurePodSecurityPolicies(t *testing.T, client kubernetes.Interface, cfg *config.TestConfig, namespace string) {
-	pspName := "test-psp"
+	pspName := pspNameForNamespace(namespace)
 
 	// Pod Security Policy
 	{
@@ -353,7 +393,7 @@ func configurePodSecurityPolicies(t *testing.T, client kubernetes.Interface, cfg
 			// This policy is fairly simple and only prevents from running privileged containers.
 			psp := &policyv1beta.PodSecurityPolicy{
 				ObjectMeta: metav1.ObjectMeta{
-					Name: "test-psp",
+					Name: pspName,
 				},
 				Spec: policyv1beta.PodSecurityPolicySpec{
 					Privileged:          false,
@@ -444,6 +484,12 @@ func configurePodSecurityPolicies(t *testing.T, client kubernetes.Interface, cfg
 	})
 }
 
+// pspNameForNamespace returns the name of the pod security policy, cluster role
+// and role binding created by configurePodSecurityPolicies for namespace.
+func pspNameForNamespace(namespace string) string {
+	return fmt.Sprintf("test-psp-%s", namespace)
+}
+
 func createOrUpdateLicenseSecret(t *testing.T, client kubernetes.Interface, cfg *config.TestConfig, namespace string) {
 	_, err := client.CoreV1().Secrets(namespace).Get(context.Background(), config.LicenseSecretName, metav1.GetOptions{})
 	if errors.IsNotFound(err) {
@@ -519,3 +565,13 @@ func mergeMaps(a, b map[string]string) {
 		a[k] = v
 	}
 }
+
+// isolatedNamespaceValues returns the Helm values that limit connect
+// injection and catalog sync to namespace.
+func isolatedNamespaceValues(namespace string) map[string]string {
+	return map[string]string{
+		"connectInject.k8sAllowNamespaces": fmt.Sprintf("{%s}", namespace),
+		"connectInject.namespaceSelector":  fmt.Sprintf("matchLabels:\n  %s: %s\n", environment.TestNamespaceNameLabel, namespace),
+		"syncCatalog.k8sAllowNamespaces":   fmt.Sprintf("{%s}", namespace),
+	}
+}
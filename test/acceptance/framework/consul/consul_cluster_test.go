@@ -1,11 +1,15 @@
 package consul
 
 import (
+	"context"
 	"testing"
 
 	"github.com/gruntwork-io/terratest/modules/k8s"
 	"github.com/hashicorp/consul-helm/test/acceptance/framework/config"
+	"github.com/hashicorp/consul-helm/test/acceptance/framework/environment"
+	"github.com/hashicorp/consul-helm/test/acceptance/framework/helmvalues"
 	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
 )
@@ -42,7 +46,7 @@ func TestNewHelmCluster(t *testing.T) {
 				"connectInject.logLevel":                        "debug",
 				"connectInject.transparentProxy.defaultEnabled": "true",
 				"dns.enabled":                                   "true",
-				"feature.enabled":                               "true",
+				"ui.enabled":                                    "true",
 			},
 			want: map[string]string{
 				"global.image":                                  "test-image",
@@ -52,7 +56,7 @@ func TestNewHelmCluster(t *testing.T) {
 				"connectInject.logLevel":                        "debug",
 				"connectInject.transparentProxy.defaultEnabled": "true",
 				"dns.enabled":                                   "true",
-				"feature.enabled":                               "true",
+				"ui.enabled":                                    "true",
 			},
 		},
 	}
@@ -64,15 +68,88 @@ func TestNewHelmCluster(t *testing.T) {
 	}
 }
 
-type ctx struct{}
+// Test that the string fields of typed values are set with --set-string and
+// override the defaults.
+func TestNewHelmClusterFromValues(t *testing.T) {
+	cluster := NewHelmClusterFromValues(t, &helmvalues.Values{
+		Global: &helmvalues.GlobalValues{
+			Datacenter: helmvalues.String("3"),
+		},
+		Server: &helmvalues.ServerValues{
+			Enabled: helmvalues.Inherit(),
+		},
+		ConnectInject: &helmvalues.ConnectInjectValues{
+			LogLevel: helmvalues.String("info"),
+		},
+	}, &ctx{}, &config.TestConfig{}, "test")
+	helmOptions := cluster.(*HelmCluster).helmOptions
+	require.Equal(t, map[string]string{
+		"global.datacenter":      "3",
+		"connectInject.logLevel": "info",
+	}, helmOptions.SetStrValues)
+	require.Equal(t, "-", helmOptions.SetValues["server.enabled"])
+	require.NotContains(t, helmOptions.SetValues, "connectInject.logLevel")
+}
+
+// Test that a release in a namespace from WithNewNamespace only injects and
+// syncs its own namespace.
+func TestNewHelmCluster_isolated(t *testing.T) {
+	cluster := NewHelmCluster(t, map[string]string{"syncCatalog.k8sAllowNamespaces": "{*}"}, &ctx{namespace: "test-ns", isolated: true}, &config.TestConfig{}, "test")
+	values := cluster.(*HelmCluster).helmOptions.SetValues
+	require.Equal(t, "{test-ns}", values["connectInject.k8sAllowNamespaces"])
+	require.Equal(t, "matchLabels:\n  consul-helm-test-namespace: test-ns\n", values["connectInject.namespaceSelector"])
+	// The test's values take precedence.
+	require.Equal(t, "{*}", values["syncCatalog.k8sAllowNamespaces"])
+}
+
+// Test that each namespace gets its own pod security policy and cluster role
+// so that a test finishing doesn't delete the ones another test is using.
+func TestConfigurePodSecurityPolicies(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	cfg := &config.TestConfig{}
+	pspExists := func(t *testing.T, namespace string) bool {
+		name := pspNameForNamespace(namespace)
+		_, err := client.PolicyV1beta1().PodSecurityPolicies().Get(context.Background(), name, metav1.GetOptions{})
+		_, roleErr := client.RbacV1().ClusterRoles().Get(context.Background(), name, metav1.GetOptions{})
+		_, bindingErr := client.RbacV1().RoleBindings(namespace).Get(context.Background(), name, metav1.GetOptions{})
+		require.Equal(t, err == nil, roleErr == nil)
+		require.Equal(t, err == nil, bindingErr == nil)
+		return err == nil
+	}
+
+	t.Run("first test", func(t *testing.T) {
+		configurePodSecurityPolicies(t, client, cfg, "ns1")
+		t.Run("second test", func(t *testing.T) {
+			configurePodSecurityPolicies(t, client, cfg, "ns2")
+			require.True(t, pspExists(t, "ns2"))
+		})
+		require.False(t, pspExists(t, "ns2"))
+		// The first test's policy is still there.
+		require.True(t, pspExists(t, "ns1"))
+	})
+	require.False(t, pspExists(t, "ns1"))
+}
+
+type ctx struct {
+	namespace string
+	isolated  bool
+}
 
 func (c *ctx) Name() string {
 	return ""
 }
 
 func (c *ctx) KubectlOptions(_ *testing.T) *k8s.KubectlOptions {
-	return &k8s.KubectlOptions{}
+	return &k8s.KubectlOptions{Namespace: c.namespace}
 }
 func (c *ctx) KubernetesClient(_ *testing.T) kubernetes.Interface {
 	return fake.NewSimpleClientset()
 }
+
+func (c *ctx) WithNewNamespace(_ *testing.T) environment.TestContext {
+	return c
+}
+
+func (c *ctx) Isolated() bool {
+	return c.isolated
+}
@@ -1,20 +1,39 @@
 package environment
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/gruntwork-io/terratest/modules/k8s"
 	"github.com/hashicorp/consul-helm/test/acceptance/framework/config"
 	"github.com/hashicorp/consul-helm/test/acceptance/framework/helpers"
+	"github.com/hashicorp/consul-helm/test/acceptance/framework/logger"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/kubernetes"
 )
 
 const (
 	DefaultContextName   = "default"
 	SecondaryContextName = "secondary"
+
+	// TestNamespaceLabel is set to "true" on the namespaces created by
+	// WithNewNamespace so they can be found, e.g. to clean up after an
+	// interrupted run.
+	TestNamespaceLabel = "consul-helm-test"
+	// TestNameLabel is set to the name of the test a namespace was created
+	// for, shortened to fit in a label value if needed.
+	TestNameLabel = "consul-helm-test-name"
+	// TestContextLabel is set to the name of the context a namespace was
+	// created in.
+	TestContextLabel = "consul-helm-test-context"
+	// TestNamespaceNameLabel is set to the name of the namespace so that the
+	// connect-inject webhook of a release in it can select only it.
+	TestNamespaceNameLabel = "consul-helm-test-namespace"
 )
 
 // TestEnvironment represents the infrastructure environment of the test,
@@ -22,32 +41,54 @@ const (
 type TestEnvironment interface {
 	DefaultContext(t *testing.T) TestContext
 	Context(t *testing.T, name string) TestContext
+	// Contexts returns all the contexts, starting with the default context.
+	Contexts() []TestContext
 }
 
 // TestContext represents a specific context a test needs,
 // for example, information about a specific Kubernetes cluster.
 type TestContext interface {
+	Name() string
 	KubectlOptions(t *testing.T) *k8s.KubectlOptions
 	KubernetesClient(t *testing.T) kubernetes.Interface
+	// WithNewNamespace creates a uniquely named namespace for the test t
+	// and returns a copy of the context that uses it. The namespace is
+	// deleted when the test finishes, so tests that use it can run in
+	// parallel with t.Parallel().
+	WithNewNamespace(t *testing.T) TestContext
+	// Isolated returns true if the context is from WithNewNamespace. Consul
+	// releases installed in it only inject and sync the pods and services in
+	// its namespace so they don't interfere with tests running in parallel.
+	Isolated() bool
 }
 
 type KubernetesEnvironment struct {
 	contexts map[string]*kubernetesContext
+	// names are the names of the contexts in the order they were added.
+	names []string
 }
 
 func NewKubernetesEnvironmentFromConfig(config *config.TestConfig) *KubernetesEnvironment {
-	defaultContext := NewContext(config.KubeNamespace, config.Kubeconfig, config.KubeContext)
+	kenv := &KubernetesEnvironment{}
+	if len(config.Contexts) > 0 {
+		// The named contexts replace the contexts set by the other flags.
+		// The first and second of them are the default and secondary
+		// contexts.
+		for _, c := range config.Contexts {
+			kenv.addContext(c.Name, NewContext(c.KubeNamespace, c.Kubeconfig, c.KubeContext))
+		}
+	} else {
+		// Create a kubernetes environment with default context.
+		kenv.addContext(DefaultContextName, NewContext(config.KubeNamespace, config.Kubeconfig, config.KubeContext))
 
-	// Create a kubernetes environment with default context.
-	kenv := &KubernetesEnvironment{
-		contexts: map[string]*kubernetesContext{
-			DefaultContextName: defaultContext,
-		},
+		// Add secondary context if multi cluster tests are enabled.
+		if config.EnableMultiCluster {
+			kenv.addContext(SecondaryContextName, NewContext(config.SecondaryKubeNamespace, config.SecondaryKubeconfig, config.SecondaryKubeContext))
+		}
 	}
 
-	// Add secondary context if multi cluster tests are enabled.
-	if config.EnableMultiCluster {
-		kenv.contexts[SecondaryContextName] = NewContext(config.SecondaryKubeNamespace, config.SecondaryKubeconfig, config.SecondaryKubeContext)
+	for _, ctx := range kenv.contexts {
+		ctx.noCleanupOnFailure = config.NoCleanupOnFailure
 	}
 
 	return kenv
@@ -55,38 +96,93 @@ func NewKubernetesEnvironmentFromConfig(config *config.TestConfig) *KubernetesEn
 
 func NewKubernetesEnvironmentFromContext(context *kubernetesContext) *KubernetesEnvironment {
 	// Create a kubernetes environment with default context.
-	kenv := &KubernetesEnvironment{
-		contexts: map[string]*kubernetesContext{
-			DefaultContextName: context,
-		},
-	}
+	kenv := &KubernetesEnvironment{}
+	kenv.addContext(DefaultContextName, context)
 
 	return kenv
 }
 
+// addContext adds context to the environment with name.
+func (k *KubernetesEnvironment) addContext(name string, context *kubernetesContext) {
+	if k.contexts == nil {
+		k.contexts = make(map[string]*kubernetesContext)
+	}
+	k.names = append(k.names, name)
+	context.name = name
+	k.contexts[name] = context
+}
+
+// context returns the context with name. The first and second contexts are
+// also the default and secondary contexts, whatever their names are.
+func (k *KubernetesEnvironment) context(name string) (*kubernetesContext, bool) {
+	if ctx, ok := k.contexts[name]; ok {
+		return ctx, true
+	}
+	switch {
+	case name == DefaultContextName && len(k.names) > 0:
+		return k.contexts[k.names[0]], true
+	case name == SecondaryContextName && len(k.names) > 1:
+		return k.contexts[k.names[1]], true
+	}
+	return nil, false
+}
+
 func (k *KubernetesEnvironment) Context(t *testing.T, name string) TestContext {
-	ctx, ok := k.contexts[name]
+	ctx, ok := k.context(name)
 	require.Truef(t, ok, fmt.Sprintf("requested context %s not found", name))
 
 	return ctx
 }
 
 func (k *KubernetesEnvironment) DefaultContext(t *testing.T) TestContext {
-	ctx, ok := k.contexts[DefaultContextName]
+	ctx, ok := k.context(DefaultContextName)
 	require.Truef(t, ok, "default context not found")
 
 	return ctx
 }
 
+func (k *KubernetesEnvironment) Contexts() []TestContext {
+	var contexts []TestContext
+	for _, name := range k.names {
+		contexts = append(contexts, k.contexts[name])
+	}
+	return contexts
+}
+
+// ContextsOrSkip returns the first n contexts of env, starting with the
+// default context. The test is skipped if env has fewer than n contexts so
+// that tests can declare how many Kubernetes clusters they need.
+func ContextsOrSkip(t *testing.T, env TestEnvironment, n int) []TestContext {
+	t.Helper()
+
+	contexts := env.Contexts()
+	if len(contexts) < n {
+		t.Skipf("skipping because the test needs %d Kubernetes contexts but only %d are configured", n, len(contexts))
+	}
+	return contexts[:n]
+}
+
 type kubernetesContext struct {
+	name             string
 	pathToKubeConfig string
 	kubeContextName  string
 	namespace        string
 
+	// noCleanupOnFailure is true if the namespaces created by
+	// WithNewNamespace shouldn't be deleted when the test fails.
+	noCleanupOnFailure bool
+
+	// isolated is true if the context is from WithNewNamespace.
+	isolated bool
+
 	client  kubernetes.Interface
 	options *k8s.KubectlOptions
 }
 
+func (k kubernetesContext) Name() string {
+	return k.name
+}
+
 func (k kubernetesContext) KubectlOptions(t *testing.T) *k8s.KubectlOptions {
 	if k.options != nil {
 		return k.options
@@ -127,6 +223,57 @@ func (k kubernetesContext) KubernetesClient(t *testing.T) kubernetes.Interface {
 	return k.client
 }
 
+func (k kubernetesContext) WithNewNamespace(t *testing.T) TestContext {
+	t.Helper()
+
+	client := k.KubernetesClient(t)
+	namespace := helpers.RandomName()
+	_, err := client.CoreV1().Namespaces().Create(context.Background(), &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: namespace,
+			Labels: map[string]string{
+				TestNamespaceLabel:     "true",
+				TestNameLabel:          labelValue(t.Name()),
+				TestContextLabel:       labelValue(k.name),
+				TestNamespaceNameLabel: namespace,
+			},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	logger.Logf(t, "created namespace %s", namespace)
+
+	helpers.Cleanup(t, k.noCleanupOnFailure, func() {
+		_ = client.CoreV1().Namespaces().Delete(context.Background(), namespace, metav1.DeleteOptions{})
+	})
+
+	isolated := k
+	isolated.namespace = namespace
+	isolated.options = nil
+	isolated.client = client
+	isolated.isolated = true
+	return &isolated
+}
+
+func (k kubernetesContext) Isolated() bool {
+	return k.isolated
+}
+
+// labelValue returns s with the characters that aren't allowed in label
+// values replaced with "-", shortened to the maximum length of a label value.
+func labelValue(s string) string {
+	value := []byte(s)
+	for i, c := range value {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			value[i] = '-'
+		}
+	}
+	if len(value) > validation.LabelValueMaxLength {
+		value = value[:validation.LabelValueMaxLength]
+	}
+	// Label values must start and end with an alphanumeric character.
+	return strings.Trim(string(value), "-_.")
+}
+
 func NewContext(namespace, pathToKubeConfig, kubeContextName string) *kubernetesContext {
 	return &kubernetesContext{
 		namespace:        namespace,
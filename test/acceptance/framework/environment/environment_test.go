@@ -0,0 +1,108 @@
+package environment
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul-helm/test/acceptance/framework/config"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNewKubernetesEnvironmentFromConfig(t *testing.T) {
+	env := NewKubernetesEnvironmentFromConfig(&config.TestConfig{
+		KubeContext:          "kind-dc1",
+		EnableMultiCluster:   true,
+		SecondaryKubeContext: "kind-dc2",
+	})
+
+	var names, kubeContexts []string
+	for _, ctx := range env.Contexts() {
+		names = append(names, ctx.Name())
+		kubeContexts = append(kubeContexts, ctx.(*kubernetesContext).kubeContextName)
+	}
+	require.Equal(t, []string{DefaultContextName, SecondaryContextName}, names)
+	require.Equal(t, []string{"kind-dc1", "kind-dc2"}, kubeContexts)
+}
+
+func TestNewKubernetesEnvironmentFromConfig_namedContexts(t *testing.T) {
+	// The named contexts replace the other contexts.
+	env := NewKubernetesEnvironmentFromConfig(&config.TestConfig{
+		KubeContext:          "ignored",
+		EnableMultiCluster:   true,
+		SecondaryKubeContext: "ignored",
+		Contexts: []config.KubeContextConfig{
+			{Name: "dc1", KubeContext: "kind-dc1"},
+			{Name: "dc2", KubeContext: "kind-dc2"},
+			{Name: "dc3", KubeContext: "kind-dc3", KubeNamespace: "consul"},
+		},
+	})
+
+	var names, kubeContexts []string
+	for _, ctx := range env.Contexts() {
+		names = append(names, ctx.Name())
+		kubeContexts = append(kubeContexts, ctx.(*kubernetesContext).kubeContextName)
+	}
+	require.Equal(t, []string{"dc1", "dc2", "dc3"}, names)
+	require.Equal(t, []string{"kind-dc1", "kind-dc2", "kind-dc3"}, kubeContexts)
+	// The first and second contexts are the default and secondary contexts.
+	require.Equal(t, "dc1", env.DefaultContext(t).Name())
+	require.Equal(t, "dc1", env.Context(t, DefaultContextName).Name())
+	require.Equal(t, "dc2", env.Context(t, SecondaryContextName).Name())
+	require.Equal(t, "dc3", env.Context(t, "dc3").Name())
+}
+
+func TestContextsOrSkip(t *testing.T) {
+	env := NewKubernetesEnvironmentFromConfig(&config.TestConfig{
+		Contexts: []config.KubeContextConfig{{Name: "dc2"}},
+	})
+
+	require.Len(t, ContextsOrSkip(t, env, 2), 2)
+
+	skipped := true
+	t.Run("fewer contexts than needed", func(t *testing.T) {
+		ContextsOrSkip(t, env, 3)
+		skipped = false
+	})
+	require.True(t, skipped)
+}
+
+func TestWithNewNamespace(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	ctx := &kubernetesContext{name: DefaultContextName, namespace: "default", client: client}
+
+	var namespace string
+	t.Run("isolated test", func(t *testing.T) {
+		isolated := ctx.WithNewNamespace(t)
+		require.Equal(t, DefaultContextName, isolated.Name())
+		namespace = isolated.KubectlOptions(t).Namespace
+		require.NotEqual(t, "default", namespace)
+
+		ns, err := client.CoreV1().Namespaces().Get(context.Background(), namespace, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			TestNamespaceLabel:     "true",
+			TestNameLabel:          "TestWithNewNamespace-isolated_test",
+			TestContextLabel:       DefaultContextName,
+			TestNamespaceNameLabel: namespace,
+		}, ns.Labels)
+		require.True(t, isolated.Isolated())
+
+		// The original context is unchanged.
+		require.Equal(t, "default", ctx.KubectlOptions(t).Namespace)
+		require.False(t, ctx.Isolated())
+	})
+
+	// The namespace is deleted when the test finishes.
+	_, err := client.CoreV1().Namespaces().Get(context.Background(), namespace, metav1.GetOptions{})
+	require.True(t, errors.IsNotFound(err))
+}
+
+func TestLabelValue(t *testing.T) {
+	require.Equal(t, "TestFoo-secure-_true-_auto-encrypt-_false", labelValue("TestFoo/secure:_true;_auto-encrypt:_false"))
+	require.Equal(t, "a", labelValue("/a/"))
+	require.Len(t, labelValue(strings.Repeat("a", 100)), 63)
+}
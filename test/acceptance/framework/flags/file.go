@@ -0,0 +1,238 @@
+package flags
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// contextsKey is the key of the config file's per-context sections.
+	contextsKey = "contexts"
+
+	// helmValuesKey is the key of the config file's map of Helm values to set
+	// on every install.
+	helmValuesKey = "helm-values"
+
+	// contextFlag is the name of the flag with the named contexts.
+	contextFlag = "context"
+)
+
+// contextFlagPrefixes maps the names of the contexts that have their own flags
+// to the prefix of their flags, e.g. the kubeconfig of the "secondary" context
+// is set by -secondary-kubeconfig. If the config file has other contexts, all
+// of its contexts are added to -context instead.
+var contextFlagPrefixes = map[string]string{
+	"default":   "",
+	"secondary": "secondary-",
+}
+
+// contextKeys are the keys of a context's section in the config file.
+var contextKeys = []string{"kubeconfig", "kubecontext", "namespace"}
+
+// legacyEnvVars maps flags to the environment variables that set them that
+// don't follow envVarName.
+var legacyEnvVars = map[string]string{
+	"enterprise-license": "CONSUL_ENT_LICENSE",
+}
+
+// envVarName returns the name of the environment variable that sets the flag
+// name, e.g. CONSUL_TEST_ENABLE_ENTERPRISE for -enable-enterprise.
+func envVarName(name string) string {
+	return "CONSUL_TEST_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// readConfigFile reads the YAML or JSON config file at path. It returns the
+// values of the flags it sets keyed by flag name, the extra Helm values and a
+// message for each error in the file.
+func readConfigFile(path string, isFlag func(name string) bool) (map[string]string, map[string]string, []string) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, []string{fmt.Sprintf("failed to read config file: %s", err)}
+	}
+	var file map[interface{}]interface{}
+	if err := yaml.Unmarshal(contents, &file); err != nil {
+		return nil, nil, []string{fmt.Sprintf("failed to parse config file %s: %s", path, err)}
+	}
+
+	values := make(map[string]string)
+	var helmValues map[string]string
+	var errs []string
+	for _, keyValue := range sortedKeys(file) {
+		key := fmt.Sprint(keyValue)
+		switch key {
+		case contextsKey:
+			// The contexts' names are read in order too because the first
+			// one is the default context.
+			var ordered struct {
+				Contexts yaml.MapSlice `yaml:"contexts"`
+			}
+			contexts, ok := file[keyValue].(map[interface{}]interface{})
+			if !ok || yaml.Unmarshal(contents, &ordered) != nil {
+				errs = append(errs, fmt.Sprintf("config file: %q must be a map of context names to their settings", key))
+				continue
+			}
+			var names []interface{}
+			for _, item := range ordered.Contexts {
+				names = append(names, item.Key)
+			}
+			errs = append(errs, readContexts(names, contexts, values)...)
+		case helmValuesKey:
+			m, ok := file[keyValue].(map[interface{}]interface{})
+			if !ok {
+				errs = append(errs, fmt.Sprintf("config file: %q must be a map of Helm values, e.g. global.logLevel: debug", key))
+				continue
+			}
+			helmValues = make(map[string]string)
+			errs = append(errs, readHelmValues("", m, helmValues)...)
+		default:
+			if key == "config" || !isFlag(key) {
+				errs = append(errs, fmt.Sprintf("config file: unknown key %q", key))
+				continue
+			}
+			// A key without a value, e.g. "enable-enterprise:", leaves the
+			// flag's default rather than setting it to an empty string.
+			if file[keyValue] == nil {
+				continue
+			}
+			v, err := scalar(file[keyValue])
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("config file: %q %s", key, err))
+				continue
+			}
+			values[key] = v
+		}
+	}
+	return values, helmValues, errs
+}
+
+// readContexts adds the values of the flags set by the per-context sections
+// of the config file, contexts, to values. names are the names of the
+// contexts in the order they're in the file. If there are only the default
+// and secondary contexts they set their own flags, otherwise they're all
+// added to -context in order.
+func readContexts(names []interface{}, contexts map[interface{}]interface{}, values map[string]string) []string {
+	useFlags := true
+	for _, nameValue := range names {
+		if _, ok := contextFlagPrefixes[fmt.Sprint(nameValue)]; !ok {
+			useFlags = false
+		}
+	}
+
+	var errs []string
+	var named []string
+	for _, nameValue := range names {
+		name := fmt.Sprint(nameValue)
+		settings, ok := contexts[nameValue].(map[interface{}]interface{})
+		if !ok {
+			errs = append(errs, fmt.Sprintf("config file: context %q must be a map of %s", name, strings.Join(contextKeys, ", ")))
+			continue
+		}
+		context := make(map[string]string)
+		for _, keyValue := range sortedKeys(settings) {
+			key := fmt.Sprint(keyValue)
+			if !contains(contextKeys, key) {
+				errs = append(errs, fmt.Sprintf("config file: unknown key %q in context %q", key, name))
+				continue
+			}
+			if settings[keyValue] == nil {
+				continue
+			}
+			v, err := scalar(settings[keyValue])
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("config file: %q in context %q %s", key, name, err))
+				continue
+			}
+			context[key] = v
+		}
+
+		if useFlags {
+			prefix := contextFlagPrefixes[name]
+			for key, v := range context {
+				values[prefix+key] = v
+			}
+			continue
+		}
+		entry := fmt.Sprintf("%s=%s:%s", name, context["kubeconfig"], context["kubecontext"])
+		if context["namespace"] != "" {
+			entry += ":" + context["namespace"]
+		}
+		named = append(named, entry)
+	}
+	if len(named) > 0 {
+		if values[contextFlag] != "" {
+			named = append([]string{values[contextFlag]}, named...)
+		}
+		values[contextFlag] = strings.Join(named, ",")
+	}
+	return errs
+}
+
+// readHelmValues adds the values in m to helmValues keyed by their dotted
+// path, so both global.logLevel: debug and global: {logLevel: debug} set
+// global.logLevel. prefix is the path of m.
+func readHelmValues(prefix string, m map[interface{}]interface{}, helmValues map[string]string) []string {
+	var errs []string
+	for _, keyValue := range sortedKeys(m) {
+		key := prefix + fmt.Sprint(keyValue)
+		if nested, ok := m[keyValue].(map[interface{}]interface{}); ok {
+			errs = append(errs, readHelmValues(key+".", nested, helmValues)...)
+			continue
+		}
+		v, err := scalar(m[keyValue])
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("config file: %q in %q %s", key, helmValuesKey, err))
+			continue
+		}
+		helmValues[key] = v
+	}
+	return errs
+}
+
+// scalar returns the string form of the config file value v.
+func scalar(v interface{}) (string, error) {
+	switch v.(type) {
+	case map[interface{}]interface{}, []interface{}:
+		return "", fmt.Errorf("must be a string, number or boolean")
+	case nil:
+		return "", nil
+	}
+	return fmt.Sprint(v), nil
+}
+
+// lookupEnv returns the value of the environment variable that sets the flag
+// name, if it's set.
+func lookupEnv(name string) (string, bool) {
+	if v, ok := os.LookupEnv(envVarName(name)); ok {
+		return v, true
+	}
+	if legacy, ok := legacyEnvVars[name]; ok {
+		return os.LookupEnv(legacy)
+	}
+	return "", false
+}
+
+// sortedKeys returns the keys of m sorted by their string form so errors are
+// reported in a stable order.
+func sortedKeys(m map[interface{}]interface{}) []interface{} {
+	var keys []interface{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+	return keys
+}
+
+// contains returns true if s is in list.
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
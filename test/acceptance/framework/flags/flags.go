@@ -3,13 +3,16 @@ package flags
 import (
 	"errors"
 	"flag"
-	"os"
+	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/hashicorp/consul-helm/test/acceptance/framework/config"
 )
 
 type TestFlags struct {
+	flagConfigFile string
+
 	flagKubeconfig  string
 	flagKubecontext string
 	flagNamespace   string
@@ -19,6 +22,8 @@ type TestFlags struct {
 	flagSecondaryKubecontext string
 	flagSecondaryNamespace   string
 
+	flagContexts string
+
 	flagEnableEnterprise  bool
 	flagEnterpriseLicense string
 
@@ -37,92 +42,210 @@ type TestFlags struct {
 
 	flagUseKind bool
 
+	flagCreateKindClusters int
+	flagKindNodeImage      string
+
+	// flagSet is the flag set the flags are registered with.
+	flagSet *flag.FlagSet
+
+	// helmValues are the extra Helm values from the config file.
+	helmValues map[string]string
+
+	// loadErrs are the errors from loading the config file and environment
+	// variables. They're reported by Validate.
+	loadErrs []string
+	loaded   bool
+
 	once sync.Once
 }
 
 func NewTestFlags() *TestFlags {
-	t := &TestFlags{}
+	return newTestFlags(flag.CommandLine)
+}
+
+// newTestFlags returns TestFlags with its flags registered with flagSet.
+func newTestFlags(flagSet *flag.FlagSet) *TestFlags {
+	t := &TestFlags{flagSet: flagSet}
 	t.once.Do(t.init)
 
 	return t
 }
 
 func (t *TestFlags) init() {
-	flag.StringVar(&t.flagKubeconfig, "kubeconfig", "", "The path to a kubeconfig file. If this is blank, "+
+	t.flagSet.StringVar(&t.flagConfigFile, "config", "", "The path to a YAML or JSON file with the values of any of the other flags, "+
+		"keyed by flag name without the dash, as well as per-context sections and extra Helm values to set on every install. "+
+		"Environment variables override the file and flags override both.")
+	t.flagSet.StringVar(&t.flagKubeconfig, "kubeconfig", "", "The path to a kubeconfig file. If this is blank, "+
 		"the default kubeconfig path (~/.kube/config) will be used.")
-	flag.StringVar(&t.flagKubecontext, "kubecontext", "", "The name of the Kubernetes context to use. If this is blank, "+
+	t.flagSet.StringVar(&t.flagKubecontext, "kubecontext", "", "The name of the Kubernetes context to use. If this is blank, "+
 		"the context set as the current context will be used by default.")
-	flag.StringVar(&t.flagNamespace, "namespace", "", "The Kubernetes namespace to use for tests.")
+	t.flagSet.StringVar(&t.flagNamespace, "namespace", "", "The Kubernetes namespace to use for tests.")
 
-	flag.StringVar(&t.flagConsulImage, "consul-image", "", "The Consul image to use for all tests.")
-	flag.StringVar(&t.flagConsulK8sImage, "consul-k8s-image", "", "The consul-k8s image to use for all tests.")
+	t.flagSet.StringVar(&t.flagConsulImage, "consul-image", "", "The Consul image to use for all tests.")
+	t.flagSet.StringVar(&t.flagConsulK8sImage, "consul-k8s-image", "", "The consul-k8s image to use for all tests.")
 
-	flag.BoolVar(&t.flagEnableMultiCluster, "enable-multi-cluster", false,
+	t.flagSet.BoolVar(&t.flagEnableMultiCluster, "enable-multi-cluster", false,
 		"If true, the tests that require multiple Kubernetes clusters will be run. "+
 			"At least one of -secondary-kubeconfig or -secondary-kubecontext is required when this flag is used.")
-	flag.StringVar(&t.flagSecondaryKubeconfig, "secondary-kubeconfig", "", "The path to a kubeconfig file of the secondary k8s cluster. "+
+	t.flagSet.StringVar(&t.flagSecondaryKubeconfig, "secondary-kubeconfig", "", "The path to a kubeconfig file of the secondary k8s cluster. "+
 		"If this is blank, the default kubeconfig path (~/.kube/config) will be used.")
-	flag.StringVar(&t.flagSecondaryKubecontext, "secondary-kubecontext", "", "The name of the Kubernetes context for the secondary cluster to use. "+
+	t.flagSet.StringVar(&t.flagSecondaryKubecontext, "secondary-kubecontext", "", "The name of the Kubernetes context for the secondary cluster to use. "+
 		"If this is blank, the context set as the current context will be used by default.")
-	flag.StringVar(&t.flagSecondaryNamespace, "secondary-namespace", "", "The Kubernetes namespace to use in the secondary k8s cluster.")
-
-	flag.BoolVar(&t.flagEnableEnterprise, "enable-enterprise", false,
+	t.flagSet.StringVar(&t.flagSecondaryNamespace, "secondary-namespace", "", "The Kubernetes namespace to use in the secondary k8s cluster.")
+	t.flagSet.StringVar(&t.flagContexts, "context", "", "A comma-separated list of named Kubernetes contexts in the format "+
+		"<name>=<kubeconfig>:<context>[:<namespace>], e.g. dc1=:kind-dc1,dc2=:kind-dc2,dc3=/path/to/kubeconfig:dc3. "+
+		"Blank parts use the defaults. If it's set, the tests run against only these contexts: the first is the default context "+
+		"and the second is the secondary context, so it can't be combined with the other flags that set them. "+
+		"Implies -enable-multi-cluster if there are at least 2.")
+
+	t.flagSet.BoolVar(&t.flagEnableEnterprise, "enable-enterprise", false,
 		"If true, the test suite will run tests for enterprise features. "+
 			"Note that some features may require setting the enterprise license flag below or the env var CONSUL_ENT_LICENSE")
-	flag.StringVar(&t.flagEnterpriseLicense, "enterprise-license", "",
+	t.flagSet.StringVar(&t.flagEnterpriseLicense, "enterprise-license", "",
 		"The enterprise license for Consul.")
 
-	flag.BoolVar(&t.flagEnableOpenshift, "enable-openshift", false,
+	t.flagSet.BoolVar(&t.flagEnableOpenshift, "enable-openshift", false,
 		"If true, the tests will automatically add Openshift Helm value for each Helm install.")
 
-	flag.BoolVar(&t.flagEnablePodSecurityPolicies, "enable-pod-security-policies", false,
+	t.flagSet.BoolVar(&t.flagEnablePodSecurityPolicies, "enable-pod-security-policies", false,
 		"If true, the test suite will run tests with pod security policies enabled.")
 
-	flag.BoolVar(&t.flagEnableTransparentProxy, "enable-transparent-proxy", false,
+	t.flagSet.BoolVar(&t.flagEnableTransparentProxy, "enable-transparent-proxy", false,
 		"If true, the test suite will run tests with transparent proxy enabled. "+
 			"This applies only to tests that enable connectInject.")
 
-	flag.BoolVar(&t.flagNoCleanupOnFailure, "no-cleanup-on-failure", false,
+	t.flagSet.BoolVar(&t.flagNoCleanupOnFailure, "no-cleanup-on-failure", false,
 		"If true, the tests will not cleanup Kubernetes resources they create when they finish running."+
 			"Note this flag must be run with -failfast flag, otherwise subsequent tests will fail.")
 
-	flag.StringVar(&t.flagDebugDirectory, "debug-directory", "", "The directory where to write debug information about failed test runs, "+
+	t.flagSet.StringVar(&t.flagDebugDirectory, "debug-directory", "", "The directory where to write debug information about failed test runs, "+
 		"such as logs and pod definitions. If not provided, a temporary directory will be created by the tests.")
 
-	flag.BoolVar(&t.flagUseKind, "use-kind", false,
+	t.flagSet.BoolVar(&t.flagUseKind, "use-kind", false,
 		"If true, the tests will assume they are running against a local kind cluster(s).")
 
-	if t.flagEnterpriseLicense == "" {
-		t.flagEnterpriseLicense = os.Getenv("CONSUL_ENT_LICENSE")
+	t.flagSet.IntVar(&t.flagCreateKindClusters, "create-kind-clusters", 0,
+		"The number of kind clusters to create before running the tests and delete afterwards. "+
+			"The first two are the default and secondary contexts and use -namespace and -secondary-namespace, "+
+			"and the rest are named dc3, dc4 and so on and use -namespace. It fails if clusters left behind by a previous run still exist. "+
+			"Implies -use-kind, and -enable-multi-cluster if it's at least 2. It can't be combined with -context.")
+	t.flagSet.StringVar(&t.flagKindNodeImage, "kind-node-image", "",
+		"The kind node image to create the clusters from with -create-kind-clusters. If this is blank, kind's default is used.")
+}
+
+// load sets the flags that weren't passed on the command line from their
+// environment variables or, failing that, the config file. It only runs
+// once, after the flags have been parsed.
+func (t *TestFlags) load() {
+	if t.loaded || t.flagSet == nil {
+		return
 	}
+	t.loaded = true
+
+	setOnCommandLine := make(map[string]bool)
+	t.flagSet.Visit(func(f *flag.Flag) {
+		setOnCommandLine[f.Name] = true
+	})
+
+	var fileValues map[string]string
+	if t.flagConfigFile != "" {
+		isFlag := func(name string) bool { return t.flagSet.Lookup(name) != nil }
+		var errs []string
+		fileValues, t.helmValues, errs = readConfigFile(t.flagConfigFile, isFlag)
+		t.loadErrs = append(t.loadErrs, errs...)
+	}
+
+	t.flagSet.VisitAll(func(f *flag.Flag) {
+		if setOnCommandLine[f.Name] || f.Name == "config" {
+			return
+		}
+		source := "the config file"
+		v, ok := fileValues[f.Name]
+		if envValue, envOK := lookupEnv(f.Name); envOK {
+			source, v, ok = "its environment variable", envValue, true
+		}
+		if !ok {
+			return
+		}
+		if err := f.Value.Set(v); err != nil {
+			t.loadErrs = append(t.loadErrs, fmt.Sprintf("invalid value %q for -%s from %s: %s", v, f.Name, source, err))
+		}
+	})
 }
 
+// Validate returns an error listing every problem with the flags, the config
+// file and the environment variables.
 func (t *TestFlags) Validate() error {
-	if t.flagEnableMultiCluster {
-		if t.flagSecondaryKubecontext == "" && t.flagSecondaryKubeconfig == "" {
-			return errors.New("at least one of -secondary-kubecontext or -secondary-kubeconfig flags must be provided if -enable-multi-cluster is set")
+	t.load()
+
+	errs := append([]string{}, t.loadErrs...)
+
+	contexts, err := parseContexts(t.flagContexts)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	if t.flagContexts != "" {
+		for _, f := range []struct{ name, value string }{
+			{"kubeconfig", t.flagKubeconfig},
+			{"kubecontext", t.flagKubecontext},
+			{"namespace", t.flagNamespace},
+			{"secondary-kubeconfig", t.flagSecondaryKubeconfig},
+			{"secondary-kubecontext", t.flagSecondaryKubecontext},
+			{"secondary-namespace", t.flagSecondaryNamespace},
+		} {
+			if f.value != "" {
+				errs = append(errs, fmt.Sprintf("-%s can't be combined with -context, set the first two contexts in -context instead", f.name))
+			}
+		}
+	}
+
+	// The secondary context of the kind clusters is set when they're created.
+	if t.flagEnableMultiCluster && t.flagCreateKindClusters < 2 {
+		if t.flagContexts != "" {
+			if err == nil && len(contexts) < 2 {
+				errs = append(errs, "-context must have at least 2 contexts if -enable-multi-cluster is set")
+			}
+		} else if t.flagSecondaryKubecontext == "" && t.flagSecondaryKubeconfig == "" {
+			errs = append(errs, "at least one of -secondary-kubecontext or -secondary-kubeconfig flags must be provided if -enable-multi-cluster is set")
 		}
 	}
 
 	if t.flagEnableEnterprise && t.flagEnterpriseLicense == "" {
-		return errors.New("-enable-enterprise provided without setting env var CONSUL_ENT_LICENSE with consul license")
+		errs = append(errs, "-enable-enterprise provided without setting env var CONSUL_ENT_LICENSE with consul license")
+	}
+
+	if t.flagCreateKindClusters < 0 {
+		errs = append(errs, "-create-kind-clusters must not be negative")
+	}
+	if t.flagCreateKindClusters > 0 && t.flagContexts != "" {
+		errs = append(errs, "-create-kind-clusters can't be combined with -context because the contexts are the kind clusters")
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
 	}
 	return nil
 }
 
 func (t *TestFlags) TestConfigFromFlags() *config.TestConfig {
+	t.load()
+
 	tempDir := t.flagDebugDirectory
 
+	// Errors are reported by Validate.
+	contexts, _ := parseContexts(t.flagContexts)
+
 	return &config.TestConfig{
 		Kubeconfig:    t.flagKubeconfig,
 		KubeContext:   t.flagKubecontext,
 		KubeNamespace: t.flagNamespace,
 
-		EnableMultiCluster:     t.flagEnableMultiCluster,
+		EnableMultiCluster:     t.flagEnableMultiCluster || t.flagCreateKindClusters >= 2 || len(contexts) >= 2,
 		SecondaryKubeconfig:    t.flagSecondaryKubeconfig,
 		SecondaryKubeContext:   t.flagSecondaryKubecontext,
 		SecondaryKubeNamespace: t.flagSecondaryNamespace,
 
+		Contexts: contexts,
+
 		EnableEnterprise:  t.flagEnableEnterprise,
 		EnterpriseLicense: t.flagEnterpriseLicense,
 
@@ -137,6 +260,56 @@ func (t *TestFlags) TestConfigFromFlags() *config.TestConfig {
 
 		NoCleanupOnFailure: t.flagNoCleanupOnFailure,
 		DebugDirectory:     tempDir,
-		UseKind:            t.flagUseKind,
+		UseKind:            t.flagUseKind || t.flagCreateKindClusters > 0,
+
+		KindClusters:  t.flagCreateKindClusters,
+		KindNodeImage: t.flagKindNodeImage,
+
+		HelmValues: t.helmValues,
+	}
+}
+
+// parseContexts parses the value of -context, e.g.
+// "dc1=:kind-dc1,dc2=/path/to/kubeconfig:dc2:consul". Only the first and
+// second contexts can be named default and secondary since they're the
+// default and secondary contexts.
+func parseContexts(value string) ([]config.KubeContextConfig, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var contexts []config.KubeContextConfig
+	seen := make(map[string]bool)
+	for i, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		nameAndContext := strings.SplitN(entry, "=", 2)
+		if len(nameAndContext) != 2 || nameAndContext[0] == "" {
+			return nil, fmt.Errorf("-context %q must be in the format <name>=<kubeconfig>:<context>[:<namespace>]", entry)
+		}
+		parts := strings.Split(nameAndContext[1], ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("-context %q must be in the format <name>=<kubeconfig>:<context>[:<namespace>]", entry)
+		}
+		name := nameAndContext[0]
+		if seen[name] {
+			return nil, fmt.Errorf("-context %q is set more than once", name)
+		}
+		seen[name] = true
+		if name == "default" && i != 0 {
+			return nil, fmt.Errorf("-context %q must be the first context because it's the default context", name)
+		}
+		if name == "secondary" && i != 1 {
+			return nil, fmt.Errorf("-context %q must be the second context because it's the secondary context", name)
+		}
+
+		context := config.KubeContextConfig{
+			Name:        name,
+			Kubeconfig:  parts[0],
+			KubeContext: parts[1],
+		}
+		if len(parts) == 3 {
+			context.KubeNamespace = parts[2]
+		}
+		contexts = append(contexts, context)
 	}
+	return contexts, nil
 }
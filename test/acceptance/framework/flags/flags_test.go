@@ -1,8 +1,14 @@
 package flags
 
 import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/hashicorp/consul-helm/test/acceptance/framework/config"
+	"github.com/hashicorp/consul-helm/test/acceptance/framework/environment"
 	"github.com/stretchr/testify/require"
 )
 
@@ -117,3 +123,273 @@ func TestFlags_validate(t *testing.T) {
 		})
 	}
 }
+
+func TestFlags_configFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, ioutil.WriteFile(configFile, []byte(`
+consul-image: consul:from-file
+consul-k8s-image: consul-k8s:from-file
+enable-multi-cluster: true
+enable-transparent-proxy: true
+contexts:
+  default:
+    kubecontext: kind-dc1
+    namespace: consul
+  secondary:
+    kubeconfig: /secondary/kubeconfig
+  dc3:
+    kubecontext: kind-dc3
+    namespace: consul
+helm-values:
+  global.logLevel: debug
+  server:
+    replicas: 3
+    storage: null
+`), 0644))
+
+	// Environment variables override the file and flags override both.
+	os.Setenv("CONSUL_TEST_CONSUL_K8S_IMAGE", "consul-k8s:from-env")
+	defer os.Unsetenv("CONSUL_TEST_CONSUL_K8S_IMAGE")
+	os.Setenv("CONSUL_TEST_CONSUL_IMAGE", "consul:from-env")
+	defer os.Unsetenv("CONSUL_TEST_CONSUL_IMAGE")
+
+	tf := newTestFlags(flag.NewFlagSet("test", flag.ContinueOnError))
+	err = tf.flagSet.Parse([]string{
+		"-config", configFile,
+		"-consul-image", "consul:from-flag",
+		"-enable-transparent-proxy=false",
+	})
+	require.NoError(t, err)
+	require.NoError(t, tf.Validate())
+
+	cfg := tf.TestConfigFromFlags()
+	require.Equal(t, "consul:from-flag", cfg.ConsulImage)
+	require.Equal(t, "consul-k8s:from-env", cfg.ConsulK8SImage)
+	require.True(t, cfg.EnableMultiCluster)
+	require.False(t, cfg.EnableTransparentProxy)
+	// The contexts are in the order they're in the file since there are
+	// more than the default and secondary contexts.
+	require.Equal(t, []config.KubeContextConfig{
+		{Name: "default", KubeContext: "kind-dc1", KubeNamespace: "consul"},
+		{Name: "secondary", Kubeconfig: "/secondary/kubeconfig"},
+		{Name: "dc3", KubeContext: "kind-dc3", KubeNamespace: "consul"},
+	}, cfg.Contexts)
+	// Nested maps are flattened into dotted keys.
+	require.Equal(t, map[string]string{"global.logLevel": "debug", "server.replicas": "3", "server.storage": ""}, cfg.HelmValues)
+}
+
+// Test that options without a value in the config file keep their defaults.
+func TestFlags_configFileNullValues(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, ioutil.WriteFile(configFile, []byte(`
+enable-enterprise:
+secondary-namespace: ~
+contexts:
+  default:
+    kubecontext: kind-dc1
+    namespace:
+`), 0644))
+
+	tf := newTestFlags(flag.NewFlagSet("test", flag.ContinueOnError))
+	require.NoError(t, tf.flagSet.Parse([]string{"-config", configFile}))
+	require.NoError(t, tf.Validate())
+
+	cfg := tf.TestConfigFromFlags()
+	require.False(t, cfg.EnableEnterprise)
+	require.Equal(t, "kind-dc1", cfg.KubeContext)
+	require.Empty(t, cfg.KubeNamespace)
+	require.Empty(t, cfg.SecondaryKubeNamespace)
+}
+
+func TestFlags_configFileJSON(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	configFile := filepath.Join(dir, "config.json")
+	require.NoError(t, ioutil.WriteFile(configFile, []byte(`{"use-kind": true, "contexts": {"default": {"kubecontext": "kind-dc1"}, "secondary": {"namespace": "consul"}}}`), 0644))
+
+	tf := newTestFlags(flag.NewFlagSet("test", flag.ContinueOnError))
+	require.NoError(t, tf.flagSet.Parse([]string{"-config", configFile}))
+	require.NoError(t, tf.Validate())
+
+	// Only the default and secondary contexts set their own flags.
+	cfg := tf.TestConfigFromFlags()
+	require.True(t, cfg.UseKind)
+	require.Equal(t, "kind-dc1", cfg.KubeContext)
+	require.Equal(t, "consul", cfg.SecondaryKubeNamespace)
+	require.Empty(t, cfg.Contexts)
+}
+
+func TestFlags_validateReportsAllErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, ioutil.WriteFile(configFile, []byte(`
+enable-multi-cluster: true
+enable-enterprise: yes please
+consul-imag: consul:typo
+contexts:
+  dc3: kind-dc3
+  default:
+    cluster: dc1
+helm-values: debug
+`), 0644))
+	os.Unsetenv("CONSUL_ENT_LICENSE")
+
+	tf := newTestFlags(flag.NewFlagSet("test", flag.ContinueOnError))
+	require.NoError(t, tf.flagSet.Parse([]string{"-config", configFile}))
+	require.EqualError(t, tf.Validate(), `config file: unknown key "consul-imag"
+config file: context "dc3" must be a map of kubeconfig, kubecontext, namespace
+config file: unknown key "cluster" in context "default"
+config file: "helm-values" must be a map of Helm values, e.g. global.logLevel: debug
+invalid value "yes please" for -enable-enterprise from the config file: parse error
+-context must have at least 2 contexts if -enable-multi-cluster is set`)
+}
+
+func TestFlags_helmValuesMustBeScalars(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, ioutil.WriteFile(configFile, []byte(`
+helm-values:
+  global.logLevel: debug
+  server:
+    extraVolumes: [config]
+`), 0644))
+
+	tf := newTestFlags(flag.NewFlagSet("test", flag.ContinueOnError))
+	require.NoError(t, tf.flagSet.Parse([]string{"-config", configFile}))
+	require.EqualError(t, tf.Validate(), `config file: "server.extraVolumes" in "helm-values" must be a string, number or boolean`)
+}
+
+func TestFlags_namedContexts(t *testing.T) {
+	tf := newTestFlags(flag.NewFlagSet("test", flag.ContinueOnError))
+	require.NoError(t, tf.flagSet.Parse([]string{"-context", "dc1=/path/to/kubeconfig:kind-dc1,dc2=:kind-dc2,dc3=:kind-dc3:consul"}))
+	require.NoError(t, tf.Validate())
+
+	cfg := tf.TestConfigFromFlags()
+	require.True(t, cfg.EnableMultiCluster)
+
+	// The environment has only the named contexts, and the first and second
+	// are the default and secondary contexts.
+	env := environment.NewKubernetesEnvironmentFromConfig(cfg)
+	var names []string
+	for _, ctx := range env.Contexts() {
+		names = append(names, ctx.Name())
+	}
+	require.Equal(t, []string{"dc1", "dc2", "dc3"}, names)
+	require.Equal(t, "dc1", env.DefaultContext(t).Name())
+	require.Equal(t, "dc2", env.Context(t, environment.SecondaryContextName).Name())
+	require.Equal(t, "dc3", env.Context(t, "dc3").Name())
+	require.Len(t, environment.ContextsOrSkip(t, env, 3), 3)
+
+	tf = newTestFlags(flag.NewFlagSet("test", flag.ContinueOnError))
+	require.NoError(t, tf.flagSet.Parse([]string{
+		"-context", "dc1=:kind-dc1",
+		"-kubecontext", "kind-dc1",
+		"-secondary-namespace", "consul",
+		"-enable-multi-cluster",
+	}))
+	require.EqualError(t, tf.Validate(), `-kubecontext can't be combined with -context, set the first two contexts in -context instead
+-secondary-namespace can't be combined with -context, set the first two contexts in -context instead
+-context must have at least 2 contexts if -enable-multi-cluster is set`)
+}
+
+func TestParseContexts(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   []config.KubeContextConfig
+		expErr string
+	}{
+		{
+			value: "",
+			want:  nil,
+		},
+		{
+			value: "dc1=:kind-dc1, dc2=/path/to/kubeconfig:dc2:consul,dc3=:",
+			want: []config.KubeContextConfig{
+				{Name: "dc1", KubeContext: "kind-dc1"},
+				{Name: "dc2", Kubeconfig: "/path/to/kubeconfig", KubeContext: "dc2", KubeNamespace: "consul"},
+				{Name: "dc3"},
+			},
+		},
+		{
+			value:  "dc1",
+			expErr: `-context "dc1" must be in the format <name>=<kubeconfig>:<context>[:<namespace>]`,
+		},
+		{
+			value:  "dc1=kind-dc1",
+			expErr: `-context "dc1=kind-dc1" must be in the format <name>=<kubeconfig>:<context>[:<namespace>]`,
+		},
+		{
+			value:  "=:kind-dc1",
+			expErr: `-context "=:kind-dc1" must be in the format <name>=<kubeconfig>:<context>[:<namespace>]`,
+		},
+		{
+			value:  "dc1=:kind-dc1,dc1=:kind-dc2",
+			expErr: `-context "dc1" is set more than once`,
+		},
+		{
+			value: "default=:kind-dc1,secondary=:kind-dc2,dc3=:kind-dc3",
+			want: []config.KubeContextConfig{
+				{Name: "default", KubeContext: "kind-dc1"},
+				{Name: "secondary", KubeContext: "kind-dc2"},
+				{Name: "dc3", KubeContext: "kind-dc3"},
+			},
+		},
+		{
+			value:  "dc1=:kind-dc1,default=:kind-dc2",
+			expErr: `-context "default" must be the first context because it's the default context`,
+		},
+		{
+			value:  "secondary=:kind-dc2",
+			expErr: `-context "secondary" must be the second context because it's the secondary context`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			contexts, err := parseContexts(tt.value)
+			if tt.expErr != "" {
+				require.EqualError(t, err, tt.expErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.want, contexts)
+			}
+		})
+	}
+}
+
+func TestFlags_createKindClusters(t *testing.T) {
+	tf := newTestFlags(flag.NewFlagSet("test", flag.ContinueOnError))
+	require.NoError(t, tf.flagSet.Parse([]string{"-create-kind-clusters", "2", "-kind-node-image", "kindest/node:v1.21.1"}))
+	// -enable-multi-cluster doesn't need the secondary context to be set
+	// because it's created.
+	require.NoError(t, tf.Validate())
+
+	cfg := tf.TestConfigFromFlags()
+	require.Equal(t, 2, cfg.KindClusters)
+	require.Equal(t, "kindest/node:v1.21.1", cfg.KindNodeImage)
+	require.True(t, cfg.UseKind)
+	require.True(t, cfg.EnableMultiCluster)
+
+	tf = newTestFlags(flag.NewFlagSet("test", flag.ContinueOnError))
+	require.NoError(t, tf.flagSet.Parse([]string{"-create-kind-clusters", "-1"}))
+	require.EqualError(t, tf.Validate(), "-create-kind-clusters must not be negative")
+
+	tf = newTestFlags(flag.NewFlagSet("test", flag.ContinueOnError))
+	require.NoError(t, tf.flagSet.Parse([]string{"-create-kind-clusters", "2", "-context", "dc1=:kind-dc1,dc2=:kind-dc2"}))
+	require.EqualError(t, tf.Validate(), "-create-kind-clusters can't be combined with -context because the contexts are the kind clusters")
+}
@@ -0,0 +1,120 @@
+// Package helmvalues has typed structs for the values of the Helm chart so
+// that a typo in a key is a compile error rather than a confusing test
+// failure. The structs in values.go are generated from values.yaml by
+// hack/helm-reference-gen. Regenerate them with: make gen-go-values
+package helmvalues
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// String returns a pointer to s, for setting string fields.
+func String(s string) *string {
+	return &s
+}
+
+// Boolean is the value of a boolean key. Besides "true" and "false" it can
+// be "-", which means the key inherits its value, e.g. from global.enabled.
+type Boolean string
+
+// Bool returns a pointer to b, for setting boolean fields.
+func Bool(b bool) *Boolean {
+	v := Boolean(strconv.FormatBool(b))
+	return &v
+}
+
+// Inherit returns a pointer to "-", for setting boolean fields that inherit
+// their value, e.g. from global.enabled.
+func Inherit() *Boolean {
+	v := Boolean("-")
+	return &v
+}
+
+// Int returns a pointer to i, for setting integer fields.
+func Int(i int) *int {
+	return &i
+}
+
+// ToSetValues flattens v into the maps of keys to values that are passed to
+// `helm install`. String fields are in strValues, to be passed with
+// --set-string, because --set would turn strings like "3" or "true" into
+// numbers and booleans. The other fields are in values, to be passed with
+// --set. consul.NewHelmClusterFromValues passes them both. Nil fields aren't
+// set. Elements of lists are set by index, e.g.
+// "server.extraVolumes[0].name", and an empty but non-nil list is set to
+// "{}". Dots in map keys are escaped, as are commas and backslashes in
+// values so they aren't split into lists.
+func ToSetValues(v *Values) (values map[string]string, strValues map[string]string) {
+	values = make(map[string]string)
+	strValues = make(map[string]string)
+	flatten("", reflect.ValueOf(v), values, strValues)
+	return values, strValues
+}
+
+// booleanType is the type of Boolean, which is a string but is set with
+// --set so that Helm parses it as a boolean.
+var booleanType = reflect.TypeOf(Boolean(""))
+
+// flatten adds the --set values for v at key to out, or to strOut if v is a
+// string.
+func flatten(key string, v reflect.Value, out map[string]string, strOut map[string]string) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			flatten(key, v.Elem(), out, strOut)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			name := strings.Split(v.Type().Field(i).Tag.Get("yaml"), ",")[0]
+			flatten(joinKey(key, name), v.Field(i), out, strOut)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			name := fmt.Sprint(iter.Key().Interface())
+			flatten(joinKey(key, strings.ReplaceAll(name, ".", `\.`)), iter.Value(), out, strOut)
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			return
+		}
+		if v.Len() == 0 {
+			out[key] = "{}"
+		}
+		for i := 0; i < v.Len(); i++ {
+			flatten(fmt.Sprintf("%s[%d]", key, i), v.Index(i), out, strOut)
+		}
+	case reflect.String:
+		if v.Type() == booleanType {
+			out[key] = v.String()
+		} else {
+			strOut[key] = escapeValue(v.String())
+		}
+	case reflect.Bool:
+		out[key] = strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		out[key] = strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		out[key] = strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		out[key] = strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	}
+}
+
+// joinKey returns the key of name under parent.
+func joinKey(parent string, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// escapeValue escapes the characters in a --set value that Helm would
+// otherwise treat specially.
+func escapeValue(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, ",", `\,`)
+}
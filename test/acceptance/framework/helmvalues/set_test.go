@@ -0,0 +1,145 @@
+package helmvalues
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToSetValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  *Values
+		want    map[string]string
+		wantStr map[string]string
+	}{
+		{
+			name:    "nil values",
+			values:  nil,
+			want:    map[string]string{},
+			wantStr: map[string]string{},
+		},
+		{
+			name: "nested keys",
+			values: &Values{
+				Global: &GlobalValues{
+					Image: String("hashicorp/consul:1.10.0"),
+					TLS: &GlobalTLSValues{
+						Enabled: Bool(true),
+					},
+				},
+				Server: &ServerValues{
+					Replicas: Int(3),
+				},
+				ConnectInject: &ConnectInjectValues{
+					TransparentProxy: &ConnectInjectTransparentProxyValues{
+						DefaultEnabled: Bool(false),
+					},
+				},
+			},
+			want: map[string]string{
+				"global.tls.enabled": "true",
+				"server.replicas":    "3",
+				"connectInject.transparentProxy.defaultEnabled": "false",
+			},
+			wantStr: map[string]string{
+				"global.image": "hashicorp/consul:1.10.0",
+			},
+		},
+		{
+			name: "booleans that inherit and strings that look like other types",
+			values: &Values{
+				Global: &GlobalValues{
+					Datacenter: String("3"),
+				},
+				Server: &ServerValues{
+					Enabled: Inherit(),
+				},
+				UI: &UIValues{
+					Enabled: Bool(true),
+				},
+			},
+			want: map[string]string{
+				"server.enabled": "-",
+				"ui.enabled":     "true",
+			},
+			wantStr: map[string]string{
+				"global.datacenter": "3",
+			},
+		},
+		{
+			name: "lists",
+			values: &Values{
+				Global: &GlobalValues{
+					Recursors: []string{"1.1.1.1", "8.8.8.8"},
+					TLS: &GlobalTLSValues{
+						ServerAdditionalDNSSANs: []string{},
+					},
+				},
+				Server: &ServerValues{
+					ExtraVolumes: []ServerExtraVolumesItem{
+						{
+							Type: String("secret"),
+							Name: String("certs"),
+							Items: []ServerExtraVolumesItemsItem{
+								{Key: String("tls.crt"), Path: String("tls.crt")},
+							},
+						},
+					},
+				},
+			},
+			want: map[string]string{
+				"global.tls.serverAdditionalDNSSANs": "{}",
+			},
+			wantStr: map[string]string{
+				"global.recursors[0]":                  "1.1.1.1",
+				"global.recursors[1]":                  "8.8.8.8",
+				"server.extraVolumes[0].type":          "secret",
+				"server.extraVolumes[0].name":          "certs",
+				"server.extraVolumes[0].items[0].key":  "tls.crt",
+				"server.extraVolumes[0].items[0].path": "tls.crt",
+			},
+		},
+		{
+			name: "maps and escaping",
+			values: &Values{
+				Client: &ClientValues{
+					NodeMeta: map[string]interface{}{
+						"consul.hashicorp.com/zone": "a,b",
+						"nested": map[string]interface{}{
+							"key": `C:\path`,
+						},
+					},
+				},
+			},
+			want: map[string]string{},
+			wantStr: map[string]string{
+				`client.nodeMeta.consul\.hashicorp\.com/zone`: `a\,b`,
+				"client.nodeMeta.nested.key":                  `C:\\path`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, strValues := ToSetValues(tt.values)
+			require.Equal(t, tt.want, values)
+			require.Equal(t, tt.wantStr, strValues)
+		})
+	}
+}
+
+func ExampleToSetValues() {
+	values, strValues := ToSetValues(&Values{
+		Global: &GlobalValues{
+			Datacenter: String("dc1"),
+		},
+		ConnectInject: &ConnectInjectValues{
+			Enabled: Bool(true),
+		},
+	})
+	// Pass values with --set and strValues with --set-string.
+	fmt.Println(values, strValues)
+	// Output: map[connectInject.enabled:true] map[global.datacenter:dc1]
+}
@@ -0,0 +1,241 @@
+package helmvalues
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// FreeFormKeys are the keys of maps that can have any sub-keys, e.g. labels.
+// The sub-keys of these keys aren't validated by ValidateSetValues. Maps
+// whose default is empty, e.g. server.extraEnvironmentVars, are always
+// free-form so they don't need to be added.
+var FreeFormKeys = []string{
+	"server.extraLabels",
+	"server.securityContext",
+	"client.extraLabels",
+	"client.nodeMeta",
+	"client.securityContext",
+	"syncCatalog.extraLabels",
+}
+
+// ItemDefaults maps lists to the map of defaults for their elements. The
+// elements of these lists can set any key of the defaults as well as the
+// keys of the list's default element.
+var ItemDefaults = map[string]string{
+	"ingressGateways.gateways":     "ingressGateways.defaults",
+	"terminatingGateways.gateways": "terminatingGateways.defaults",
+}
+
+// ValidateSetValues returns an error if any of the --set style values, e.g.
+// {"server.replicas": "3"}, sets a key that isn't in the values.yaml of the
+// chart at chartPath, or sets a key to a value that doesn't match the type of
+// its default. Elements of lists are set by index, e.g.
+// "ingressGateways.gateways[0].name", and are checked against the first
+// element of the list's default. Elements of lists whose default is empty
+// aren't checked. Any key can be set to "null".
+func ValidateSetValues(chartPath string, values map[string]string) error {
+	valuesYAML, err := ioutil.ReadFile(filepath.Join(chartPath, "values.yaml"))
+	if err != nil {
+		return err
+	}
+	var defaults map[interface{}]interface{}
+	if err := yaml.Unmarshal(valuesYAML, &defaults); err != nil {
+		return err
+	}
+	return validateSetValues(defaults, values)
+}
+
+// validateSetValues returns an error if any of the values doesn't match
+// defaults. See ValidateSetValues.
+func validateSetValues(defaults map[interface{}]interface{}, values map[string]string) error {
+	var keys []string
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var msgs []string
+	for _, key := range keys {
+		if err := validateSetValue(defaults, key, values[key]); err != nil {
+			msgs = append(msgs, fmt.Sprintf("%q: %s", key, err))
+		}
+	}
+	if len(msgs) > 0 {
+		return fmt.Errorf("invalid Helm values:\n%s", strings.Join(msgs, "\n"))
+	}
+	return nil
+}
+
+// validateSetValue returns an error if key isn't in defaults or value
+// doesn't match the type of its default.
+func validateSetValue(defaults map[interface{}]interface{}, key string, value string) error {
+	var cur interface{} = defaults
+	var path string
+	for _, part := range splitKey(key) {
+		if contains(FreeFormKeys, path) {
+			return nil
+		}
+		name, indexes, err := parseKeyPart(part)
+		if err != nil {
+			return err
+		}
+
+		m, ok := cur.(map[interface{}]interface{})
+		switch {
+		case !ok && cur == nil:
+			return fmt.Errorf("%q has no default sub-keys, add it to helmvalues.FreeFormKeys if it's a free-form map", path)
+		case !ok:
+			return fmt.Errorf("%q isn't a map", path)
+		case len(m) == 0 && path != "":
+			return nil
+		}
+		path = joinKey(path, name)
+		cur, ok = m[name]
+		if !ok {
+			return fmt.Errorf("%q doesn't exist in values.yaml", path)
+		}
+
+		for _, i := range indexes {
+			list, ok := cur.([]interface{})
+			if !ok {
+				return fmt.Errorf("%q isn't a list", path)
+			}
+			listPath := path
+			path = fmt.Sprintf("%s[%d]", path, i)
+			if len(list) == 0 {
+				return nil
+			}
+			cur = list[0]
+			if defaultsPath, ok := ItemDefaults[listPath]; ok {
+				cur = mergeItemDefaults(lookup(defaults, defaultsPath), cur)
+			}
+		}
+	}
+	return validateType(cur, value)
+}
+
+// validateType returns an error if the --set value doesn't match the type of
+// def, the default of its key.
+func validateType(def interface{}, value string) error {
+	if value == "null" {
+		return nil
+	}
+	switch def.(type) {
+	case bool:
+		// Booleans can also be set to "-" to inherit their value, e.g. from
+		// global.enabled.
+		if !strings.EqualFold(value, "true") && !strings.EqualFold(value, "false") && value != "-" {
+			return fmt.Errorf("must be a boolean or \"-\", got %q", value)
+		}
+	case int:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("must be an integer, got %q", value)
+		}
+	case float64:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("must be a number, got %q", value)
+		}
+	case []interface{}:
+		if !strings.HasPrefix(value, "{") || !strings.HasSuffix(value, "}") {
+			return fmt.Errorf("must be a list, e.g. {a,b}, got %q", value)
+		}
+	case map[interface{}]interface{}:
+		return fmt.Errorf("is a map, set its keys instead")
+	}
+	return nil
+}
+
+// splitKey splits key on the dots that aren't escaped with a backslash.
+func splitKey(key string) []string {
+	var parts []string
+	var part strings.Builder
+	for i := 0; i < len(key); i++ {
+		switch {
+		case key[i] == '\\' && i+1 < len(key) && key[i+1] == '.':
+			part.WriteByte('.')
+			i++
+		case key[i] == '.':
+			parts = append(parts, part.String())
+			part.Reset()
+		default:
+			part.WriteByte(key[i])
+		}
+	}
+	return append(parts, part.String())
+}
+
+// parseKeyPart splits a part of a key into its name and list indexes, e.g.
+// "gateways[0]" into "gateways" and [0].
+func parseKeyPart(part string) (string, []int, error) {
+	open := strings.Index(part, "[")
+	if open == -1 {
+		return part, nil, nil
+	}
+	name, rest := part[:open], part[open:]
+	var indexes []int
+	for rest != "" {
+		end := strings.Index(rest, "]")
+		if !strings.HasPrefix(rest, "[") || end == -1 {
+			return "", nil, fmt.Errorf("%q has a malformed list index", part)
+		}
+		i, err := strconv.Atoi(rest[1:end])
+		if err != nil || i < 0 {
+			return "", nil, fmt.Errorf("%q has a malformed list index", part)
+		}
+		indexes = append(indexes, i)
+		rest = rest[end+1:]
+	}
+	return name, indexes, nil
+}
+
+// lookup returns the default at the dotted path in defaults, or nil if it
+// doesn't exist.
+func lookup(defaults map[interface{}]interface{}, path string) interface{} {
+	var cur interface{} = defaults
+	for _, name := range strings.Split(path, ".") {
+		m, ok := cur.(map[interface{}]interface{})
+		if !ok {
+			return nil
+		}
+		cur = m[name]
+	}
+	return cur
+}
+
+// mergeItemDefaults returns the keys of the map defaults together with the
+// keys of the list element elem. elem is returned as is if either isn't a
+// map.
+func mergeItemDefaults(defaults interface{}, elem interface{}) interface{} {
+	defaultsMap, ok := defaults.(map[interface{}]interface{})
+	if !ok {
+		return elem
+	}
+	elemMap, ok := elem.(map[interface{}]interface{})
+	if !ok {
+		return elem
+	}
+	merged := make(map[interface{}]interface{})
+	for k, v := range defaultsMap {
+		merged[k] = v
+	}
+	for k, v := range elemMap {
+		merged[k] = v
+	}
+	return merged
+}
+
+// contains returns true if s is in list.
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package helmvalues
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+const testValuesYAML = `
+global:
+  enabled: true
+  image: "hashicorp/consul:1.10.0"
+  recursors: []
+server:
+  replicas: 3
+  extraConfig: |
+    {}
+  extraLabels: null
+  extraEnvironmentVars: {}
+  extraVolumes: []
+  resources:
+    requests:
+      cpu: "100m"
+  securityContext:
+    runAsUser: 100
+ingressGateways:
+  defaults:
+    replicas: 2
+    consulNamespace: "default"
+  gateways:
+    - name: ingress-gateway
+annotations.io:
+  enabled: false
+`
+
+func TestValidateSetValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]string
+		expErr string
+	}{
+		{
+			name: "valid keys",
+			values: map[string]string{
+				"global.enabled":                "false",
+				"global.image":                  "consul:test",
+				"global.recursors":              "{1.1.1.1,8.8.8.8}",
+				"server.replicas":               "1",
+				"server.extraConfig":            `"{"log_level": "DEBUG"}"`,
+				"server.resources.requests.cpu": "50m",
+				`annotations\.io.enabled`:       "true",
+			},
+		},
+		{
+			name:   "booleans can be set to - to inherit their value",
+			values: map[string]string{"global.enabled": "-"},
+		},
+		{
+			name: "any key can be null",
+			values: map[string]string{
+				"server.replicas":  "null",
+				"server.resources": "null",
+			},
+		},
+		{
+			name: "free-form maps",
+			values: map[string]string{
+				"server.extraLabels.app":                 "consul",
+				"server.extraEnvironmentVars.HTTP_PROXY": "proxy",
+				"server.securityContext.runAsGroup":      "1000",
+			},
+		},
+		{
+			name: "list elements",
+			values: map[string]string{
+				"ingressGateways.gateways[0].name":            "ingress-gateway",
+				"ingressGateways.gateways[1].replicas":        "1",
+				"ingressGateways.gateways[0].consulNamespace": "ns",
+				"server.extraVolumes[0].name":                 "data",
+			},
+		},
+		{
+			name:   "unknown key",
+			values: map[string]string{"global.enable": "true"},
+			expErr: "invalid Helm values:\n\"global.enable\": \"global.enable\" doesn't exist in values.yaml",
+		},
+		{
+			name:   "unknown list element key",
+			values: map[string]string{"ingressGateways.gateways[0].nmae": "gw"},
+			expErr: "invalid Helm values:\n\"ingressGateways.gateways[0].nmae\": \"ingressGateways.gateways[0].nmae\" doesn't exist in values.yaml",
+		},
+		{
+			name:   "sub-key of a null key",
+			values: map[string]string{"global.image.tag": "1.10.0", "server.extraLabels.app": "consul"},
+			expErr: "invalid Helm values:\n\"global.image.tag\": \"global.image\" isn't a map",
+		},
+		{
+			name: "wrong types",
+			values: map[string]string{
+				"global.enabled":     "yes",
+				"global.recursors":   "1.1.1.1",
+				"server.replicas":    "three",
+				"server.resources":   "100m",
+				"global.image[0]":    "consul",
+				"server.replicas[0]": "1",
+			},
+			expErr: "invalid Helm values:\n" +
+				"\"global.enabled\": must be a boolean or \"-\", got \"yes\"\n" +
+				"\"global.image[0]\": \"global.image\" isn't a list\n" +
+				"\"global.recursors\": must be a list, e.g. {a,b}, got \"1.1.1.1\"\n" +
+				"\"server.replicas\": must be an integer, got \"three\"\n" +
+				"\"server.replicas[0]\": \"server.replicas\" isn't a list\n" +
+				"\"server.resources\": is a map, set its keys instead",
+		},
+		{
+			name:   "malformed index",
+			values: map[string]string{"ingressGateways.gateways[a].name": "gw"},
+			expErr: "invalid Helm values:\n\"ingressGateways.gateways[a].name\": \"gateways[a]\" has a malformed list index",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var defaults map[interface{}]interface{}
+			require.NoError(t, yaml.Unmarshal([]byte(testValuesYAML), &defaults))
+			err := validateSetValues(defaults, tt.values)
+			if tt.expErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.expErr)
+			}
+		})
+	}
+}
+
+func TestValidateSetValues_NullWithoutSubKeys(t *testing.T) {
+	defaults := map[interface{}]interface{}{
+		"client": map[interface{}]interface{}{"nodeSelector": nil},
+	}
+	err := validateSetValues(defaults, map[string]string{"client.nodeSelector.zone": "a"})
+	require.EqualError(t, err, "invalid Helm values:\n\"client.nodeSelector.zone\": \"client.nodeSelector\" has no default sub-keys, add it to helmvalues.FreeFormKeys if it's a free-form map")
+}
+
+// Test that the values the framework sets by default are valid for the chart.
+func TestValidateSetValues_Chart(t *testing.T) {
+	err := ValidateSetValues("../../../..", map[string]string{
+		"server.replicas":                               "1",
+		"server.bootstrapExpect":                        "1",
+		"connectInject.envoyExtraArgs":                  "--log-level debug",
+		"connectInject.logLevel":                        "debug",
+		"connectInject.transparentProxy.defaultEnabled": "false",
+		"dns.enabled":                                   "false",
+		"ingressGateways.gateways[0].name":              "ingress-gateway",
+		"ingressGateways.gateways[0].consulNamespace":   "ns",
+		"client.extraLabels.app":                        "consul",
+	})
+	require.NoError(t, err)
+}
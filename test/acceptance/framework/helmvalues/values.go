@@ -0,0 +1,2683 @@
+// Code generated by helm-reference-gen from values.yaml. DO NOT EDIT.
+// Regenerate with: make gen-go-values
+
+package helmvalues
+
+// Values are the values of the Helm chart.
+type Values struct {
+	// Holds values that affect multiple components of the chart.
+	Global *GlobalValues `yaml:"global,omitempty"`
+
+	// Server, when enabled, configures a server cluster to run. This should
+	// be disabled if you plan on connecting to a Consul cluster external to
+	// the Kube cluster.
+	Server *ServerValues `yaml:"server,omitempty"`
+
+	// Configuration for Consul servers when the servers are running outside of Kubernetes.
+	// When running external servers, configuring these values is recommended
+	// if setting `global.tls.enableAutoEncrypt` to true (requires consul-k8s >= 0.13.0)
+	// or `global.acls.manageSystemACLs` to true (requires consul-k8s >= 0.14.0).
+	ExternalServers *ExternalServersValues `yaml:"externalServers,omitempty"`
+
+	// Values that configure running a Consul client on Kubernetes nodes.
+	Client *ClientValues `yaml:"client,omitempty"`
+
+	// Configuration for DNS configuration within the Kubernetes cluster.
+	// This creates a service that routes to all agents (client or server)
+	// for serving DNS requests. This DOES NOT automatically configure kube-dns
+	// today, so you must still manually configure a `stubDomain` with kube-dns
+	// for this to have any effect:
+	// https://kubernetes.io/docs/tasks/administer-cluster/dns-custom-nameservers/#configure-stub-domain-and-upstream-dns-servers
+	DNS *DNSValues `yaml:"dns,omitempty"`
+
+	// Values that configure the Consul UI.
+	UI *UIValues `yaml:"ui,omitempty"`
+
+	// Configure the catalog sync process to sync K8S with Consul
+	// services. This can run bidirectional (default) or unidirectionally (Consul
+	// to K8S or K8S to Consul only).
+	//
+	// This process assumes that a Consul agent is available on the host IP.
+	// This is done automatically if clients are enabled. If clients are not
+	// enabled then set the node selection so that it chooses a node with a
+	// Consul agent.
+	SyncCatalog *SyncCatalogValues `yaml:"syncCatalog,omitempty"`
+
+	// Configures the automatic Connect sidecar injector.
+	ConnectInject *ConnectInjectValues `yaml:"connectInject,omitempty"`
+
+	// Controller handles config entry custom resources.
+	// Requires consul >= 1.8.4.
+	// ServiceIntentions require consul 1.9+.
+	Controller *ControllerValues `yaml:"controller,omitempty"`
+
+	// Mesh Gateways enable Consul Connect to work across Consul datacenters.
+	MeshGateway *MeshGatewayValues `yaml:"meshGateway,omitempty"`
+
+	// Configuration options for ingress gateways. Default values for all
+	// ingress gateways are defined in `ingressGateways.defaults`. Any of
+	// these values may be overridden in `ingressGateways.gateways` for a
+	// specific gateway with the exception of annotations. Annotations will
+	// include both the default annotations and any additional ones defined
+	// for a specific gateway.
+	// Requirements: consul >= 1.8.0 and consul-k8s >= 0.16.0 if using
+	// global.acls.manageSystemACLs and consul-k8s >= 0.10.0 if not.
+	IngressGateways *IngressGatewaysValues `yaml:"ingressGateways,omitempty"`
+
+	// Configuration options for terminating gateways. Default values for all
+	// terminating gateways are defined in `terminatingGateways.defaults`. Any of
+	// these values may be overridden in `terminatingGateways.gateways` for a
+	// specific gateway with the exception of annotations. Annotations will
+	// include both the default annotations and any additional ones defined
+	// for a specific gateway.
+	// Requirements: consul >= 1.8.0 and consul-k8s >= 0.16.0 if using
+	// global.acls.manageSystemACLs and consul-k8s >= 0.10.0 if not.
+	TerminatingGateways *TerminatingGatewaysValues `yaml:"terminatingGateways,omitempty"`
+
+	// Configures a demo Prometheus installation.
+	Prometheus *PrometheusValues `yaml:"prometheus,omitempty"`
+
+	// Control whether a test Pod manifest is generated when running helm template.
+	// When using helm install, the test Pod is not submitted to the cluster so this
+	// is only useful when running helm template.
+	Tests *TestsValues `yaml:"tests,omitempty"`
+}
+
+// GlobalValues are the values under `global`.
+type GlobalValues struct {
+	// The main enabled/disabled setting. If true, servers,
+	// clients, Consul DNS and the Consul UI will be enabled. Each component can override
+	// this default via its component-specific "enabled" config. If false, no components
+	// will be installed by default and per-component opt-in is required, such as by
+	// setting `server.enabled` to true.
+	//
+	// Default: true
+	Enabled *Boolean `yaml:"enabled,omitempty"`
+
+	// The default log level to apply to all components which do not otherwise override this setting.
+	// It is recommended to generally not set this below "info" unless actively debugging due to logging verbosity.
+	// One of "debug", "info", "warn", or "error".
+	//
+	// Default: info
+	LogLevel *string `yaml:"logLevel,omitempty"`
+
+	// Enable all component logs to be output in JSON format.
+	//
+	// Default: false
+	LogJSON *Boolean `yaml:"logJSON,omitempty"`
+
+	// Set the prefix used for all resources in the Helm chart. If not set,
+	// the prefix will be `<helm release name>-consul`.
+	//
+	// Default: null
+	Name *string `yaml:"name,omitempty"`
+
+	// The domain Consul will answer DNS queries for
+	// (see `-domain` (https://consul.io/docs/agent/options#_domain)) and the domain services synced from
+	// Consul into Kubernetes will have, e.g. `service-name.service.consul`.
+	//
+	// Default: consul
+	Domain *string `yaml:"domain,omitempty"`
+
+	// The name (and tag) of the Consul Docker image for clients and servers.
+	// This can be overridden per component. This should be pinned to a specific
+	// version tag, otherwise you may inadvertently upgrade your Consul version.
+	//
+	// Examples:
+	//
+	// ```yaml
+	// # Consul 1.10.0
+	// image: "consul:1.10.0"
+	// ```
+	//
+	// ```yaml
+	// # Consul Enterprise 1.10.0
+	// image: "hashicorp/consul-enterprise:1.10.0-ent"
+	// ```
+	//
+	// Default: hashicorp/consul:<latest version>
+	Image *string `yaml:"image,omitempty"`
+
+	// Array of objects containing image pull secret names that will be applied to each service account.
+	// This can be used to reference image pull secrets if using a custom consul or consul-k8s Docker image.
+	// See https://kubernetes.io/docs/concepts/containers/images/#using-a-private-registry for reference.
+	//
+	// Example:
+	//
+	// ```yaml
+	// imagePullSecrets:
+	//   - name: pull-secret-name
+	//   - name: pull-secret-name-2
+	// ```
+	ImagePullSecrets []GlobalImagePullSecretsItem `yaml:"imagePullSecrets,omitempty"`
+
+	// The name (and tag) of the consul-k8s (https://github.com/hashicorp/consul-k8s)
+	// Docker image that is used for functionality such the catalog sync.
+	// This can be overridden per component.
+	//
+	// Default: hashicorp/consul-k8s:<latest version>
+	ImageK8S *string `yaml:"imageK8S,omitempty"`
+
+	// The name of the datacenter that the agents should
+	// register as. This can't be changed once the Consul cluster is up and running
+	// since Consul doesn't support an automatic way to change this value currently:
+	// https://github.com/hashicorp/consul/issues/1858.
+	//
+	// Default: dc1
+	Datacenter *string `yaml:"datacenter,omitempty"`
+
+	// Controls whether pod security policies are created for the Consul components
+	// created by this chart. See https://kubernetes.io/docs/concepts/policy/pod-security-policy/.
+	//
+	// Default: false
+	EnablePodSecurityPolicies *Boolean `yaml:"enablePodSecurityPolicies,omitempty"`
+
+	// Configures which Kubernetes secret to retrieve Consul's
+	// gossip encryption key from (see `-encrypt` (https://consul.io/docs/agent/options#_encrypt)). If secretName or
+	// secretKey are not set, gossip encryption will not be enabled. The secret must
+	// be in the same namespace that Consul is installed into.
+	//
+	// The secret can be created by running:
+	//
+	// ```shell
+	// $ kubectl create secret generic consul-gossip-encryption-key --from-literal=key=$(consul keygen)
+	// ```
+	//
+	// To reference, use:
+	//
+	// ```yaml
+	// global:
+	//   gossipEncryption:
+	//     secretName: consul-gossip-encryption-key
+	//     secretKey: key
+	// ```
+	GossipEncryption *GlobalGossipEncryptionValues `yaml:"gossipEncryption,omitempty"`
+
+	// A list of addresses of upstream DNS servers that are used to recursively resolve DNS queries.
+	// These values are given as `-recursor` flags to Consul servers and clients.
+	// See https://www.consul.io/docs/agent/options#_recursor for more details.
+	// If this is an empty array (the default), then Consul DNS will only resolve queries for the Consul top level domain (by default `.consul`).
+	//
+	// Default: []
+	Recursors []string `yaml:"recursors,omitempty"`
+
+	// Enables TLS (https://learn.hashicorp.com/tutorials/consul/tls-encryption-secure)
+	// across the cluster to verify authenticity of the Consul servers and clients.
+	// Requires Consul v1.4.1+ and consul-k8s v0.16.2+
+	TLS *GlobalTLSValues `yaml:"tls,omitempty"`
+
+	// `enableConsulNamespaces` indicates that you are running
+	// Consul Enterprise v1.7+ with a valid Consul Enterprise license and would
+	// like to make use of configuration beyond registering everything into
+	// the `default` Consul namespace. Requires consul-k8s v0.12+. Additional configuration
+	// options are found in the `consulNamespaces` section of both the catalog sync
+	// and connect injector.
+	//
+	// Default: false
+	EnableConsulNamespaces *Boolean `yaml:"enableConsulNamespaces,omitempty"`
+
+	// Configure ACLs.
+	ACLs *GlobalACLsValues `yaml:"acls,omitempty"`
+
+	// Configure federation.
+	Federation *GlobalFederationValues `yaml:"federation,omitempty"`
+
+	// Configures metrics for Consul service mesh
+	Metrics *GlobalMetricsValues `yaml:"metrics,omitempty"`
+
+	// For connect-injected pods, the consul sidecar is responsible for metrics merging. For ingress/mesh/terminating
+	// gateways, it additionally ensures the Consul services are always registered with their local Consul client.
+	ConsulSidecarContainer map[string]interface{} `yaml:"consulSidecarContainer,omitempty"`
+
+	// The name (and tag) of the Envoy Docker image used for the
+	// connect-injected sidecar proxies and mesh, terminating, and ingress gateways.
+	// See https://www.consul.io/docs/connect/proxies/envoy for full compatibility matrix between Consul and Envoy.
+	//
+	// Default: envoyproxy/envoy-alpine:<latest supported version>
+	ImageEnvoy *string `yaml:"imageEnvoy,omitempty"`
+
+	// Configuration for running this Helm chart on the Red Hat OpenShift platform.
+	// This Helm chart currently supports OpenShift v4.x+.
+	Openshift *GlobalOpenshiftValues `yaml:"openshift,omitempty"`
+}
+
+// ServerValues are the values under `server`.
+type ServerValues struct {
+	// If true, the chart will install all the resources necessary for a
+	// Consul server cluster. If you're running Consul externally and want agents
+	// within Kubernetes to join that cluster, this should probably be false.
+	//
+	// Default: global.enabled
+	Enabled *Boolean `yaml:"enabled,omitempty"`
+
+	// The name of the Docker image (including any tag) for the containers running
+	// Consul server agents.
+	//
+	// Default: null
+	Image *string `yaml:"image,omitempty"`
+
+	// The number of server agents to run. This determines the fault tolerance of
+	// the cluster. Please see the deployment table (https://consul.io/docs/internals/consensus#deployment-table)
+	// for more information.
+	//
+	// Default: 3
+	Replicas *int `yaml:"replicas,omitempty"`
+
+	// The number of servers that are expected to be running.
+	// It defaults to server.replicas.
+	// In most cases the default should be used, however if there are more
+	// servers in this datacenter than server.replicas it might make sense
+	// to override the default. This would be the case if two kube clusters
+	// were joined into the same datacenter and each cluster ran a certain number
+	// of servers.
+	//
+	// Default: null
+	BootstrapExpect *int `yaml:"bootstrapExpect,omitempty"`
+
+	// This value refers to a Kubernetes secret that you have created
+	// that contains your enterprise license. It is required if you are using an
+	// enterprise binary. Defining it here applies it to your cluster once a leader
+	// has been elected. If you are not using an enterprise image or if you plan to
+	// introduce the license key via another route, then set these fields to null.
+	// Note: the job to apply license runs on both Helm installs and upgrades.
+	EnterpriseLicense *ServerEnterpriseLicenseValues `yaml:"enterpriseLicense,omitempty"`
+
+	// A Kubernetes secret containing a certificate & key for the server agents to use
+	// for TLS communication within the Consul cluster. Cert needs to be provided with
+	// additional DNS name SANs so that it will work within the Kubernetes cluster:
+	//
+	// ```bash
+	// consul tls cert create -server -days=730 -domain=consul -ca=consul-agent-ca.pem \
+	//     -key=consul-agent-ca-key.pem -dc={{datacenter}} \
+	//     -additional-dnsname="{{fullname}}-server" \
+	//     -additional-dnsname="*.{{fullname}}-server" \
+	//     -additional-dnsname="*.{{fullname}}-server.{{namespace}}" \
+	//     -additional-dnsname="*.{{fullname}}-server.{{namespace}}.svc" \
+	//     -additional-dnsname="*.server.{{datacenter}}.{{domain}}" \
+	//     -additional-dnsname="server.{{datacenter}}.{{domain}}"
+	// ```
+	//
+	// If you have generated the
+	// server-cert yourself with the consul CLI, you could use the following command
+	// to create the secret in Kubernetes:
+	//
+	// ```bash
+	// kubectl create secret generic consul-server-cert \
+	//     --from-file='tls.crt=./dc1-server-consul-0.pem'
+	//     --from-file='tls.key=./dc1-server-consul-0-key.pem'
+	// ```
+	ServerCert *ServerServerCertValues `yaml:"serverCert,omitempty"`
+
+	// Exposes the servers' gossip and RPC ports as hostPorts. To enable a client
+	// agent outside of the k8s cluster to join the datacenter, you would need to
+	// enable `server.exposeGossipAndRPCPorts`, `client.exposeGossipPorts`, and
+	// set `server.ports.serflan.port` to a port not being used on the host. Since
+	// `client.exposeGossipPorts` uses the hostPort 8301,
+	// `server.ports.serflan.port` must be set to something other than 8301.
+	//
+	// Default: false
+	ExposeGossipAndRPCPorts *Boolean `yaml:"exposeGossipAndRPCPorts,omitempty"`
+
+	// Configures ports for the consul servers.
+	Ports *ServerPortsValues `yaml:"ports,omitempty"`
+
+	// This defines the disk size for configuring the
+	// servers' StatefulSet storage. For dynamically provisioned storage classes, this is the
+	// desired size. For manually defined persistent volumes, this should be set to
+	// the disk size of the attached volume.
+	//
+	// Default: 10Gi
+	Storage *string `yaml:"storage,omitempty"`
+
+	// The StorageClass to use for the servers' StatefulSet storage. It must be
+	// able to be dynamically provisioned if you want the storage
+	// to be automatically created. For example, to use local
+	// (https://kubernetes.io/docs/concepts/storage/storage-classes/#local)
+	// storage classes, the PersistentVolumeClaims would need to be manually created.
+	// A `null` value will use the Kubernetes cluster's default StorageClass. If a default
+	// StorageClass does not exist, you will need to create one.
+	//
+	// Default: null
+	StorageClass *string `yaml:"storageClass,omitempty"`
+
+	// This will enable/disable Connect (https://consul.io/docs/connect). Setting this to true
+	// _will not_ automatically secure pod communication, this
+	// setting will only enable usage of the feature. Consul will automatically initialize
+	// a new CA and set of certificates. Additional Connect settings can be configured
+	// by setting the `server.extraConfig` value.
+	//
+	// Default: true
+	Connect *Boolean `yaml:"connect,omitempty"`
+
+	ServiceAccount *ServerServiceAccountValues `yaml:"serviceAccount,omitempty"`
+
+	// The resource requests (CPU, memory, etc.)
+	// for each of the server agents. This should be a YAML map corresponding to a Kubernetes
+	// ResourceRequirements (https://kubernetes.io/docs/reference/generated/kubernetes-api/v1.11/#resourcerequirements-v1-core)
+	// object. NOTE: The use of a YAML string is deprecated.
+	//
+	// Example:
+	//
+	// ```yaml
+	// resources:
+	//   requests:
+	//     memory: '100Mi'
+	//     cpu: '100m'
+	//   limits:
+	//     memory: '100Mi'
+	//     cpu: '100m'
+	// ```
+	Resources map[string]interface{} `yaml:"resources,omitempty"`
+
+	// The security context for the server pods. This should be a YAML map corresponding to a
+	// Kubernetes [SecurityContext](https://kubernetes.io/docs/tasks/configure-pod-container/security-context/) object.
+	// By default, servers will run as non-root, with user ID `100` and group ID `1000`,
+	// which correspond to the consul user and group created by the Consul docker image.
+	// Note: if running on OpenShift, this setting is ignored because the user and group are set automatically
+	// by the OpenShift platform.
+	SecurityContext map[string]interface{} `yaml:"securityContext,omitempty"`
+
+	// This value is used to carefully
+	// control a rolling update of Consul server agents. This value specifies the
+	// partition (https://kubernetes.io/docs/concepts/workloads/controllers/statefulset/#partitions)
+	// for performing a rolling update. Please read the linked Kubernetes documentation
+	// and https://www.consul.io/docs/k8s/upgrade#upgrading-consul-servers for more information.
+	//
+	// Default: 0
+	UpdatePartition *int `yaml:"updatePartition,omitempty"`
+
+	// This configures the PodDisruptionBudget (https://kubernetes.io/docs/tasks/run-application/configure-pdb/)
+	// for the server cluster.
+	DisruptionBudget *ServerDisruptionBudgetValues `yaml:"disruptionBudget,omitempty"`
+
+	// A raw string of extra JSON configuration (https://consul.io/docs/agent/options) for Consul
+	// servers. This will be saved as-is into a ConfigMap that is read by the Consul
+	// server agents. This can be used to add additional configuration that
+	// isn't directly exposed by the chart.
+	//
+	// Example:
+	//
+	// ```yaml
+	// extraConfig: |
+	//   {
+	//     "log_level": "DEBUG"
+	//   }
+	// ```
+	//
+	// This can also be set using Helm's `--set` flag using the following syntax:
+	//
+	// ```shell
+	// --set 'server.extraConfig="{"log_level": "DEBUG"}"'
+	// ```
+	//
+	// Default: {}
+	ExtraConfig *string `yaml:"extraConfig,omitempty"`
+
+	// A list of extra volumes to mount for server agents. This
+	// is useful for bringing in extra data that can be referenced by other configurations
+	// at a well known path, such as TLS certificates or Gossip encryption keys. The
+	// value of this should be a list of objects.
+	//
+	// Example:
+	//
+	// ```yaml
+	// extraVolumes:
+	//   - type: secret
+	//     name: consul-certs
+	//     load: false
+	// ```
+	ExtraVolumes []ServerExtraVolumesItem `yaml:"extraVolumes,omitempty"`
+
+	// This value defines the affinity (https://kubernetes.io/docs/concepts/configuration/assign-pod-node/#affinity-and-anti-affinity)
+	// for server pods. It defaults to allowing only a single server pod on each node, which
+	// minimizes risk of the cluster becoming unusable if a node is lost. If you need
+	// to run more pods per node (for example, testing on Minikube), set this value
+	// to `null`.
+	//
+	// Example:
+	//
+	// ```yaml
+	// affinity: |
+	//   podAntiAffinity:
+	//     requiredDuringSchedulingIgnoredDuringExecution:
+	//       - labelSelector:
+	//           matchLabels:
+	//             app: {{ template "consul.name" . }}
+	//             release: "{{ .Release.Name }}"
+	//             component: server
+	//       topologyKey: kubernetes.io/hostname
+	// ```
+	Affinity *string `yaml:"affinity,omitempty"`
+
+	// Toleration settings for server pods. This
+	// should be a multi-line string matching the Tolerations
+	// (https://kubernetes.io/docs/concepts/configuration/taint-and-toleration/) array in a Pod spec.
+	//
+	// Default: ""
+	Tolerations *string `yaml:"tolerations,omitempty"`
+
+	// Pod topology spread constraints for server pods.
+	// This should be a multi-line YAML string matching the `topologySpreadConstraints` array
+	// (https://kubernetes.io/docs/concepts/workloads/pods/pod-topology-spread-constraints/) in a Pod Spec.
+	//
+	// This requires K8S >= 1.18 (beta) or 1.19 (stable).
+	//
+	// Example:
+	//
+	// ```yaml
+	// topologySpreadConstraints: |
+	//   - maxSkew: 1
+	//     topologyKey: topology.kubernetes.io/zone
+	//     whenUnsatisfiable: DoNotSchedule
+	//     labelSelector:
+	//       matchLabels:
+	//         app: {{ template "consul.name" . }}
+	//         release: "{{ .Release.Name }}"
+	//         component: server
+	// ```
+	//
+	// Default: ""
+	TopologySpreadConstraints *string `yaml:"topologySpreadConstraints,omitempty"`
+
+	// This value defines `nodeSelector` (https://kubernetes.io/docs/concepts/configuration/assign-pod-node/#nodeselector)
+	// labels for server pod assignment, formatted as a multi-line string.
+	//
+	// Example:
+	//
+	// ```yaml
+	// nodeSelector: |
+	//   beta.kubernetes.io/arch: amd64
+	// ```
+	//
+	// Default: null
+	NodeSelector *string `yaml:"nodeSelector,omitempty"`
+
+	// This value references an existing
+	// Kubernetes `priorityClassName` (https://kubernetes.io/docs/concepts/configuration/pod-priority-preemption/#pod-priority)
+	// that can be assigned to server pods.
+	//
+	// Default: ""
+	PriorityClassName *string `yaml:"priorityClassName,omitempty"`
+
+	// Extra labels to attach to the server pods. This should be a YAML map.
+	//
+	// Example:
+	//
+	// ```yaml
+	// extraLabels:
+	//   labelKey: label-value
+	//   anotherLabelKey: another-label-value
+	// ```
+	ExtraLabels map[string]interface{} `yaml:"extraLabels,omitempty"`
+
+	// This value defines additional annotations for
+	// server pods. This should be formatted as a multi-line string.
+	//
+	// ```yaml
+	// annotations: |
+	//   "sample/annotation1": "foo"
+	//   "sample/annotation2": "bar"
+	// ```
+	//
+	// Default: null
+	Annotations *string `yaml:"annotations,omitempty"`
+
+	// Server service properties.
+	Service *ServerServiceValues `yaml:"service,omitempty"`
+
+	// A list of extra environment variables to set within the stateful set.
+	// These could be used to include proxy settings required for cloud auto-join
+	// feature, in case kubernetes cluster is behind egress http proxies. Additionally,
+	// it could be used to configure custom consul parameters.
+	ExtraEnvironmentVars map[string]interface{} `yaml:"extraEnvironmentVars,omitempty"`
+}
+
+// ExternalServersValues are the values under `externalServers`.
+type ExternalServersValues struct {
+	// If true, the Helm chart will be configured to talk to the external servers.
+	// If setting this to true, you must also set `server.enabled` to false.
+	//
+	// Default: false
+	Enabled *Boolean `yaml:"enabled,omitempty"`
+
+	// An array of external Consul server hosts that are used to make
+	// HTTPS connections from the components in this Helm chart.
+	// Valid values include IPs, DNS names, or Cloud auto-join string.
+	// The port must be provided separately below.
+	// Note: `client.join` must also be set to the hosts that should be
+	// used to join the cluster. In most cases, the `client.join` values
+	// should be the same, however, they may be different if you
+	// wish to use separate hosts for the HTTPS connections.
+	//
+	// Default: []
+	Hosts []string `yaml:"hosts,omitempty"`
+
+	// The HTTPS port of the Consul servers.
+	//
+	// Default: 8501
+	HttpsPort *int `yaml:"httpsPort,omitempty"`
+
+	// The server name to use as the SNI host header when connecting with HTTPS.
+	//
+	// Default: null
+	TlsServerName *string `yaml:"tlsServerName,omitempty"`
+
+	// If true, consul-k8s components will ignore the CA set in
+	// `global.tls.caCert` when making HTTPS calls to Consul servers and
+	// will instead use the consul-k8s image's system CAs for TLS verification.
+	// If false, consul-k8s components will use `global.tls.caCert` when
+	// making HTTPS calls to Consul servers.
+	// **NOTE:** This does not affect Consul's internal RPC communication which will
+	// always use `global.tls.caCert`.
+	//
+	// Default: false
+	UseSystemRoots *Boolean `yaml:"useSystemRoots,omitempty"`
+
+	// If you are setting `global.acls.manageSystemACLs` and
+	// `connectInject.enabled` to true, set `k8sAuthMethodHost` to the address of the Kubernetes API server.
+	// This address must be reachable from the Consul servers.
+	// Please see the Kubernetes Auth Method documentation (https://consul.io/docs/acl/auth-methods/kubernetes).
+	// Requires consul-k8s >= 0.14.0.
+	//
+	// You could retrieve this value from your `kubeconfig` by running:
+	//
+	// ```shell
+	// kubectl config view \
+	//   -o jsonpath="{.clusters[?(@.name=='<your cluster name>')].cluster.server}"
+	// ```
+	//
+	// Default: null
+	K8sAuthMethodHost *string `yaml:"k8sAuthMethodHost,omitempty"`
+}
+
+// ClientValues are the values under `client`.
+type ClientValues struct {
+	// If true, the chart will install all
+	// the resources necessary for a Consul client on every Kubernetes node. This _does not_ require
+	// `server.enabled`, since the agents can be configured to join an external cluster.
+	//
+	// Default: global.enabled
+	Enabled *Boolean `yaml:"enabled,omitempty"`
+
+	// The name of the Docker image (including any tag) for the containers
+	// running Consul client agents.
+	//
+	// Default: null
+	Image *string `yaml:"image,omitempty"`
+
+	// A list of valid `-retry-join` values (https://consul.io/docs/agent/options#retry-join).
+	// If this is `null` (default), then the clients will attempt to automatically
+	// join the server cluster running within Kubernetes.
+	// This means that with `server.enabled` set to true, clients will automatically
+	// join that cluster. If `server.enabled` is not true, then a value must be
+	// specified so the clients can join a valid cluster.
+	//
+	// Default: null
+	Join []string `yaml:"join,omitempty"`
+
+	// An absolute path to a directory on the host machine to use as the Consul
+	// client data directory. If set to the empty string or null, the Consul agent
+	// will store its data in the Pod's local filesystem (which will
+	// be lost if the Pod is deleted). Security Warning: If setting this, Pod Security
+	// Policies _must_ be enabled on your cluster and in this Helm chart (via the
+	// `global.enablePodSecurityPolicies` setting) to prevent other pods from
+	// mounting the same host path and gaining access to all of Consul's data.
+	// Consul's data is not encrypted at rest.
+	//
+	// Default: null
+	DataDirectoryHostPath *string `yaml:"dataDirectoryHostPath,omitempty"`
+
+	// If true, agents will enable their GRPC listener on
+	// port 8502 and expose it to the host. This will use slightly more resources, but is
+	// required for Connect.
+	//
+	// Default: true
+	GRPC *Boolean `yaml:"grpc,omitempty"`
+
+	// nodeMeta specifies an arbitrary metadata key/value pair to associate with the node
+	// (see https://www.consul.io/docs/agent/options.html#_node_meta)
+	NodeMeta map[string]interface{} `yaml:"nodeMeta,omitempty"`
+
+	// If true, the Helm chart will expose the clients' gossip ports as hostPorts.
+	// This is only necessary if pod IPs in the k8s cluster are not directly routable
+	// and the Consul servers are outside of the k8s cluster.
+	// This also changes the clients' advertised IP to the `hostIP` rather than `podIP`.
+	//
+	// Default: false
+	ExposeGossipPorts *Boolean `yaml:"exposeGossipPorts,omitempty"`
+
+	ServiceAccount *ClientServiceAccountValues `yaml:"serviceAccount,omitempty"`
+
+	// Resource settings for Client agents.
+	// NOTE: The use of a YAML string is deprecated. Instead, set directly as a
+	// YAML map.
+	Resources map[string]interface{} `yaml:"resources,omitempty"`
+
+	// The security context for the client pods. This should be a YAML map corresponding to a
+	// Kubernetes [SecurityContext](https://kubernetes.io/docs/tasks/configure-pod-container/security-context/) object.
+	// By default, servers will run as non-root, with user ID `100` and group ID `1000`,
+	// which correspond to the consul user and group created by the Consul docker image.
+	// Note: if running on OpenShift, this setting is ignored because the user and group are set automatically
+	// by the OpenShift platform.
+	SecurityContext map[string]interface{} `yaml:"securityContext,omitempty"`
+
+	// A raw string of extra JSON configuration (https://consul.io/docs/agent/options) for Consul
+	// clients. This will be saved as-is into a ConfigMap that is read by the Consul
+	// client agents. This can be used to add additional configuration that
+	// isn't directly exposed by the chart.
+	//
+	// Example:
+	//
+	// ```yaml
+	// extraConfig: |
+	//   {
+	//     "log_level": "DEBUG"
+	//   }
+	// ```
+	//
+	// This can also be set using Helm's `--set` flag using the following syntax:
+	//
+	// ```shell
+	// --set 'client.extraConfig="{"log_level": "DEBUG"}"'
+	// ```
+	//
+	// Default: {}
+	ExtraConfig *string `yaml:"extraConfig,omitempty"`
+
+	// A list of extra volumes to mount for client agents. This
+	// is useful for bringing in extra data that can be referenced by other configurations
+	// at a well known path, such as TLS certificates or Gossip encryption keys. The
+	// value of this should be a list of objects.
+	//
+	// Example:
+	//
+	// ```yaml
+	// extraVolumes:
+	//   - type: secret
+	//     name: consul-certs
+	//     load: false
+	// ```
+	ExtraVolumes []ClientExtraVolumesItem `yaml:"extraVolumes,omitempty"`
+
+	// Toleration Settings for Client pods
+	// This should be a multi-line string matching the Toleration array
+	// in a PodSpec.
+	// The example below will allow Client pods to run on every node
+	// regardless of taints
+	//
+	// ```yaml
+	// tolerations: |
+	//   - operator: Exists
+	// ```
+	//
+	// Default: ""
+	Tolerations *string `yaml:"tolerations,omitempty"`
+
+	// nodeSelector labels for client pod assignment, formatted as a multi-line string.
+	// ref: https://kubernetes.io/docs/concepts/configuration/assign-pod-node/#nodeselector
+	//
+	// Example:
+	//
+	// ```yaml
+	// nodeSelector: |
+	//   beta.kubernetes.io/arch: amd64
+	// ```
+	//
+	// Default: null
+	NodeSelector *string `yaml:"nodeSelector,omitempty"`
+
+	// Affinity Settings for Client pods, formatted as a multi-line YAML string.
+	// ref: https://kubernetes.io/docs/concepts/configuration/assign-pod-node/#affinity-and-anti-affinity
+	//
+	// Example:
+	//
+	// ```yaml
+	// affinity: |
+	//   nodeAffinity:
+	//     requiredDuringSchedulingIgnoredDuringExecution:
+	//       nodeSelectorTerms:
+	//       - matchExpressions:
+	//         - key: node-role.kubernetes.io/master
+	//           operator: DoesNotExist
+	// ```
+	//
+	// Default: null
+	Affinity *string `yaml:"affinity,omitempty"`
+
+	// This value references an existing
+	// Kubernetes `priorityClassName` (https://kubernetes.io/docs/concepts/configuration/pod-priority-preemption/#pod-priority)
+	// that can be assigned to client pods.
+	//
+	// Default: ""
+	PriorityClassName *string `yaml:"priorityClassName,omitempty"`
+
+	// This value defines additional annotations for
+	// client pods. This should be formatted as a multi-line string.
+	//
+	// ```yaml
+	// annotations: |
+	//   "sample/annotation1": "foo"
+	//   "sample/annotation2": "bar"
+	// ```
+	//
+	// Default: null
+	Annotations *string `yaml:"annotations,omitempty"`
+
+	// Extra labels to attach to the client pods. This should be a regular YAML map.
+	//
+	// Example:
+	//
+	// ```yaml
+	// extraLabels:
+	//   labelKey: label-value
+	//   anotherLabelKey: another-label-value
+	// ```
+	ExtraLabels map[string]interface{} `yaml:"extraLabels,omitempty"`
+
+	// A list of extra environment variables to set within the stateful set.
+	// These could be used to include proxy settings required for cloud auto-join
+	// feature, in case kubernetes cluster is behind egress http proxies. Additionally,
+	// it could be used to configure custom consul parameters.
+	ExtraEnvironmentVars map[string]interface{} `yaml:"extraEnvironmentVars,omitempty"`
+
+	// This value defines the Pod DNS policy (https://kubernetes.io/docs/concepts/services-networking/dns-pod-service/#pod-s-dns-policy)
+	// for client pods to use.
+	//
+	// Default: null
+	DnsPolicy *string `yaml:"dnsPolicy,omitempty"`
+
+	// hostNetwork defines whether or not we use host networking instead of hostPort in the event
+	// that a CNI plugin doesn't support `hostPort`. This has security implications and is not recommended
+	// as doing so gives the consul client unnecessary access to all network traffic on the host.
+	// In most cases, pod network and host network are on different networks so this should be
+	// combined with `dnsPolicy: ClusterFirstWithHostNet`
+	//
+	// Default: false
+	HostNetwork *Boolean `yaml:"hostNetwork,omitempty"`
+
+	// updateStrategy for the DaemonSet.
+	// See https://kubernetes.io/docs/tasks/manage-daemon/update-daemon-set/#daemonset-update-strategy.
+	// This should be a multi-line string mapping directly to the updateStrategy
+	//
+	// Example:
+	//
+	// ```yaml
+	// updateStrategy: |
+	//   rollingUpdate:
+	//     maxUnavailable: 5
+	//   type: RollingUpdate
+	// ```
+	//
+	// Default: null
+	UpdateStrategy *string `yaml:"updateStrategy,omitempty"`
+
+	// Values for setting up and running snapshot agents
+	// (https://consul.io/commands/snapshot/agent)
+	// within the Consul clusters. They are required to be co-located with Consul clients,
+	// so will inherit the clients' nodeSelector, tolerations and affinity.
+	SnapshotAgent *ClientSnapshotAgentValues `yaml:"snapshotAgent,omitempty"`
+}
+
+// DNSValues are the values under `dns`.
+type DNSValues struct {
+	// Default: -
+	Enabled *Boolean `yaml:"enabled,omitempty"`
+
+	// Used to control the type of service created. For
+	// example, setting this to "LoadBalancer" will create an external load
+	// balancer (for supported K8S installations)
+	//
+	// Default: ClusterIP
+	Type *string `yaml:"type,omitempty"`
+
+	// Set a predefined cluster IP for the DNS service.
+	// Useful if you need to reference the DNS service's IP
+	// address in CoreDNS config.
+	//
+	// Default: null
+	ClusterIP *string `yaml:"clusterIP,omitempty"`
+
+	// Extra annotations to attach to the dns service
+	// This should be a multi-line string of
+	// annotations to apply to the dns Service
+	//
+	// Default: null
+	Annotations *string `yaml:"annotations,omitempty"`
+
+	// Additional ServiceSpec values
+	// This should be a multi-line string mapping directly to a Kubernetes
+	// ServiceSpec object.
+	//
+	// Default: null
+	AdditionalSpec *string `yaml:"additionalSpec,omitempty"`
+}
+
+// UIValues are the values under `ui`.
+type UIValues struct {
+	// If true, the UI will be enabled. This will
+	// only _enable_ the UI, it doesn't automatically register any service for external
+	// access. The UI will only be enabled on server agents. If `server.enabled` is
+	// false, then this setting has no effect. To expose the UI in some way, you must
+	// configure `ui.service`.
+	//
+	// Default: global.enabled
+	Enabled *Boolean `yaml:"enabled,omitempty"`
+
+	// Configure the service for the Consul UI.
+	Service *UIServiceValues `yaml:"service,omitempty"`
+
+	// Configure Ingress for the Consul UI.
+	// If `global.tls.enabled` is set to `true`, the Ingress will expose
+	// the port 443 on the UI service. Please ensure the Ingress Controller
+	// supports SSL pass-through and it is enabled to ensure traffic forwarded
+	// to port 443 has not been TLS terminated.
+	Ingress *UIIngressValues `yaml:"ingress,omitempty"`
+
+	// Configurations for displaying metrics in the UI.
+	Metrics *UIMetricsValues `yaml:"metrics,omitempty"`
+}
+
+// SyncCatalogValues are the values under `syncCatalog`.
+type SyncCatalogValues struct {
+	// True if you want to enable the catalog sync. Set to "-" to inherit from
+	// global.enabled.
+	//
+	// Default: false
+	Enabled *Boolean `yaml:"enabled,omitempty"`
+
+	// The name of the Docker image (including any tag) for consul-k8s
+	// to run the sync program.
+	//
+	// Default: null
+	Image *string `yaml:"image,omitempty"`
+
+	// If true, all valid services in K8S are
+	// synced by default. If false, the service must be annotated
+	// (https://consul.io/docs/k8s/service-sync#sync-enable-disable) properly to sync.
+	// In either case an annotation can override the default.
+	//
+	// Default: true
+	Default *Boolean `yaml:"default,omitempty"`
+
+	// Optional priorityClassName.
+	//
+	// Default: ""
+	PriorityClassName *string `yaml:"priorityClassName,omitempty"`
+
+	// If true, will sync Kubernetes services to Consul. This can be disabled to
+	// have a one-way sync.
+	//
+	// Default: true
+	ToConsul *Boolean `yaml:"toConsul,omitempty"`
+
+	// If true, will sync Consul services to Kubernetes. This can be disabled to
+	// have a one-way sync.
+	//
+	// Default: true
+	ToK8S *Boolean `yaml:"toK8S,omitempty"`
+
+	// Service prefix to prepend to services before registering
+	// with Kubernetes. For example "consul-" will register all services
+	// prepended with "consul-". (Consul -> Kubernetes sync)
+	//
+	// Default: null
+	K8sPrefix *string `yaml:"k8sPrefix,omitempty"`
+
+	// List of k8s namespaces to sync the k8s services from.
+	// If a k8s namespace is not included in this list or is listed in `k8sDenyNamespaces`,
+	// services in that k8s namespace will not be synced even if they are explicitly
+	// annotated. Use `["*"]` to automatically allow all k8s namespaces.
+	//
+	// For example, `["namespace1", "namespace2"]` will only allow services in the k8s
+	// namespaces `namespace1` and `namespace2` to be synced and registered
+	// with Consul. All other k8s namespaces will be ignored.
+	//
+	// To deny all namespaces, set this to `[]`.
+	//
+	// Note: `k8sDenyNamespaces` takes precedence over values defined here.
+	// Requires consul-k8s v0.12+
+	//
+	// Default: ["*"]
+	K8sAllowNamespaces []string `yaml:"k8sAllowNamespaces,omitempty"`
+
+	// List of k8s namespaces that should not have their
+	// services synced. This list takes precedence over `k8sAllowNamespaces`.
+	// `*` is not supported because then nothing would be allowed to sync.
+	// Requires consul-k8s v0.12+.
+	//
+	// For example, if `k8sAllowNamespaces` is `["*"]` and `k8sDenyNamespaces` is
+	// `["namespace1", "namespace2"]`, then all k8s namespaces besides `namespace1`
+	// and `namespace2` will be synced.
+	//
+	// Default: ["kube-system", "kube-public"]
+	K8sDenyNamespaces []string `yaml:"k8sDenyNamespaces,omitempty"`
+
+	// [DEPRECATED] Use k8sAllowNamespaces and k8sDenyNamespaces instead. For
+	// backwards compatibility, if both this and the allow/deny lists are set,
+	// the allow/deny lists will be ignored.
+	// k8sSourceNamespace is the Kubernetes namespace to watch for service
+	// changes and sync to Consul. If this is not set then it will default
+	// to all namespaces.
+	//
+	// Default: null
+	K8sSourceNamespace *string `yaml:"k8sSourceNamespace,omitempty"`
+
+	// These settings manage the catalog sync's interaction with
+	// Consul namespaces (requires consul-ent v1.7+ and consul-k8s v0.12+).
+	// Also, `global.enableConsulNamespaces` must be true.
+	ConsulNamespaces *SyncCatalogConsulNamespacesValues `yaml:"consulNamespaces,omitempty"`
+
+	// Appends Kubernetes namespace suffix to
+	// each service name synced to Consul, separated by a dash.
+	// For example, for a service 'foo' in the default namespace,
+	// the sync process will create a Consul service named 'foo-default'.
+	// Set this flag to true to avoid registering services with the same name
+	// but in different namespaces as instances for the same Consul service.
+	// Namespace suffix is not added if 'annotationServiceName' is provided.
+	//
+	// Default: true
+	AddK8SNamespaceSuffix *Boolean `yaml:"addK8SNamespaceSuffix,omitempty"`
+
+	// Service prefix which prepends itself
+	// to Kubernetes services registered within Consul
+	// For example, "k8s-" will register all services prepended with "k8s-".
+	// (Kubernetes -> Consul sync)
+	// consulPrefix is ignored when 'annotationServiceName' is provided.
+	// NOTE: Updating this property to a non-null value for an existing installation will result in deregistering
+	// of existing services in Consul and registering them with a new name.
+	//
+	// Default: null
+	ConsulPrefix *string `yaml:"consulPrefix,omitempty"`
+
+	// Optional tag that is applied to all of the Kubernetes services
+	// that are synced into Consul. If nothing is set, defaults to "k8s".
+	// (Kubernetes -> Consul sync)
+	//
+	// Default: null
+	K8sTag *string `yaml:"k8sTag,omitempty"`
+
+	// Defines the Consul synthetic node that all services
+	// will be registered to.
+	// NOTE: Changing the node name and upgrading the Helm chart will leave
+	// all of the previously sync'd services registered with Consul and
+	// register them again under the new Consul node name. The out-of-date
+	// registrations will need to be explicitly removed.
+	//
+	// Default: k8s-sync
+	ConsulNodeName *string `yaml:"consulNodeName,omitempty"`
+
+	// Syncs services of the ClusterIP type, which may
+	// or may not be broadly accessible depending on your Kubernetes cluster.
+	// Set this to false to skip syncing ClusterIP services.
+	//
+	// Default: true
+	SyncClusterIPServices *Boolean `yaml:"syncClusterIPServices,omitempty"`
+
+	// Configures the type of syncing that happens for NodePort
+	// services. The valid options are: ExternalOnly, InternalOnly, ExternalFirst.
+	//
+	// - ExternalOnly will only use a node's ExternalIP address for the sync
+	// - InternalOnly use's the node's InternalIP address
+	// - ExternalFirst will preferentially use the node's ExternalIP address, but
+	//   if it doesn't exist, it will use the node's InternalIP address instead.
+	//
+	// Default: ExternalFirst
+	NodePortSyncType *string `yaml:"nodePortSyncType,omitempty"`
+
+	// Refers to a Kubernetes secret that you have created that contains
+	// an ACL token for your Consul cluster which allows the sync process the correct
+	// permissions. This is only needed if ACLs are enabled on the Consul cluster.
+	AclSyncToken *SyncCatalogAclSyncTokenValues `yaml:"aclSyncToken,omitempty"`
+
+	// This value defines `nodeSelector` (https://kubernetes.io/docs/concepts/configuration/assign-pod-node/#nodeselector)
+	// labels for catalog sync pod assignment, formatted as a multi-line string.
+	//
+	// Example:
+	//
+	// ```yaml
+	// nodeSelector: |
+	//   beta.kubernetes.io/arch: amd64
+	// ```
+	//
+	// Default: null
+	NodeSelector *string `yaml:"nodeSelector,omitempty"`
+
+	// Affinity Settings
+	// This should be a multi-line string matching the affinity object
+	//
+	// Default: null
+	Affinity *string `yaml:"affinity,omitempty"`
+
+	// Toleration Settings
+	// This should be a multi-line string matching the Toleration array
+	// in a PodSpec.
+	//
+	// Default: null
+	Tolerations *string `yaml:"tolerations,omitempty"`
+
+	ServiceAccount *SyncCatalogServiceAccountValues `yaml:"serviceAccount,omitempty"`
+
+	// Resource settings for sync catalog pods.
+	Resources map[string]interface{} `yaml:"resources,omitempty"`
+
+	// Override global log verbosity level. One of "debug", "info", "warn", or "error".
+	//
+	// Default: ""
+	LogLevel *string `yaml:"logLevel,omitempty"`
+
+	// Override the default interval to perform syncing operations creating Consul services.
+	//
+	// Default: null
+	ConsulWriteInterval *string `yaml:"consulWriteInterval,omitempty"`
+
+	// Extra labels to attach to the sync catalog pods. This should be a YAML map.
+	//
+	// Example:
+	//
+	// ```yaml
+	// extraLabels:
+	//   labelKey: label-value
+	//   anotherLabelKey: another-label-value
+	// ```
+	ExtraLabels map[string]interface{} `yaml:"extraLabels,omitempty"`
+}
+
+// ConnectInjectValues are the values under `connectInject`.
+type ConnectInjectValues struct {
+	// True if you want to enable connect injection. Set to "-" to inherit from
+	// global.enabled.
+	//
+	// Default: false
+	Enabled *Boolean `yaml:"enabled,omitempty"`
+
+	// The number of deployment replicas.
+	//
+	// Default: 2
+	Replicas *int `yaml:"replicas,omitempty"`
+
+	// Image for consul-k8s that contains the injector
+	//
+	// Default: null
+	Image *string `yaml:"image,omitempty"`
+
+	// If true, the injector will inject the
+	// Connect sidecar into all pods by default. Otherwise, pods must specify the
+	// injection annotation (https://consul.io/docs/k8s/connect#consul-hashicorp-com-connect-inject)
+	// to opt-in to Connect injection. If this is true, pods can use the same annotation
+	// to explicitly opt-out of injection.
+	//
+	// Default: false
+	Default *Boolean `yaml:"default,omitempty"`
+
+	// Configures Transparent Proxy for Consul Service mesh services.
+	// Using this feature requires Consul 1.10.0-beta1+ and consul-k8s 0.26.0-beta1+.
+	TransparentProxy *ConnectInjectTransparentProxyValues `yaml:"transparentProxy,omitempty"`
+
+	// Configures metrics for Consul Connect services. All values are overridable
+	// via annotations on a per-pod basis.
+	Metrics *ConnectInjectMetricsValues `yaml:"metrics,omitempty"`
+
+	// Used to pass arguments to the injected envoy sidecar.
+	// Valid arguments to pass to envoy can be found here: https://www.envoyproxy.io/docs/envoy/latest/operations/cli
+	// e.g "--log-level debug --disable-hot-restart"
+	//
+	// Default: null
+	EnvoyExtraArgs *string `yaml:"envoyExtraArgs,omitempty"`
+
+	// Optional priorityClassName.
+	//
+	// Default: ""
+	PriorityClassName *string `yaml:"priorityClassName,omitempty"`
+
+	// The Docker image for Consul to use when performing Connect injection.
+	// Defaults to global.image.
+	//
+	// Default: null
+	ImageConsul *string `yaml:"imageConsul,omitempty"`
+
+	// Override global log verbosity level. One of "debug", "info", "warn", or "error".
+	//
+	// Default: ""
+	LogLevel *string `yaml:"logLevel,omitempty"`
+
+	ServiceAccount *ConnectInjectServiceAccountValues `yaml:"serviceAccount,omitempty"`
+
+	// Resource settings for connect inject pods.
+	Resources map[string]interface{} `yaml:"resources,omitempty"`
+
+	// Sets the failurePolicy for the mutating webhook. By default this will cause pods not part of the consul installation to fail scheduling while the webhook
+	// is offline. This prevents a pod from skipping mutation if the webhook were to be momentarily offline.
+	// Once the webhook is back online the pod will be scheduled.
+	// In some environments such as Kind this may have an undesirable effect as it may prevent volume provisioner pods from running
+	// which can lead to hangs. In these environments it is recommend to use "Ignore" instead.
+	// This setting can be safely disabled by setting to "Ignore".
+	//
+	// Default: Fail
+	FailurePolicy *string `yaml:"failurePolicy,omitempty"`
+
+	// Selector for restricting the webhook to only
+	// specific namespaces. This should be set to a multiline string.
+	// See https://kubernetes.io/docs/reference/access-authn-authz/extensible-admission-controllers/#matching-requests-namespaceselector
+	// for more details.
+	//
+	// Example:
+	//
+	// ```yaml
+	// namespaceSelector: |
+	//   matchLabels:
+	//     namespace-label: label-value
+	// ```
+	//
+	// Default: null
+	NamespaceSelector *string `yaml:"namespaceSelector,omitempty"`
+
+	// List of k8s namespaces to allow Connect sidecar
+	// injection in. If a k8s namespace is not included or is listed in `k8sDenyNamespaces`,
+	// pods in that k8s namespace will not be injected even if they are explicitly
+	// annotated. Use `["*"]` to automatically allow all k8s namespaces.
+	//
+	// For example, `["namespace1", "namespace2"]` will only allow pods in the k8s
+	// namespaces `namespace1` and `namespace2` to have Connect sidecars injected
+	// and registered with Consul. All other k8s namespaces will be ignored.
+	//
+	// To deny all namespaces, set this to `[]`.
+	//
+	// Note: `k8sDenyNamespaces` takes precedence over values defined here and
+	// `namespaceSelector` takes precedence over both since it is applied first.
+	// `kube-system` and `kube-public` are never injected, even if included here.
+	// Requires consul-k8s v0.12+
+	//
+	// Default: ["*"]
+	K8sAllowNamespaces []string `yaml:"k8sAllowNamespaces,omitempty"`
+
+	// List of k8s namespaces that should not allow Connect
+	// sidecar injection. This list takes precedence over `k8sAllowNamespaces`.
+	// `*` is not supported because then nothing would be allowed to be injected.
+	//
+	// For example, if `k8sAllowNamespaces` is `["*"]` and k8sDenyNamespaces is
+	// `["namespace1", "namespace2"]`, then all k8s namespaces besides "namespace1"
+	// and "namespace2" will be available for injection.
+	//
+	// Note: `namespaceSelector` takes precedence over this since it is applied first.
+	// `kube-system` and `kube-public` are never injected.
+	// Requires consul-k8s v0.12+.
+	//
+	// Default: []
+	K8sDenyNamespaces []string `yaml:"k8sDenyNamespaces,omitempty"`
+
+	// These settings manage the connect injector's interaction with
+	// Consul namespaces (requires consul-ent v1.7+ and consul-k8s v0.12+).
+	// Also, `global.enableConsulNamespaces` must be true.
+	ConsulNamespaces *ConnectInjectConsulNamespacesValues `yaml:"consulNamespaces,omitempty"`
+
+	// Selector labels for connectInject pod assignment, formatted as a multi-line string.
+	// ref: https://kubernetes.io/docs/concepts/configuration/assign-pod-node/#nodeselector
+	//
+	// Example:
+	//
+	// ```yaml
+	// nodeSelector: |
+	//   beta.kubernetes.io/arch: amd64
+	// ```
+	//
+	// Default: null
+	NodeSelector *string `yaml:"nodeSelector,omitempty"`
+
+	// Affinity Settings
+	// This should be a multi-line string matching the affinity object
+	//
+	// Default: null
+	Affinity *string `yaml:"affinity,omitempty"`
+
+	// Toleration Settings
+	// This should be a multi-line string matching the Toleration array
+	// in a PodSpec.
+	//
+	// Default: null
+	Tolerations *string `yaml:"tolerations,omitempty"`
+
+	// Query that defines which Service Accounts
+	// can authenticate to Consul and receive an ACL token during Connect injection.
+	// The default setting, i.e. serviceaccount.name!=default, prevents the
+	// 'default' Service Account from logging in.
+	// If set to an empty string all service accounts can log in.
+	// This only has effect if ACLs are enabled.
+	//
+	// See https://www.consul.io/docs/acl/acl-auth-methods.html#binding-rules
+	// and https://www.consul.io/docs/acl/auth-methods/kubernetes.html#trusted-identity-attributes
+	// for more details.
+	// Requires Consul >= v1.5 and consul-k8s >= v0.8.0.
+	//
+	// Default: serviceaccount.name!=default
+	AclBindingRuleSelector *string `yaml:"aclBindingRuleSelector,omitempty"`
+
+	// If you are not using global.acls.manageSystemACLs and instead manually setting up an
+	// auth method for Connect inject, set this to the name of your auth method.
+	//
+	// Default: ""
+	OverrideAuthMethodName *string `yaml:"overrideAuthMethodName,omitempty"`
+
+	// Refers to a Kubernetes secret that you have created that contains
+	// an ACL token for your Consul cluster which allows the Connect injector the correct
+	// permissions. This is only needed if Consul namespaces [Enterprise Only] and ACLs
+	// are enabled on the Consul cluster and you are not setting
+	// `global.acls.manageSystemACLs` to `true`.
+	// This token needs to have `operator = "write"` privileges to be able to
+	// create Consul namespaces.
+	AclInjectToken *ConnectInjectAclInjectTokenValues `yaml:"aclInjectToken,omitempty"`
+
+	SidecarProxy *ConnectInjectSidecarProxyValues `yaml:"sidecarProxy,omitempty"`
+
+	// Resource settings for the Connect injected init container.
+	InitContainer map[string]interface{} `yaml:"initContainer,omitempty"`
+}
+
+// ControllerValues are the values under `controller`.
+type ControllerValues struct {
+	// Enables the controller for managing custom resources.
+	//
+	// Default: false
+	Enabled *Boolean `yaml:"enabled,omitempty"`
+
+	// The number of deployment replicas.
+	//
+	// Default: 1
+	Replicas *int `yaml:"replicas,omitempty"`
+
+	// Log verbosity level. One of "debug", "info", "warn", or "error".
+	//
+	// Default: ""
+	LogLevel *string `yaml:"logLevel,omitempty"`
+
+	ServiceAccount *ControllerServiceAccountValues `yaml:"serviceAccount,omitempty"`
+
+	// Resource settings for controller pods.
+	Resources map[string]interface{} `yaml:"resources,omitempty"`
+
+	// Optional YAML string to specify a nodeSelector config.
+	//
+	// Default: null
+	NodeSelector *string `yaml:"nodeSelector,omitempty"`
+
+	// Optional YAML string to specify tolerations.
+	//
+	// Default: null
+	Tolerations *string `yaml:"tolerations,omitempty"`
+
+	// Affinity Settings
+	// This should be a multi-line string matching the affinity object
+	//
+	// Default: null
+	Affinity *string `yaml:"affinity,omitempty"`
+
+	// Optional priorityClassName.
+	//
+	// Default: ""
+	PriorityClassName *string `yaml:"priorityClassName,omitempty"`
+
+	// Refers to a Kubernetes secret that you have created that contains
+	// an ACL token for your Consul cluster which grants the controller process the correct
+	// permissions. This is only needed if you are managing ACLs yourself (i.e. not using
+	// `global.acls.manageSystemACLs`).
+	//
+	// If running Consul OSS, requires permissions:
+	// ```hcl
+	// operator = "write"
+	// service_prefix "" {
+	//   policy = "write"
+	//   intentions = "write"
+	// }
+	// ```
+	// If running Consul Enterprise, talk to your account manager for assistance.
+	AclToken *ControllerAclTokenValues `yaml:"aclToken,omitempty"`
+}
+
+// MeshGatewayValues are the values under `meshGateway`.
+type MeshGatewayValues struct {
+	// If mesh gateways are enabled, a Deployment will be created that runs
+	// gateways and Consul Connect will be configured to use gateways.
+	// See https://www.consul.io/docs/connect/mesh_gateway.html
+	// Requirements: consul 1.6.0+ and consul-k8s 0.15.0+ if using
+	// global.acls.manageSystemACLs.
+	//
+	// Default: false
+	Enabled *Boolean `yaml:"enabled,omitempty"`
+
+	// Number of replicas for the Deployment.
+	//
+	// Default: 2
+	Replicas *int `yaml:"replicas,omitempty"`
+
+	// What gets registered as WAN address for the gateway.
+	WanAddress *MeshGatewayWanAddressValues `yaml:"wanAddress,omitempty"`
+
+	// The service option configures the Service that fronts the Gateway Deployment.
+	Service *MeshGatewayServiceValues `yaml:"service,omitempty"`
+
+	// If set to true, gateway Pods will run on the host network.
+	//
+	// Default: false
+	HostNetwork *Boolean `yaml:"hostNetwork,omitempty"`
+
+	// dnsPolicy to use.
+	//
+	// Default: null
+	DnsPolicy *string `yaml:"dnsPolicy,omitempty"`
+
+	// Consul service name for the mesh gateways.
+	// Cannot be set to anything other than "mesh-gateway" if
+	// global.acls.manageSystemACLs is true since the ACL token
+	// generated is only for the name 'mesh-gateway'.
+	//
+	// Default: mesh-gateway
+	ConsulServiceName *string `yaml:"consulServiceName,omitempty"`
+
+	// Port that the gateway will run on inside the container.
+	//
+	// Default: 8443
+	ContainerPort *int `yaml:"containerPort,omitempty"`
+
+	// Optional hostPort for the gateway to be exposed on.
+	// This can be used with wanAddress.port and wanAddress.useNodeIP
+	// to expose the gateways directly from the node.
+	// If hostNetwork is true, this must be null or set to the same port as
+	// containerPort.
+	// NOTE: Cannot set to 8500 or 8502 because those are reserved for the Consul
+	// agent.
+	//
+	// Default: null
+	HostPort *int `yaml:"hostPort,omitempty"`
+
+	ServiceAccount *MeshGatewayServiceAccountValues `yaml:"serviceAccount,omitempty"`
+
+	// Resource settings for mesh gateway pods.
+	// NOTE: The use of a YAML string is deprecated. Instead, set directly as a
+	// YAML map.
+	Resources map[string]interface{} `yaml:"resources,omitempty"`
+
+	// Resource settings for the `copy-consul-bin` init container.
+	InitCopyConsulContainer map[string]interface{} `yaml:"initCopyConsulContainer,omitempty"`
+
+	// By default, we set an anti-affinity so that two gateway pods won't be
+	// on the same node. NOTE: Gateways require that Consul client agents are
+	// also running on the nodes alongside each gateway pod.
+	Affinity *string `yaml:"affinity,omitempty"`
+
+	// Optional YAML string to specify tolerations.
+	//
+	// Default: null
+	Tolerations *string `yaml:"tolerations,omitempty"`
+
+	// Optional YAML string to specify a nodeSelector config.
+	//
+	// Default: null
+	NodeSelector *string `yaml:"nodeSelector,omitempty"`
+
+	// Optional priorityClassName.
+	//
+	// Default: ""
+	PriorityClassName *string `yaml:"priorityClassName,omitempty"`
+
+	// Annotations to apply to the mesh gateway deployment.
+	//
+	// Example:
+	//
+	// ```yaml
+	// annotations: |
+	//   'annotation-key': annotation-value
+	// ```
+	//
+	// Default: null
+	Annotations *string `yaml:"annotations,omitempty"`
+}
+
+// IngressGatewaysValues are the values under `ingressGateways`.
+type IngressGatewaysValues struct {
+	// Enable ingress gateway deployment. Requires `connectInject.enabled=true`
+	// and `client.enabled=true`.
+	//
+	// Default: false
+	Enabled *Boolean `yaml:"enabled,omitempty"`
+
+	// Defaults sets default values for all gateway fields. With the exception
+	// of annotations, defining any of these values in the `gateways` list
+	// will override the default values provided here. Annotations will
+	// include both the default annotations and any additional ones defined
+	// for a specific gateway.
+	Defaults *IngressGatewaysDefaultsValues `yaml:"defaults,omitempty"`
+
+	// Gateways is a list of gateway objects. The only required field for
+	// each is `name`, though they can also contain any of the fields in
+	// `defaults`. Values defined here override the defaults except in the
+	// case of annotations where both will be applied.
+	Gateways []IngressGatewaysGatewaysItem `yaml:"gateways,omitempty"`
+}
+
+// TerminatingGatewaysValues are the values under `terminatingGateways`.
+type TerminatingGatewaysValues struct {
+	// Enable terminating gateway deployment. Requires `connectInject.enabled=true`
+	// and `client.enabled=true`.
+	//
+	// Default: false
+	Enabled *Boolean `yaml:"enabled,omitempty"`
+
+	// Defaults sets default values for all gateway fields. With the exception
+	// of annotations, defining any of these values in the `gateways` list
+	// will override the default values provided here. Annotations will
+	// include both the default annotations and any additional ones defined
+	// for a specific gateway.
+	Defaults *TerminatingGatewaysDefaultsValues `yaml:"defaults,omitempty"`
+
+	// Gateways is a list of gateway objects. The only required field for
+	// each is `name`, though they can also contain any of the fields in
+	// `defaults`. Values defined here override the defaults except in the
+	// case of annotations where both will be applied.
+	Gateways []TerminatingGatewaysGatewaysItem `yaml:"gateways,omitempty"`
+}
+
+// PrometheusValues are the values under `prometheus`.
+type PrometheusValues struct {
+	// When true, the Helm chart will install a demo Prometheus server instance
+	// alongside Consul.
+	//
+	// Default: false
+	Enabled *Boolean `yaml:"enabled,omitempty"`
+}
+
+// TestsValues are the values under `tests`.
+type TestsValues struct {
+	// Default: true
+	Enabled *Boolean `yaml:"enabled,omitempty"`
+}
+
+// GlobalImagePullSecretsItem is an element of `global.imagePullSecrets`.
+type GlobalImagePullSecretsItem struct {
+	// Name of the image pull secret.
+	//
+	// Required.
+	Name *string `yaml:"name,omitempty"`
+}
+
+// GlobalGossipEncryptionValues are the values under `global.gossipEncryption`.
+type GlobalGossipEncryptionValues struct {
+	// secretName is the name of the Kubernetes secret that holds the gossip
+	// encryption key. The secret must be in the same namespace that Consul is installed into.
+	//
+	// Default: ""
+	SecretName *string `yaml:"secretName,omitempty"`
+
+	// secretKey is the key within the Kubernetes secret that holds the gossip
+	// encryption key.
+	//
+	// Default: ""
+	SecretKey *string `yaml:"secretKey,omitempty"`
+}
+
+// GlobalTLSValues are the values under `global.tls`.
+type GlobalTLSValues struct {
+	// If true, the Helm chart will enable TLS for Consul
+	// servers and clients and all consul-k8s components, as well as generate certificate
+	// authority (optional) and server and client certificates.
+	//
+	// Default: false
+	Enabled *Boolean `yaml:"enabled,omitempty"`
+
+	// If true, turns on the auto-encrypt feature on clients and servers.
+	// It also switches consul-k8s components to retrieve the CA from the servers
+	// via the API. Requires Consul 1.7.1+ and consul-k8s 0.13.0
+	//
+	// Default: false
+	EnableAutoEncrypt *Boolean `yaml:"enableAutoEncrypt,omitempty"`
+
+	// A list of additional DNS names to set as Subject Alternative Names (SANs)
+	// in the server certificate. This is useful when you need to access the
+	// Consul server(s) externally, for example, if you're using the UI.
+	//
+	// Default: []
+	ServerAdditionalDNSSANs []string `yaml:"serverAdditionalDNSSANs,omitempty"`
+
+	// A list of additional IP addresses to set as Subject Alternative Names (SANs)
+	// in the server certificate. This is useful when you need to access the
+	// Consul server(s) externally, for example, if you're using the UI.
+	//
+	// Default: []
+	ServerAdditionalIPSANs []string `yaml:"serverAdditionalIPSANs,omitempty"`
+
+	// If true, `verify_outgoing`, `verify_server_hostname`,
+	// and `verify_incoming_rpc` will be set to `true` for Consul servers and clients.
+	// Set this to false to incrementally roll out TLS on an existing Consul cluster.
+	// Please see https://consul.io/docs/k8s/operations/tls-on-existing-cluster
+	// for more details.
+	//
+	// Default: true
+	Verify *Boolean `yaml:"verify,omitempty"`
+
+	// If true, the Helm chart will configure Consul to disable the HTTP port on
+	// both clients and servers and to only accept HTTPS connections.
+	//
+	// Default: true
+	HttpsOnly *Boolean `yaml:"httpsOnly,omitempty"`
+
+	// A Kubernetes secret containing the certificate of the CA to use for
+	// TLS communication within the Consul cluster. If you have generated the CA yourself
+	// with the consul CLI, you could use the following command to create the secret
+	// in Kubernetes:
+	//
+	// ```bash
+	// kubectl create secret generic consul-ca-cert \
+	//     --from-file='tls.crt=./consul-agent-ca.pem'
+	// ```
+	CaCert *GlobalTLSCaCertValues `yaml:"caCert,omitempty"`
+
+	// A Kubernetes secret containing the private key of the CA to use for
+	// TLS communication within the Consul cluster. If you have generated the CA yourself
+	// with the consul CLI, you could use the following command to create the secret
+	// in Kubernetes:
+	//
+	// ```bash
+	// kubectl create secret generic consul-ca-key \
+	//     --from-file='tls.key=./consul-agent-ca-key.pem'
+	// ```
+	//
+	// Note that we need the CA key so that we can generate server and client certificates.
+	// It is particularly important for the client certificates since they need to have host IPs
+	// as Subject Alternative Names. In the future, we may support bringing your own server
+	// certificates.
+	CaKey *GlobalTLSCaKeyValues `yaml:"caKey,omitempty"`
+}
+
+// GlobalACLsValues are the values under `global.acls`.
+type GlobalACLsValues struct {
+	// If true, the Helm chart will automatically manage ACL tokens and policies
+	// for all Consul and consul-k8s components.
+	// This requires Consul >= 1.4 and consul-k8s >= 0.14.0.
+	//
+	// Default: false
+	ManageSystemACLs *Boolean `yaml:"manageSystemACLs,omitempty"`
+
+	// A Kubernetes secret containing the bootstrap token to use for
+	// creating policies and tokens for all Consul and consul-k8s components.
+	// If set, we will skip ACL bootstrapping of the servers and will only
+	// initialize ACLs for the Consul clients and consul-k8s system components.
+	// Requires consul-k8s >= 0.14.0.
+	BootstrapToken *GlobalACLsBootstrapTokenValues `yaml:"bootstrapToken,omitempty"`
+
+	// If true, an ACL token will be created that can be used in secondary
+	// datacenters for replication. This should only be set to true in the
+	// primary datacenter since the replication token must be created from that
+	// datacenter.
+	// In secondary datacenters, the secret needs to be imported from the primary
+	// datacenter and referenced via `global.acls.replicationToken`.
+	// Requires consul-k8s >= 0.13.0.
+	//
+	// Default: false
+	CreateReplicationToken *Boolean `yaml:"createReplicationToken,omitempty"`
+
+	// replicationToken references a secret containing the replication ACL token.
+	// This token will be used by secondary datacenters to perform ACL replication
+	// and create ACL tokens and policies.
+	// This value is ignored if `bootstrapToken` is also set.
+	// Requires consul-k8s >= 0.13.0.
+	ReplicationToken *GlobalACLsReplicationTokenValues `yaml:"replicationToken,omitempty"`
+}
+
+// GlobalFederationValues are the values under `global.federation`.
+type GlobalFederationValues struct {
+	// If enabled, this datacenter will be federation-capable. Only federation
+	// via mesh gateways is supported.
+	// Mesh gateways and servers will be configured to allow federation.
+	// Requires `global.tls.enabled`, `meshGateway.enabled` and `connectInject.enabled`
+	// to be true. Requires Consul 1.8+.
+	//
+	// Default: false
+	Enabled *Boolean `yaml:"enabled,omitempty"`
+
+	// If true, the chart will create a Kubernetes secret that can be imported
+	// into secondary datacenters so they can federate with this datacenter. The
+	// secret contains all the information secondary datacenters need to contact
+	// and authenticate with this datacenter. This should only be set to true
+	// in your primary datacenter. The secret name is
+	// `<global.name>-federation` (if setting `global.name`), otherwise
+	// `<helm-release-name>-consul-federation`. Requires consul-k8s 0.15.0+.
+	//
+	// Default: false
+	CreateFederationSecret *Boolean `yaml:"createFederationSecret,omitempty"`
+}
+
+// GlobalMetricsValues are the values under `global.metrics`.
+type GlobalMetricsValues struct {
+	// Configures the Helm chart’s components
+	// to expose Prometheus metrics for the Consul service mesh. By default
+	// this includes gateway metrics and sidecar metrics.
+	//
+	// Default: false
+	Enabled *Boolean `yaml:"enabled,omitempty"`
+
+	// Configures consul agent metrics. Only applicable if
+	// `global.metrics.enabled` is true.
+	//
+	// Default: false
+	EnableAgentMetrics *Boolean `yaml:"enableAgentMetrics,omitempty"`
+
+	// Configures the retention time for metrics in Consul clients and
+	// servers. This must be greater than 0 for Consul clients and servers
+	// to expose any metrics at all.
+	// Only applicable if `global.metrics.enabled` is true.
+	//
+	// Default: 1m
+	AgentMetricsRetentionTime *string `yaml:"agentMetricsRetentionTime,omitempty"`
+
+	// If true, mesh, terminating, and ingress gateways will expose their
+	// Envoy metrics on port `20200` at the `/metrics` path and all gateway pods
+	// will have Prometheus scrape annotations. Only applicable if `global.metrics.enabled` is true.
+	//
+	// Default: true
+	EnableGatewayMetrics *Boolean `yaml:"enableGatewayMetrics,omitempty"`
+}
+
+// GlobalOpenshiftValues are the values under `global.openshift`.
+type GlobalOpenshiftValues struct {
+	// If true, the Helm chart will create necessary configuration for running
+	// its components on OpenShift.
+	//
+	// Default: false
+	Enabled *Boolean `yaml:"enabled,omitempty"`
+}
+
+// ServerEnterpriseLicenseValues are the values under `server.enterpriseLicense`.
+type ServerEnterpriseLicenseValues struct {
+	// The name of the Kubernetes secret that holds the enterprise license.
+	// The secret must be in the same namespace that Consul is installed into.
+	//
+	// Default: null
+	SecretName *string `yaml:"secretName,omitempty"`
+
+	// The key within the Kubernetes secret that holds the enterprise license.
+	//
+	// Default: null
+	SecretKey *string `yaml:"secretKey,omitempty"`
+
+	// Manages license autoload. Required in Consul 1.10.0+, 1.9.7+ and 1.8.12+.
+	//
+	// Default: true
+	EnableLicenseAutoload *Boolean `yaml:"enableLicenseAutoload,omitempty"`
+}
+
+// ServerServerCertValues are the values under `server.serverCert`.
+type ServerServerCertValues struct {
+	// The name of the Kubernetes secret.
+	//
+	// Default: null
+	SecretName *string `yaml:"secretName,omitempty"`
+}
+
+// ServerPortsValues are the values under `server.ports`.
+type ServerPortsValues struct {
+	// Configures the LAN gossip port for the consul servers. If you choose to
+	// enable `server.exposeGossipAndRPCPorts` and `client.exposeGossipPorts`,
+	// that will configure the LAN gossip ports on the servers and clients to be
+	// hostPorts, so if you are running clients and servers on the same node the
+	// ports will conflict if they are both 8301. When you enable
+	// `server.exposeGossipAndRPCPorts` and `client.exposeGossipPorts`, you must
+	// change this from the default to an unused port on the host, e.g. 9301. By
+	// default the LAN gossip port is 8301 and configured as a containerPort on
+	// the consul server Pods.
+	Serflan *ServerPortsSerflanValues `yaml:"serflan,omitempty"`
+}
+
+// ServerServiceAccountValues are the values under `server.serviceAccount`.
+type ServerServiceAccountValues struct {
+	// This value defines additional annotations for the server service account. This should be formatted as a multi-line
+	// string.
+	//
+	// ```yaml
+	// annotations: |
+	//   "sample/annotation1": "foo"
+	//   "sample/annotation2": "bar"
+	// ```
+	//
+	// Default: null
+	Annotations *string `yaml:"annotations,omitempty"`
+}
+
+// ServerDisruptionBudgetValues are the values under `server.disruptionBudget`.
+type ServerDisruptionBudgetValues struct {
+	// This will enable/disable registering a PodDisruptionBudget for the server
+	// cluster. If this is enabled, it will only register the budget so long as
+	// the server cluster is enabled.
+	//
+	// Default: true
+	Enabled *Boolean `yaml:"enabled,omitempty"`
+
+	// The maximum number of unavailable pods. By default, this will be
+	// automatically computed based on the `server.replicas` value to be `(n/2)-1`.
+	// If you need to set this to `0`, you will need to add a
+	// --set 'server.disruptionBudget.maxUnavailable=0'` flag to the helm chart installation
+	// command because of a limitation in the Helm templating language.
+	//
+	// Default: null
+	MaxUnavailable *int `yaml:"maxUnavailable,omitempty"`
+}
+
+// ServerExtraVolumesItem is an element of `server.extraVolumes`.
+type ServerExtraVolumesItem struct {
+	// Type of the volume. Case sensitive.
+	//
+	// Required.
+	Type *string `yaml:"type,omitempty"`
+
+	// Name of the configMap or secret to be mounted. This also controls
+	// the path that it is mounted to. The volume will be mounted to `/consul/userconfig/<name>`.
+	//
+	// Required.
+	Name *string `yaml:"name,omitempty"`
+
+	// If true, then the agent will be
+	// configured to automatically load HCL/JSON configuration files from this volume
+	// with `-config-dir`.
+	//
+	// Default: false
+	Load *Boolean `yaml:"load,omitempty"`
+
+	// The keys of the configMap or secret to mount and the paths to mount
+	// them to. If not set, all keys are mounted.
+	Items []ServerExtraVolumesItemsItem `yaml:"items,omitempty"`
+}
+
+// ServerServiceValues are the values under `server.service`.
+type ServerServiceValues struct {
+	// Annotations to apply to the server service.
+	//
+	// ```yaml
+	// annotations: |
+	//   "annotation-key": "annotation-value"
+	// ```
+	//
+	// Default: null
+	Annotations *string `yaml:"annotations,omitempty"`
+}
+
+// ClientServiceAccountValues are the values under `client.serviceAccount`.
+type ClientServiceAccountValues struct {
+	// This value defines additional annotations for the client service account. This should be formatted as a multi-line
+	// string.
+	//
+	// ```yaml
+	// annotations: |
+	//   "sample/annotation1": "foo"
+	//   "sample/annotation2": "bar"
+	// ```
+	//
+	// Default: null
+	Annotations *string `yaml:"annotations,omitempty"`
+}
+
+// ClientExtraVolumesItem is an element of `client.extraVolumes`.
+type ClientExtraVolumesItem struct {
+	// Type of the volume. Case sensitive.
+	//
+	// Required.
+	Type *string `yaml:"type,omitempty"`
+
+	// Name of the configMap or secret to be mounted. This also controls
+	// the path that it is mounted to. The volume will be mounted to `/consul/userconfig/<name>`.
+	//
+	// Required.
+	Name *string `yaml:"name,omitempty"`
+
+	// If true, then the agent will be
+	// configured to automatically load HCL/JSON configuration files from this volume
+	// with `-config-dir`.
+	//
+	// Default: false
+	Load *Boolean `yaml:"load,omitempty"`
+
+	// The keys of the configMap or secret to mount and the paths to mount
+	// them to. If not set, all keys are mounted.
+	Items []ClientExtraVolumesItemsItem `yaml:"items,omitempty"`
+}
+
+// ClientSnapshotAgentValues are the values under `client.snapshotAgent`.
+type ClientSnapshotAgentValues struct {
+	// If true, the chart will install resources necessary to run the snapshot agent.
+	//
+	// Default: false
+	Enabled *Boolean `yaml:"enabled,omitempty"`
+
+	// The number of snapshot agents to run.
+	//
+	// Default: 2
+	Replicas *int `yaml:"replicas,omitempty"`
+
+	// A Kubernetes secret that should be manually created to contain the entire
+	// config to be used on the snapshot agent.
+	// This is the preferred method of configuration since there are usually storage
+	// credentials present. Please see Snapshot agent config (https://consul.io/commands/snapshot/agent#config-file-options)
+	// for details.
+	ConfigSecret *ClientSnapshotAgentConfigSecretValues `yaml:"configSecret,omitempty"`
+
+	ServiceAccount *ClientSnapshotAgentServiceAccountValues `yaml:"serviceAccount,omitempty"`
+
+	// Resource settings for snapshot agent pods.
+	Resources map[string]interface{} `yaml:"resources,omitempty"`
+
+	// Optional PEM-encoded CA certificate that will be added to the trusted system CAs.
+	// Useful if using an S3-compatible storage exposing a self-signed certificate.
+	//
+	// Example:
+	//
+	// ```yaml
+	// caCert: |
+	//   -----BEGIN CERTIFICATE-----
+	//   MIIC7jCCApSgAwIBAgIRAIq2zQEVexqxvtxP6J0bXAwwCgYIKoZIzj0EAwIwgbkx
+	//   ...
+	// ```
+	//
+	// Default: null
+	CaCert *string `yaml:"caCert,omitempty"`
+}
+
+// UIServiceValues are the values under `ui.service`.
+type UIServiceValues struct {
+	// This will enable/disable registering a
+	// Kubernetes Service for the Consul UI. This value only takes effect if `ui.enabled` is
+	// true and taking effect.
+	//
+	// Default: true
+	Enabled *Boolean `yaml:"enabled,omitempty"`
+
+	// The service type to register.
+	//
+	// Default: null
+	Type *string `yaml:"type,omitempty"`
+
+	// Optionally set the nodePort value of the ui service if using a NodePort service.
+	// If not set and using a NodePort service, Kubernetes will automatically assign
+	// a port.
+	NodePort *UIServiceNodePortValues `yaml:"nodePort,omitempty"`
+
+	// Annotations to apply to the UI service.
+	//
+	// Example:
+	//
+	// ```yaml
+	// annotations: |
+	//   'annotation-key': annotation-value
+	// ```
+	//
+	// Default: null
+	Annotations *string `yaml:"annotations,omitempty"`
+
+	// Additional ServiceSpec values
+	// This should be a multi-line string mapping directly to a Kubernetes
+	// ServiceSpec object.
+	//
+	// Default: null
+	AdditionalSpec *string `yaml:"additionalSpec,omitempty"`
+}
+
+// UIIngressValues are the values under `ui.ingress`.
+type UIIngressValues struct {
+	// This will create an Ingress resource for the Consul UI.
+	//
+	// Default: false
+	Enabled *Boolean `yaml:"enabled,omitempty"`
+
+	// pathType override - see: https://kubernetes.io/docs/concepts/services-networking/ingress/#path-types
+	//
+	// Default: Prefix
+	PathType *string `yaml:"pathType,omitempty"`
+
+	// hosts is a list of host name to create Ingress rules.
+	//
+	// ```yaml
+	// hosts:
+	//   - host: foo.bar
+	//     paths:
+	//       - /example
+	//       - /test
+	// ```
+	Hosts []map[string]interface{} `yaml:"hosts,omitempty"`
+
+	// tls is a list of hosts and secret name in an Ingress
+	// which tells the Ingress controller to secure the channel.
+	//
+	// ```yaml
+	// tls:
+	//   - hosts:
+	//     - chart-example.local
+	//     secretName: testsecret-tls
+	// ```
+	TLS []map[string]interface{} `yaml:"tls,omitempty"`
+
+	// Annotations to apply to the UI ingress.
+	//
+	// Example:
+	//
+	// ```yaml
+	// annotations: |
+	//   'annotation-key': annotation-value
+	// ```
+	//
+	// Default: null
+	Annotations *string `yaml:"annotations,omitempty"`
+}
+
+// UIMetricsValues are the values under `ui.metrics`.
+type UIMetricsValues struct {
+	// Enable displaying metrics in the UI. The default value of "-"
+	// will inherit from `global.metrics.enabled` value.
+	//
+	// Default: global.metrics.enabled
+	Enabled *Boolean `yaml:"enabled,omitempty"`
+
+	// Provider for metrics. See
+	// https://www.consul.io/docs/agent/options#ui_config_metrics_provider
+	// This value is only used if `ui.enabled` is set to true.
+	//
+	// Default: prometheus
+	Provider *string `yaml:"provider,omitempty"`
+
+	// baseURL is the URL of the prometheus server, usually the service URL.
+	// This value is only used if `ui.enabled` is set to true.
+	//
+	// Default: http://prometheus-server
+	BaseURL *string `yaml:"baseURL,omitempty"`
+}
+
+// SyncCatalogConsulNamespacesValues are the values under `syncCatalog.consulNamespaces`.
+type SyncCatalogConsulNamespacesValues struct {
+	// Name of the Consul namespace to register all
+	// k8s services into. If the Consul namespace does not already exist,
+	// it will be created. This will be ignored if `mirroringK8S` is true.
+	//
+	// Default: default
+	ConsulDestinationNamespace *string `yaml:"consulDestinationNamespace,omitempty"`
+
+	// If true, k8s services will be registered into a Consul namespace
+	// of the same name as their k8s namespace, optionally prefixed if
+	// `mirroringK8SPrefix` is set below. If the Consul namespace does not
+	// already exist, it will be created. Turning this on overrides the
+	// `consulDestinationNamespace` setting.
+	// `addK8SNamespaceSuffix` may no longer be needed if enabling this option.
+	//
+	// Default: false
+	MirroringK8S *Boolean `yaml:"mirroringK8S,omitempty"`
+
+	// If `mirroringK8S` is set to true, `mirroringK8SPrefix` allows each Consul namespace
+	// to be given a prefix. For example, if `mirroringK8SPrefix` is set to "k8s-", a
+	// service in the k8s `staging` namespace will be registered into the
+	// `k8s-staging` Consul namespace.
+	//
+	// Default: ""
+	MirroringK8SPrefix *string `yaml:"mirroringK8SPrefix,omitempty"`
+}
+
+// SyncCatalogAclSyncTokenValues are the values under `syncCatalog.aclSyncToken`.
+type SyncCatalogAclSyncTokenValues struct {
+	// The name of the Kubernetes secret.
+	//
+	// Default: null
+	SecretName *string `yaml:"secretName,omitempty"`
+
+	// The key of the Kubernetes secret.
+	//
+	// Default: null
+	SecretKey *string `yaml:"secretKey,omitempty"`
+}
+
+// SyncCatalogServiceAccountValues are the values under `syncCatalog.serviceAccount`.
+type SyncCatalogServiceAccountValues struct {
+	// This value defines additional annotations for the mesh gateways' service account. This should be formatted as a
+	// multi-line string.
+	//
+	// ```yaml
+	// annotations: |
+	//   "sample/annotation1": "foo"
+	//   "sample/annotation2": "bar"
+	// ```
+	//
+	// Default: null
+	Annotations *string `yaml:"annotations,omitempty"`
+}
+
+// ConnectInjectTransparentProxyValues are the values under `connectInject.transparentProxy`.
+type ConnectInjectTransparentProxyValues struct {
+	// If true, then all Consul Service mesh will run with transparent proxy enabled by default,
+	// i.e. we enforce that all traffic within the pod will go through the proxy.
+	// This value is overridable via the "consul.hashicorp.com/transparent-proxy" pod annotation.
+	//
+	// Default: true
+	DefaultEnabled *Boolean `yaml:"defaultEnabled,omitempty"`
+
+	// If true, we will overwrite Kubernetes HTTP probes of the pod to point to the Envoy proxy instead.
+	// This setting is recommended because with traffic being enforced to go through the Envoy proxy,
+	// the probes on the pod will fail because kube-proxy doesn't have the right certificates
+	// to talk to Envoy.
+	// This value is also overridable via the "consul.hashicorp.com/transparent-proxy-overwrite-probes" annotation.
+	// Note: This value has no effect if transparent proxy is disabled on the pod.
+	//
+	// Default: true
+	DefaultOverwriteProbes *Boolean `yaml:"defaultOverwriteProbes,omitempty"`
+}
+
+// ConnectInjectMetricsValues are the values under `connectInject.metrics`.
+type ConnectInjectMetricsValues struct {
+	// If true, the connect-injector will automatically
+	// add prometheus annotations to connect-injected pods. It will also
+	// add a listener on the Envoy sidecar to expose metrics. The exposed
+	// metrics will depend on whether metrics merging is enabled:
+	//   - If metrics merging is enabled:
+	//     the Consul sidecar will run a merged metrics server
+	//     combining Envoy sidecar and Connect service metrics,
+	//     i.e. if your service exposes its own Prometheus metrics.
+	//   - If metrics merging is disabled:
+	//     the listener will just expose Envoy sidecar metrics.
+	// This will inherit from `global.metrics.enabled`.
+	//
+	// Default: -
+	DefaultEnabled *string `yaml:"defaultEnabled,omitempty"`
+
+	// Configures the Consul sidecar to run a merged metrics server
+	// to combine and serve both Envoy and Connect service metrics.
+	// This feature is available only in Consul v1.10.0 or greater.
+	//
+	// Default: false
+	DefaultEnableMerging *Boolean `yaml:"defaultEnableMerging,omitempty"`
+
+	// Configures the port at which the Consul sidecar will listen on to return
+	// combined metrics. This port only needs to be changed if it conflicts with
+	// the application's ports.
+	//
+	// Default: 20100
+	DefaultMergedMetricsPort *int `yaml:"defaultMergedMetricsPort,omitempty"`
+
+	// Configures the port Prometheus will scrape metrics from, by configuring
+	// the Pod annotation `prometheus.io/port` and the corresponding listener in
+	// the Envoy sidecar.
+	// NOTE: This is *not* the port that your application exposes metrics on.
+	// That can be configured with the
+	// `consul.hashicorp.com/service-metrics-port` annotation.
+	//
+	// Default: 20200
+	DefaultPrometheusScrapePort *int `yaml:"defaultPrometheusScrapePort,omitempty"`
+
+	// Configures the path Prometheus will scrape metrics from, by configuring the pod
+	// annotation `prometheus.io/path` and the corresponding handler in the Envoy
+	// sidecar.
+	// NOTE: This is *not* the path that your application exposes metrics on.
+	// That can be configured with the
+	// `consul.hashicorp.com/service-metrics-path` annotation.
+	//
+	// Default: /metrics
+	DefaultPrometheusScrapePath *string `yaml:"defaultPrometheusScrapePath,omitempty"`
+}
+
+// ConnectInjectServiceAccountValues are the values under `connectInject.serviceAccount`.
+type ConnectInjectServiceAccountValues struct {
+	// This value defines additional annotations for the injector service account. This should be formatted as a
+	// multi-line string.
+	//
+	// ```yaml
+	// annotations: |
+	//   "sample/annotation1": "foo"
+	//   "sample/annotation2": "bar"
+	// ```
+	//
+	// Default: null
+	Annotations *string `yaml:"annotations,omitempty"`
+}
+
+// ConnectInjectConsulNamespacesValues are the values under `connectInject.consulNamespaces`.
+type ConnectInjectConsulNamespacesValues struct {
+	// Name of the Consul namespace to register all
+	// k8s pods into. If the Consul namespace does not already exist,
+	// it will be created. This will be ignored if `mirroringK8S` is true.
+	//
+	// Default: default
+	ConsulDestinationNamespace *string `yaml:"consulDestinationNamespace,omitempty"`
+
+	// Causes k8s pods to be registered into a Consul namespace
+	// of the same name as their k8s namespace, optionally prefixed if
+	// `mirroringK8SPrefix` is set below. If the Consul namespace does not
+	// already exist, it will be created. Turning this on overrides the
+	// `consulDestinationNamespace` setting.
+	//
+	// Default: false
+	MirroringK8S *Boolean `yaml:"mirroringK8S,omitempty"`
+
+	// If `mirroringK8S` is set to true, `mirroringK8SPrefix` allows each Consul namespace
+	// to be given a prefix. For example, if `mirroringK8SPrefix` is set to "k8s-", a
+	// pod in the k8s `staging` namespace will be registered into the
+	// `k8s-staging` Consul namespace.
+	//
+	// Default: ""
+	MirroringK8SPrefix *string `yaml:"mirroringK8SPrefix,omitempty"`
+}
+
+// ConnectInjectAclInjectTokenValues are the values under `connectInject.aclInjectToken`.
+type ConnectInjectAclInjectTokenValues struct {
+	// The name of the Kubernetes secret.
+	//
+	// Default: null
+	SecretName *string `yaml:"secretName,omitempty"`
+
+	// The key of the Kubernetes secret.
+	//
+	// Default: null
+	SecretKey *string `yaml:"secretKey,omitempty"`
+}
+
+// ConnectInjectSidecarProxyValues are the values under `connectInject.sidecarProxy`.
+type ConnectInjectSidecarProxyValues struct {
+	// Set default resources for sidecar proxy. If null, that resource won't
+	// be set.
+	// These settings can be overridden on a per-pod basis via these annotations:
+	//
+	// - `consul.hashicorp.com/sidecar-proxy-cpu-limit`
+	// - `consul.hashicorp.com/sidecar-proxy-cpu-request`
+	// - `consul.hashicorp.com/sidecar-proxy-memory-limit`
+	// - `consul.hashicorp.com/sidecar-proxy-memory-request`
+	Resources map[string]interface{} `yaml:"resources,omitempty"`
+}
+
+// ControllerServiceAccountValues are the values under `controller.serviceAccount`.
+type ControllerServiceAccountValues struct {
+	// This value defines additional annotations for the controller service account. This should be formatted as a
+	// multi-line string.
+	//
+	// ```yaml
+	// annotations: |
+	//   "sample/annotation1": "foo"
+	//   "sample/annotation2": "bar"
+	// ```
+	//
+	// Default: null
+	Annotations *string `yaml:"annotations,omitempty"`
+}
+
+// ControllerAclTokenValues are the values under `controller.aclToken`.
+type ControllerAclTokenValues struct {
+	// The name of the Kubernetes secret.
+	//
+	// Default: null
+	SecretName *string `yaml:"secretName,omitempty"`
+
+	// The key of the Kubernetes secret.
+	//
+	// Default: null
+	SecretKey *string `yaml:"secretKey,omitempty"`
+}
+
+// MeshGatewayWanAddressValues are the values under `meshGateway.wanAddress`.
+type MeshGatewayWanAddressValues struct {
+	// source configures where to retrieve the WAN address (and possibly port)
+	// for the mesh gateway from.
+	// Can be set to either: `Service`, `NodeIP`, `NodeName` or `Static`.
+	//
+	// - `Service` - Determine the address based on the service type.
+	//
+	//   - If `service.type=LoadBalancer` use the external IP or hostname of
+	//     the service. Use the port set by `service.port`.
+	//
+	//   - If `service.type=NodePort` use the Node IP. The port will be set to
+	//     `service.nodePort` so `service.nodePort` cannot be null.
+	//
+	//   - If `service.type=ClusterIP` use the `ClusterIP`. The port will be set to
+	//     `service.port`.
+	//
+	//   - `service.type=ExternalName` is not supported.
+	//
+	// - `NodeIP` - The node IP as provided by the Kubernetes downward API.
+	//
+	// - `NodeName` - The name of the node as provided by the Kubernetes downward
+	//   API. This is useful if the node names are DNS entries that
+	//   are routable from other datacenters.
+	//
+	// - `Static` - Use the address hardcoded in `meshGateway.wanAddress.static`.
+	//
+	// Default: Service
+	Source *string `yaml:"source,omitempty"`
+
+	// Port that gets registered for WAN traffic.
+	// If source is set to "Service" then this setting will have no effect.
+	// See the documentation for source as to which port will be used in that
+	// case.
+	//
+	// Default: 443
+	Port *int `yaml:"port,omitempty"`
+
+	// If source is set to "Static" then this value will be used as the WAN
+	// address of the mesh gateways. This is useful if you've configured a
+	// DNS entry to point to your mesh gateways.
+	//
+	// Default: ""
+	Static *string `yaml:"static,omitempty"`
+}
+
+// MeshGatewayServiceValues are the values under `meshGateway.service`.
+type MeshGatewayServiceValues struct {
+	// Whether to create a Service or not.
+	//
+	// Default: true
+	Enabled *Boolean `yaml:"enabled,omitempty"`
+
+	// Type of service, ex. LoadBalancer, ClusterIP.
+	//
+	// Default: LoadBalancer
+	Type *string `yaml:"type,omitempty"`
+
+	// Port that the service will be exposed on.
+	// The targetPort will be set to meshGateway.containerPort.
+	//
+	// Default: 443
+	Port *int `yaml:"port,omitempty"`
+
+	// Optionally set the nodePort value of the service if using a NodePort service.
+	// If not set and using a NodePort service, Kubernetes will automatically assign
+	// a port.
+	//
+	// Default: null
+	NodePort *int `yaml:"nodePort,omitempty"`
+
+	// Annotations to apply to the mesh gateway service.
+	//
+	// Example:
+	//
+	// ```yaml
+	// annotations: |
+	//   'annotation-key': annotation-value
+	// ```
+	//
+	// Default: null
+	Annotations *string `yaml:"annotations,omitempty"`
+
+	// Optional YAML string that will be appended to the Service spec.
+	//
+	// Default: null
+	AdditionalSpec *string `yaml:"additionalSpec,omitempty"`
+}
+
+// MeshGatewayServiceAccountValues are the values under `meshGateway.serviceAccount`.
+type MeshGatewayServiceAccountValues struct {
+	// This value defines additional annotations for the mesh gateways' service account. This should be formatted as a
+	// multi-line string.
+	//
+	// ```yaml
+	// annotations: |
+	//   "sample/annotation1": "foo"
+	//   "sample/annotation2": "bar"
+	// ```
+	//
+	// Default: null
+	Annotations *string `yaml:"annotations,omitempty"`
+}
+
+// IngressGatewaysDefaultsValues are the values under `ingressGateways.defaults`.
+type IngressGatewaysDefaultsValues struct {
+	// Number of replicas for each ingress gateway defined.
+	//
+	// Default: 2
+	Replicas *int `yaml:"replicas,omitempty"`
+
+	// The service options configure the Service that fronts the gateway Deployment.
+	Service *IngressGatewaysDefaultsServiceValues `yaml:"service,omitempty"`
+
+	ServiceAccount *IngressGatewaysDefaultsServiceAccountValues `yaml:"serviceAccount,omitempty"`
+
+	// Resource limits for all ingress gateway pods
+	Resources map[string]interface{} `yaml:"resources,omitempty"`
+
+	// Resource settings for the `copy-consul-bin` init container.
+	InitCopyConsulContainer map[string]interface{} `yaml:"initCopyConsulContainer,omitempty"`
+
+	// By default, we set an anti-affinity so that two of the same gateway pods
+	// won't be on the same node. NOTE: Gateways require that Consul client agents are
+	// also running on the nodes alongside each gateway pod.
+	Affinity *string `yaml:"affinity,omitempty"`
+
+	// Optional YAML string to specify tolerations.
+	//
+	// Default: null
+	Tolerations *string `yaml:"tolerations,omitempty"`
+
+	// Optional YAML string to specify a nodeSelector config.
+	//
+	// Default: null
+	NodeSelector *string `yaml:"nodeSelector,omitempty"`
+
+	// Optional priorityClassName.
+	//
+	// Default: ""
+	PriorityClassName *string `yaml:"priorityClassName,omitempty"`
+
+	// Annotations to apply to the ingress gateway deployment. Annotations defined
+	// here will be applied to all ingress gateway deployments in addition to any
+	// annotations defined for a specific gateway in `ingressGateways.gateways`.
+	//
+	// Example:
+	//
+	// ```yaml
+	// annotations: |
+	//   "annotation-key": 'annotation-value'
+	// ```
+	//
+	// Default: null
+	Annotations *string `yaml:"annotations,omitempty"`
+
+	// `consulNamespace` defines the Consul namespace to register
+	// the gateway into. Requires `global.enableConsulNamespaces` to be true and
+	// Consul Enterprise v1.7+ with a valid Consul Enterprise license.
+	// Note: The Consul namespace MUST exist before the gateway is deployed.
+	//
+	// Default: default
+	ConsulNamespace *string `yaml:"consulNamespace,omitempty"`
+}
+
+// IngressGatewaysGatewaysItem is an element of `ingressGateways.gateways`.
+type IngressGatewaysGatewaysItem struct {
+	// Default: ingress-gateway
+	Name *string `yaml:"name,omitempty"`
+}
+
+// TerminatingGatewaysDefaultsValues are the values under `terminatingGateways.defaults`.
+type TerminatingGatewaysDefaultsValues struct {
+	// Number of replicas for each terminating gateway defined.
+	//
+	// Default: 2
+	Replicas *int `yaml:"replicas,omitempty"`
+
+	// A list of extra volumes to mount. These will be exposed to Consul in the path `/consul/userconfig/<name>/`.
+	//
+	// Example:
+	//
+	// ```yaml
+	// extraVolumes:
+	//   - type: secret
+	//     name: my-secret
+	//     items: # optional items array
+	//       - key: key
+	//         path: path # secret will now mount to /consul/userconfig/my-secret/path
+	// ```
+	ExtraVolumes []TerminatingGatewaysDefaultsExtraVolumesItem `yaml:"extraVolumes,omitempty"`
+
+	// Resource limits for all terminating gateway pods
+	Resources map[string]interface{} `yaml:"resources,omitempty"`
+
+	// Resource settings for the `copy-consul-bin` init container.
+	InitCopyConsulContainer map[string]interface{} `yaml:"initCopyConsulContainer,omitempty"`
+
+	// By default, we set an anti-affinity so that two of the same gateway pods
+	// won't be on the same node. NOTE: Gateways require that Consul client agents are
+	// also running on the nodes alongside each gateway pod.
+	Affinity *string `yaml:"affinity,omitempty"`
+
+	// Optional YAML string to specify tolerations.
+	//
+	// Default: null
+	Tolerations *string `yaml:"tolerations,omitempty"`
+
+	// Optional YAML string to specify a nodeSelector config.
+	//
+	// Default: null
+	NodeSelector *string `yaml:"nodeSelector,omitempty"`
+
+	// Optional priorityClassName.
+	//
+	// Default: ""
+	PriorityClassName *string `yaml:"priorityClassName,omitempty"`
+
+	// Annotations to apply to the terminating gateway deployment. Annotations defined
+	// here will be applied to all terminating gateway deployments in addition to any
+	// annotations defined for a specific gateway in `terminatingGateways.gateways`.
+	//
+	// Example:
+	//
+	// ```yaml
+	// annotations: |
+	//   'annotation-key': annotation-value
+	// ```
+	//
+	// Default: null
+	Annotations *string `yaml:"annotations,omitempty"`
+
+	ServiceAccount *TerminatingGatewaysDefaultsServiceAccountValues `yaml:"serviceAccount,omitempty"`
+
+	// `consulNamespace` defines the Consul namespace to register
+	// the gateway into. Requires `global.enableConsulNamespaces` to be true and
+	// Consul Enterprise v1.7+ with a valid Consul Enterprise license.
+	// Note: The Consul namespace MUST exist before the gateway is deployed.
+	//
+	// Default: default
+	ConsulNamespace *string `yaml:"consulNamespace,omitempty"`
+}
+
+// TerminatingGatewaysGatewaysItem is an element of `terminatingGateways.gateways`.
+type TerminatingGatewaysGatewaysItem struct {
+	// Default: terminating-gateway
+	Name *string `yaml:"name,omitempty"`
+}
+
+// GlobalTLSCaCertValues are the values under `global.tls.caCert`.
+type GlobalTLSCaCertValues struct {
+	// The name of the Kubernetes secret.
+	//
+	// Default: null
+	SecretName *string `yaml:"secretName,omitempty"`
+
+	// The key of the Kubernetes secret.
+	//
+	// Default: null
+	SecretKey *string `yaml:"secretKey,omitempty"`
+}
+
+// GlobalTLSCaKeyValues are the values under `global.tls.caKey`.
+type GlobalTLSCaKeyValues struct {
+	// The name of the Kubernetes secret.
+	//
+	// Default: null
+	SecretName *string `yaml:"secretName,omitempty"`
+
+	// The key of the Kubernetes secret.
+	//
+	// Default: null
+	SecretKey *string `yaml:"secretKey,omitempty"`
+}
+
+// GlobalACLsBootstrapTokenValues are the values under `global.acls.bootstrapToken`.
+type GlobalACLsBootstrapTokenValues struct {
+	// The name of the Kubernetes secret.
+	//
+	// Default: null
+	SecretName *string `yaml:"secretName,omitempty"`
+
+	// The key of the Kubernetes secret.
+	//
+	// Default: null
+	SecretKey *string `yaml:"secretKey,omitempty"`
+}
+
+// GlobalACLsReplicationTokenValues are the values under `global.acls.replicationToken`.
+type GlobalACLsReplicationTokenValues struct {
+	// The name of the Kubernetes secret.
+	//
+	// Default: null
+	SecretName *string `yaml:"secretName,omitempty"`
+
+	// The key of the Kubernetes secret.
+	//
+	// Default: null
+	SecretKey *string `yaml:"secretKey,omitempty"`
+}
+
+// ServerPortsSerflanValues are the values under `server.ports.serflan`.
+type ServerPortsSerflanValues struct {
+	// Default: 8301
+	Port *int `yaml:"port,omitempty"`
+}
+
+// ServerExtraVolumesItemsItem is an element of `server.extraVolumes.items`.
+type ServerExtraVolumesItemsItem struct {
+	// Key in the configMap or secret.
+	//
+	// Required.
+	Key *string `yaml:"key,omitempty"`
+
+	// Path to mount the key to, relative to `/consul/userconfig/<name>`.
+	//
+	// Required.
+	Path *string `yaml:"path,omitempty"`
+}
+
+// ClientExtraVolumesItemsItem is an element of `client.extraVolumes.items`.
+type ClientExtraVolumesItemsItem struct {
+	// Key in the configMap or secret.
+	//
+	// Required.
+	Key *string `yaml:"key,omitempty"`
+
+	// Path to mount the key to, relative to `/consul/userconfig/<name>`.
+	//
+	// Required.
+	Path *string `yaml:"path,omitempty"`
+}
+
+// ClientSnapshotAgentConfigSecretValues are the values under `client.snapshotAgent.configSecret`.
+type ClientSnapshotAgentConfigSecretValues struct {
+	// The name of the Kubernetes secret.
+	//
+	// Default: null
+	SecretName *string `yaml:"secretName,omitempty"`
+
+	// The key of the Kubernetes secret.
+	//
+	// Default: null
+	SecretKey *string `yaml:"secretKey,omitempty"`
+}
+
+// ClientSnapshotAgentServiceAccountValues are the values under `client.snapshotAgent.serviceAccount`.
+type ClientSnapshotAgentServiceAccountValues struct {
+	// This value defines additional annotations for the snapshot agent service account. This should be formatted as a
+	// multi-line string.
+	//
+	// ```yaml
+	// annotations: |
+	//   "sample/annotation1": "foo"
+	//   "sample/annotation2": "bar"
+	// ```
+	//
+	// Default: null
+	Annotations *string `yaml:"annotations,omitempty"`
+}
+
+// UIServiceNodePortValues are the values under `ui.service.nodePort`.
+type UIServiceNodePortValues struct {
+	// HTTP node port
+	//
+	// Default: null
+	HTTP *int `yaml:"http,omitempty"`
+
+	// HTTPS node port
+	//
+	// Default: null
+	HTTPS *int `yaml:"https,omitempty"`
+}
+
+// IngressGatewaysDefaultsServiceValues are the values under `ingressGateways.defaults.service`.
+type IngressGatewaysDefaultsServiceValues struct {
+	// Type of service: LoadBalancer, ClusterIP or NodePort. If using NodePort service
+	// type, you must set the desired nodePorts in the `ports` setting below.
+	//
+	// Default: ClusterIP
+	Type *string `yaml:"type,omitempty"`
+
+	// Ports that will be exposed on the service and gateway container. Any
+	// ports defined as ingress listeners on the gateway's Consul configuration
+	// entry should be included here. The first port will be used as part of
+	// the Consul service registration for the gateway and be listed in its
+	// SRV record. If using a NodePort service type, you must specify the
+	// desired nodePort for each exposed port.
+	//
+	// Default: [{port: 8080, port: 8443}]
+	Ports []map[string]interface{} `yaml:"ports,omitempty"`
+
+	// Annotations to apply to the ingress gateway service. Annotations defined
+	// here will be applied to all ingress gateway services in addition to any
+	// service annotations defined for a specific gateway in `ingressGateways.gateways`.
+	//
+	// Example:
+	//
+	// ```yaml
+	// annotations: |
+	//   'annotation-key': annotation-value
+	// ```
+	//
+	// Default: null
+	Annotations *string `yaml:"annotations,omitempty"`
+
+	// Optional YAML string that will be appended to the Service spec.
+	//
+	// Default: null
+	AdditionalSpec *string `yaml:"additionalSpec,omitempty"`
+}
+
+// IngressGatewaysDefaultsServiceAccountValues are the values under `ingressGateways.defaults.serviceAccount`.
+type IngressGatewaysDefaultsServiceAccountValues struct {
+	// This value defines additional annotations for the ingress gateways' service account. This should be formatted
+	// as a multi-line string.
+	//
+	// ```yaml
+	// annotations: |
+	//   "sample/annotation1": "foo"
+	//   "sample/annotation2": "bar"
+	// ```
+	//
+	// Default: null
+	Annotations *string `yaml:"annotations,omitempty"`
+}
+
+// TerminatingGatewaysDefaultsExtraVolumesItem is an element of `terminatingGateways.defaults.extraVolumes`.
+type TerminatingGatewaysDefaultsExtraVolumesItem struct {
+	// Type of the volume. Case sensitive.
+	//
+	// Required.
+	Type *string `yaml:"type,omitempty"`
+
+	// Name of the configMap or secret to be mounted. This also controls
+	// the path that it is mounted to. The volume will be mounted to `/consul/userconfig/<name>`.
+	//
+	// Required.
+	Name *string `yaml:"name,omitempty"`
+
+	// The keys of the configMap or secret to mount and the paths to mount
+	// them to. If not set, all keys are mounted.
+	Items []TerminatingGatewaysDefaultsExtraVolumesItemsItem `yaml:"items,omitempty"`
+}
+
+// TerminatingGatewaysDefaultsServiceAccountValues are the values under `terminatingGateways.defaults.serviceAccount`.
+type TerminatingGatewaysDefaultsServiceAccountValues struct {
+	// This value defines additional annotations for the terminating gateways' service account. This should be
+	// formatted as a multi-line string.
+	//
+	// ```yaml
+	// annotations: |
+	//   "sample/annotation1": "foo"
+	//   "sample/annotation2": "bar"
+	// ```
+	//
+	// Default: null
+	Annotations *string `yaml:"annotations,omitempty"`
+}
+
+// TerminatingGatewaysDefaultsExtraVolumesItemsItem is an element of `terminatingGateways.defaults.extraVolumes.items`.
+type TerminatingGatewaysDefaultsExtraVolumesItemsItem struct {
+	// Key in the configMap or secret.
+	//
+	// Required.
+	Key *string `yaml:"key,omitempty"`
+
+	// Path to mount the key to, relative to `/consul/userconfig/<name>`.
+	//
+	// Required.
+	Path *string `yaml:"path,omitempty"`
+}
@@ -0,0 +1,145 @@
+// Package kind creates and deletes local kind (https://kind.sigs.k8s.io)
+// clusters for the test suite to run against.
+package kind
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Options are the options for creating kind clusters.
+type Options struct {
+	// Count is the number of clusters to create.
+	Count int
+
+	// NamePrefix is the prefix of the clusters' names. The clusters are
+	// named <prefix>-dc1, <prefix>-dc2 and so on.
+	NamePrefix string
+
+	// NodeImage is the kind node image, e.g. kindest/node:v1.21.1. If it's
+	// blank, kind's default is used. The image isn't pulled if it's already
+	// present locally.
+	NodeImage string
+
+	// Images are the images to load into every cluster, e.g. the Consul and
+	// consul-k8s images. Images that are present locally aren't pulled.
+	Images []string
+
+	// KubeconfigDir is the directory the clusters' kubeconfig files are
+	// written to.
+	KubeconfigDir string
+}
+
+// Cluster is a kind cluster.
+type Cluster struct {
+	Name        string
+	Kubeconfig  string
+	KubeContext string
+}
+
+// runCommand runs the command name with args and stdin and returns its
+// combined output. It's a variable so that the tests can fake it.
+var runCommand = func(stdin string, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	if err != nil {
+		return out.String(), fmt.Errorf("%s %s failed: %s: %s", name, strings.Join(args, " "), err, out.String())
+	}
+	return out.String(), nil
+}
+
+// CreateClusters creates opts.Count kind clusters and loads opts.Images into
+// them. It fails without creating any clusters if a cluster with one of their
+// names already exists, e.g. because a previous run didn't clean up, so that
+// it never deletes a cluster it didn't create. If creating a cluster fails,
+// the clusters created so far are returned along with the error so they can
+// be deleted.
+func CreateClusters(opts Options) ([]Cluster, error) {
+	output, err := runCommand("", "kind", "get", "clusters")
+	if err != nil {
+		return nil, err
+	}
+	existing := strings.Fields(output)
+
+	var names, conflicts []string
+	for i := 1; i <= opts.Count; i++ {
+		name := fmt.Sprintf("%s-dc%d", opts.NamePrefix, i)
+		names = append(names, name)
+		if contains(existing, name) {
+			conflicts = append(conflicts, name)
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, fmt.Errorf("kind clusters %s already exist, delete them with `kind delete cluster --name <name>` and try again", strings.Join(conflicts, ", "))
+	}
+
+	for _, image := range opts.Images {
+		if err := pullIfMissing(image); err != nil {
+			return nil, err
+		}
+	}
+
+	var clusters []Cluster
+	for _, name := range names {
+		cluster := Cluster{
+			Name:        name,
+			Kubeconfig:  filepath.Join(opts.KubeconfigDir, name+".kubeconfig"),
+			KubeContext: "kind-" + name,
+		}
+		args := []string{"create", "cluster", "--name", name, "--kubeconfig", cluster.Kubeconfig, "--wait", "5m"}
+		if opts.NodeImage != "" {
+			args = append(args, "--image", opts.NodeImage)
+		}
+		if _, err := runCommand("", "kind", args...); err != nil {
+			return clusters, err
+		}
+		clusters = append(clusters, cluster)
+
+		for _, image := range opts.Images {
+			if _, err := runCommand("", "kind", "load", "docker-image", image, "--name", name); err != nil {
+				return clusters, err
+			}
+		}
+	}
+	return clusters, nil
+}
+
+// DeleteClusters deletes the clusters created by CreateClusters.
+func DeleteClusters(clusters []Cluster) error {
+	var errs []string
+	for _, cluster := range clusters {
+		if _, err := runCommand("", "kind", "delete", "cluster", "--name", cluster.Name, "--kubeconfig", cluster.Kubeconfig); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to delete kind clusters:\n%s", strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// pullIfMissing pulls image unless it's already present locally.
+func pullIfMissing(image string) error {
+	if _, err := runCommand("", "docker", "image", "inspect", image); err == nil {
+		return nil
+	}
+	_, err := runCommand("", "docker", "pull", image)
+	return err
+}
+
+// contains returns true if s is in list.
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package kind
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCommands replaces runCommand with a fake that records the commands it's
+// called with. Commands that start with one of the prefixes in fail fail.
+func fakeCommands(t *testing.T, existingClusters string, fail ...string) *[]string {
+	var commands []string
+	original := runCommand
+	t.Cleanup(func() { runCommand = original })
+	runCommand = func(stdin string, name string, args ...string) (string, error) {
+		command := strings.Join(append([]string{name}, args...), " ")
+		commands = append(commands, command)
+		for _, prefix := range fail {
+			if strings.HasPrefix(command, prefix) {
+				return "", errors.New("failed")
+			}
+		}
+		if command == "kind get clusters" {
+			return existingClusters, nil
+		}
+		return "", nil
+	}
+	return &commands
+}
+
+func TestCreateClusters(t *testing.T) {
+	commands := fakeCommands(t, "other\n", "docker image inspect consul-k8s:dev")
+
+	clusters, err := CreateClusters(Options{
+		Count:         2,
+		NamePrefix:    "consul",
+		NodeImage:     "kindest/node:v1.21.1",
+		Images:        []string{"consul:dev", "consul-k8s:dev"},
+		KubeconfigDir: "/tmp/kind",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Cluster{
+		{Name: "consul-dc1", Kubeconfig: "/tmp/kind/consul-dc1.kubeconfig", KubeContext: "kind-consul-dc1"},
+		{Name: "consul-dc2", Kubeconfig: "/tmp/kind/consul-dc2.kubeconfig", KubeContext: "kind-consul-dc2"},
+	}, clusters)
+	require.Equal(t, []string{
+		"kind get clusters",
+		// Only images that aren't present locally are pulled.
+		"docker image inspect consul:dev",
+		"docker image inspect consul-k8s:dev",
+		"docker pull consul-k8s:dev",
+		"kind create cluster --name consul-dc1 --kubeconfig /tmp/kind/consul-dc1.kubeconfig --wait 5m --image kindest/node:v1.21.1",
+		"kind load docker-image consul:dev --name consul-dc1",
+		"kind load docker-image consul-k8s:dev --name consul-dc1",
+		"kind create cluster --name consul-dc2 --kubeconfig /tmp/kind/consul-dc2.kubeconfig --wait 5m --image kindest/node:v1.21.1",
+		"kind load docker-image consul:dev --name consul-dc2",
+		"kind load docker-image consul-k8s:dev --name consul-dc2",
+	}, *commands)
+}
+
+func TestCreateClusters_FailsIfClustersExist(t *testing.T) {
+	commands := fakeCommands(t, "consul-dc1\nother\nconsul-dc3\n")
+
+	clusters, err := CreateClusters(Options{Count: 3, NamePrefix: "consul", KubeconfigDir: "/tmp/kind"})
+	require.EqualError(t, err, "kind clusters consul-dc1, consul-dc3 already exist, delete them with `kind delete cluster --name <name>` and try again")
+	require.Empty(t, clusters)
+	// Nothing is created or deleted.
+	require.Equal(t, []string{"kind get clusters"}, *commands)
+}
+
+func TestCreateClusters_ReturnsCreatedClustersOnError(t *testing.T) {
+	fakeCommands(t, "", "kind create cluster --name consul-dc2")
+
+	clusters, err := CreateClusters(Options{Count: 3, NamePrefix: "consul", KubeconfigDir: "/tmp/kind"})
+	require.EqualError(t, err, "failed")
+	require.Len(t, clusters, 1)
+	require.Equal(t, "consul-dc1", clusters[0].Name)
+}
+
+func TestDeleteClusters(t *testing.T) {
+	commands := fakeCommands(t, "", "kind delete cluster --name consul-dc2")
+
+	err := DeleteClusters([]Cluster{
+		{Name: "consul-dc1", Kubeconfig: "/tmp/kind/consul-dc1.kubeconfig"},
+		{Name: "consul-dc2", Kubeconfig: "/tmp/kind/consul-dc2.kubeconfig"},
+		{Name: "consul-dc3", Kubeconfig: "/tmp/kind/consul-dc3.kubeconfig"},
+	})
+	// A failure doesn't stop the other clusters from being deleted.
+	require.EqualError(t, err, "failed to delete kind clusters:\nfailed")
+	require.Equal(t, []string{
+		"kind delete cluster --name consul-dc1 --kubeconfig /tmp/kind/consul-dc1.kubeconfig",
+		"kind delete cluster --name consul-dc2 --kubeconfig /tmp/kind/consul-dc2.kubeconfig",
+		"kind delete cluster --name consul-dc3 --kubeconfig /tmp/kind/consul-dc3.kubeconfig",
+	}, *commands)
+}
@@ -4,13 +4,20 @@ import (
 	"flag"
 	"fmt"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/hashicorp/consul-helm/test/acceptance/framework/config"
 	"github.com/hashicorp/consul-helm/test/acceptance/framework/environment"
 	"github.com/hashicorp/consul-helm/test/acceptance/framework/flags"
+	"github.com/hashicorp/consul-helm/test/acceptance/framework/kind"
 )
 
+// kindNamePrefix is the prefix of the names of the kind clusters the suite
+// creates.
+const kindNamePrefix = "consul-acceptance"
+
 type suite struct {
 	m     *testing.M
 	env   *environment.KubernetesEnvironment
@@ -39,7 +46,7 @@ func NewSuite(m *testing.M) Suite {
 	}
 }
 
-func (s *suite) Run() int {
+func (s *suite) Run() (exitCode int) {
 	err := s.flags.Validate()
 	if err != nil {
 		fmt.Printf("Flag validation failed: %s\n", err)
@@ -56,9 +63,84 @@ func (s *suite) Run() int {
 		}
 	}
 
+	if s.cfg.KindClusters > 0 {
+		kubeconfigDir := filepath.Join(s.cfg.DebugDirectory, "kind")
+		clusters, err := s.createKindClusters(kubeconfigDir)
+		defer func() {
+			if exitCode != 0 && s.cfg.NoCleanupOnFailure {
+				fmt.Printf("Not deleting the kind clusters because -no-cleanup-on-failure is set. Their kubeconfigs are in %s\n", kubeconfigDir)
+				return
+			}
+			if err := kind.DeleteClusters(clusters); err != nil {
+				fmt.Println(err)
+			}
+		}()
+		if err != nil {
+			fmt.Printf("Failed to create kind clusters: %s\n", err)
+			return 1
+		}
+	}
+
 	return s.m.Run()
 }
 
+// createKindClusters creates the kind clusters, writing their kubeconfigs to
+// kubeconfigDir, and points the contexts of the environment at them. The
+// clusters created are returned even if there's an error so they can be
+// deleted.
+func (s *suite) createKindClusters(kubeconfigDir string) ([]kind.Cluster, error) {
+	if err := os.MkdirAll(kubeconfigDir, 0755); err != nil {
+		return nil, err
+	}
+
+	var images []string
+	for _, image := range []string{s.cfg.ConsulImage, s.cfg.ConsulK8SImage} {
+		if image != "" {
+			images = append(images, image)
+		}
+	}
+	clusters, err := kind.CreateClusters(kind.Options{
+		Count:         s.cfg.KindClusters,
+		NamePrefix:    kindNamePrefix,
+		NodeImage:     s.cfg.KindNodeImage,
+		Images:        images,
+		KubeconfigDir: kubeconfigDir,
+	})
+	if err != nil {
+		return clusters, err
+	}
+
+	s.cfg.Contexts = kindContexts(clusters, s.cfg)
+	s.env = environment.NewKubernetesEnvironmentFromConfig(s.cfg)
+	return clusters, nil
+}
+
+// kindContexts returns the contexts of the kind clusters. The first two are
+// the default and secondary contexts and keep the namespaces of cfg's default
+// and secondary contexts. The rest are named dc3, dc4 and so on and use the
+// default context's namespace.
+func kindContexts(clusters []kind.Cluster, cfg *config.TestConfig) []config.KubeContextConfig {
+	var contexts []config.KubeContextConfig
+	for i, cluster := range clusters {
+		name := fmt.Sprintf("dc%d", i+1)
+		namespace := cfg.KubeNamespace
+		switch i {
+		case 0:
+			name = environment.DefaultContextName
+		case 1:
+			name = environment.SecondaryContextName
+			namespace = cfg.SecondaryKubeNamespace
+		}
+		contexts = append(contexts, config.KubeContextConfig{
+			Name:          name,
+			Kubeconfig:    cluster.Kubeconfig,
+			KubeContext:   cluster.KubeContext,
+			KubeNamespace: namespace,
+		})
+	}
+	return contexts
+}
+
 func (s *suite) Environment() environment.TestEnvironment {
 	return s.env
 }
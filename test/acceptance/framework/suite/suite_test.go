@@ -0,0 +1,24 @@
+package suite
+
+import (
+	"testing"
+
+	"github.com/hashicorp/consul-helm/test/acceptance/framework/config"
+	"github.com/hashicorp/consul-helm/test/acceptance/framework/kind"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKindContexts(t *testing.T) {
+	clusters := []kind.Cluster{
+		{Name: "consul-acceptance-dc1", Kubeconfig: "/tmp/kind/dc1.kubeconfig", KubeContext: "kind-consul-acceptance-dc1"},
+		{Name: "consul-acceptance-dc2", Kubeconfig: "/tmp/kind/dc2.kubeconfig", KubeContext: "kind-consul-acceptance-dc2"},
+		{Name: "consul-acceptance-dc3", Kubeconfig: "/tmp/kind/dc3.kubeconfig", KubeContext: "kind-consul-acceptance-dc3"},
+	}
+	cfg := &config.TestConfig{KubeNamespace: "consul", SecondaryKubeNamespace: "consul-secondary"}
+
+	require.Equal(t, []config.KubeContextConfig{
+		{Name: "default", Kubeconfig: "/tmp/kind/dc1.kubeconfig", KubeContext: "kind-consul-acceptance-dc1", KubeNamespace: "consul"},
+		{Name: "secondary", Kubeconfig: "/tmp/kind/dc2.kubeconfig", KubeContext: "kind-consul-acceptance-dc2", KubeNamespace: "consul-secondary"},
+		{Name: "dc3", Kubeconfig: "/tmp/kind/dc3.kubeconfig", KubeContext: "kind-consul-acceptance-dc3", KubeNamespace: "consul"},
+	}, kindContexts(clusters, cfg))
+}
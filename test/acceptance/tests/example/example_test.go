@@ -21,7 +21,7 @@ func TestExample(t *testing.T) {
 
 	// Create Helm values for the Helm install.
 	helmValues := map[string]string{
-		"exampleFeature.enabled": "true",
+		"connectInject.enabled": "true",
 	}
 
 	// Generate a random name for this test.
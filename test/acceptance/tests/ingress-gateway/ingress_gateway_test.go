@@ -45,7 +45,7 @@ func TestIngressGateway(t *testing.T) {
 
 				"global.acls.manageSystemACLs": strconv.FormatBool(c.secure),
 				"global.tls.enabled":           strconv.FormatBool(c.secure),
-				"global.tls.autoEncrypt":       strconv.FormatBool(c.autoEncrypt),
+				"global.tls.enableAutoEncrypt": strconv.FormatBool(c.autoEncrypt),
 			}
 
 			releaseName := helpers.RandomName()
@@ -52,8 +52,12 @@ func TestSyncCatalog(t *testing.T) {
 	}
 
 	for _, c := range cases {
+		c := c
 		t.Run(c.name, func(t *testing.T) {
-			ctx := suite.Environment().DefaultContext(t)
+			// Each case installs Consul into a namespace of its own so the
+			// cases can run in parallel.
+			ctx := suite.Environment().DefaultContext(t).WithNewNamespace(t)
+			t.Parallel()
 
 			releaseName := helpers.RandomName()
 			consulCluster := consul.NewHelmCluster(t, c.helmValues, ctx, suite.Config(), releaseName)
@@ -51,7 +51,7 @@ func TestTerminatingGateway(t *testing.T) {
 
 				"global.acls.manageSystemACLs": strconv.FormatBool(c.secure),
 				"global.tls.enabled":           strconv.FormatBool(c.secure),
-				"global.tls.autoEncrypt":       strconv.FormatBool(c.autoEncrypt),
+				"global.tls.enableAutoEncrypt": strconv.FormatBool(c.autoEncrypt),
 			}
 
 			logger.Log(t, "creating consul cluster")
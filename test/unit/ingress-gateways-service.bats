@@ -188,7 +188,7 @@ key2: value2' \
       -s templates/ingress-gateways-service.yaml  \
       --set 'ingressGateways.enabled=true' \
       --set 'connectInject.enabled=true' \
-      --set 'ingressGateways.defaults.service.port=8443' \
+      --set 'ingressGateways.defaults.service.ports[0].port=8443' \
       --set 'ingressGateways.gateways[0].name=gateway1' \
       --set 'ingressGateways.gateways[0].service.ports[0].port=1234' \
       . | tee /dev/stderr |
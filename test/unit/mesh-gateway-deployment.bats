@@ -1199,7 +1199,7 @@ EOF
       --set 'meshGateway.enabled=true' \
       --set 'connectInject.enabled=true' \
       --set 'meshGateway.wanAddress.source=Service' \
-      --set 'meshGateway.wanAddress.port=ignored' \
+      --set 'meshGateway.wanAddress.port=1234' \
       --set 'meshGateway.service.enabled=true' \
       --set 'meshGateway.service.type=LoadBalancer' \
       . | tee /dev/stderr |
@@ -1318,7 +1318,7 @@ EOF
       --set 'meshGateway.enabled=true' \
       --set 'connectInject.enabled=true' \
       --set 'meshGateway.wanAddress.source=Service' \
-      --set 'meshGateway.wanAddress.port=ignored' \
+      --set 'meshGateway.wanAddress.port=1234' \
       --set 'meshGateway.service.enabled=true' \
       --set 'meshGateway.service.type=ClusterIP' \
       . | tee /dev/stderr |
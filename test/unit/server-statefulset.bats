@@ -98,6 +98,17 @@ load _helpers
   [ "${actual}" = "bar" ]
 }
 
+@test "server/StatefulSet: resources can be set to numbers" {
+  cd `chart_dir`
+  local actual=$(helm template \
+      -s templates/server-statefulset.yaml  \
+      --set 'server.resources.requests.cpu=1' \
+      --set 'server.resources.limits.memory=1073741824' \
+      . | tee /dev/stderr |
+      yq -rc '.spec.template.spec.containers[0].resources' | tee /dev/stderr)
+  [ "${actual}" = '{"limits":{"cpu":"100m","memory":1073741824},"requests":{"cpu":1,"memory":"100Mi"}}' ]
+}
+
 # Test support for the deprecated method of setting a YAML string.
 @test "server/StatefulSet: resources can be overridden with string" {
   cd `chart_dir`